@@ -0,0 +1,108 @@
+// Command backfill_forms fills entry_forms for the Chechen headwords already in
+// dictionary_pairs.
+//
+// The bot learns a paradigm every time it fetches grammar for a card, so the
+// index grows on its own — but only for words somebody looked up, and an
+// inflected form is only resolvable once its headword has been. This walks the
+// stored headwords in id order and asks dosham for each one's forms.
+//
+//	go run ./cmd/backfill_forms -db database.db [-from 0] [-limit 1000] [-delay 300ms]
+//
+// Progress prints the id reached; pass it back as -from to resume.
+package main
+
+import (
+	"chetoru/internal/business"
+	"chetoru/internal/repository"
+	"chetoru/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	_ "modernc.org/sqlite"
+)
+
+// pageSize is how many headwords one repository call returns.
+const pageSize = 200
+
+func main() {
+	dbPath := flag.String("db", "./database.db", "path to the SQLite database")
+	from := flag.Int64("from", 0, "start after this dictionary_pairs id")
+	limit := flag.Int("limit", 0, "stop after this many headwords (0 = all)")
+	delay := flag.Duration("delay", 300*time.Millisecond, "pause between requests; dosham is a non-commercial service")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	db, err := sql.Open("sqlite", "file:"+*dbPath+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "open db:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := migrations.Up(db); err != nil {
+		fmt.Fprintln(os.Stderr, "migrations:", err)
+		os.Exit(1)
+	}
+
+	log := logrus.New()
+	log.SetLevel(logrus.WarnLevel)
+	repo := repository.NewRepository(db)
+	b := business.NewBusiness(nil, repo, business.NewDoshamClient(), nil, log)
+
+	started := time.Now()
+	cursor := *from
+	words, forms, failed := 0, 0, 0
+	report := func(prefix string) {
+		fmt.Printf("%s заголовков %d (ошибок %d), новых форм %d, последний id %d, %s\n",
+			prefix, words, failed, forms, cursor, time.Since(started).Round(time.Second))
+	}
+
+	err = func() error {
+		for ctx.Err() == nil {
+			page, err := repo.ListChechenHeadwords(ctx, cursor, pageSize)
+			if err != nil {
+				return err
+			}
+			if len(page) == 0 {
+				return nil
+			}
+			for _, h := range page {
+				if ctx.Err() != nil || (*limit > 0 && words >= *limit) {
+					return ctx.Err()
+				}
+				words++
+				n, err := b.IndexEntryForms(ctx, h.Word)
+				switch {
+				case errors.Is(err, business.ErrDoshamUnavailable):
+					return fmt.Errorf("dosham недоступен, остановлено на %q: %w", h.Word, err)
+				case err != nil:
+					failed++
+					fmt.Fprintf(os.Stderr, "  %q: %v\n", h.Word, err)
+				default:
+					forms += n
+				}
+				cursor = h.ID
+				if words%50 == 0 {
+					report("  ")
+				}
+				time.Sleep(*delay)
+			}
+		}
+		return ctx.Err()
+	}()
+	report("итого")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
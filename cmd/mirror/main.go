@@ -0,0 +1,235 @@
+// Command mirror copies the dosham dictionary into dictionary_pairs, so the
+// bot can answer when dosham cannot — during an outage, or with OFFLINE_MODE
+// on.
+//
+// dosham has no "list everything" query, only a substring search, so the
+// crawl sends every two-letter seed of the alphabet and stores whatever comes
+// back. A seed that returns at least -expand entries is extended by a letter
+// and sent again, in case the API truncates long answers. Every seed is
+// recorded in mirror_seeds, so an interrupted run picks up where it stopped.
+//
+//	go run ./cmd/mirror -db database.db [-delay 300ms] [-expand 0] [-recrawl 720h]
+//	go run ./cmd/mirror -db database.db -refresh 720h [-limit 1000]
+//	go run ./cmd/mirror -db database.db -dump entries.jsonl
+//
+// -refresh re-asks dosham about headwords it has not confirmed within the
+// window, oldest first; -dump ingests one dosham entry per line instead of
+// calling the API at all.
+package main
+
+import (
+	"bufio"
+	"chetoru/internal/business"
+	"chetoru/internal/models"
+	"chetoru/internal/repository"
+	"chetoru/migrations"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	_ "modernc.org/sqlite"
+)
+
+// alphabet covers both languages: the Russian letters plus the palochka, the
+// only letter Chechen adds. ё is kept apart from е because dosham's search
+// does not fold them.
+var alphabet = []rune("абвгдеёжзийклмнопрстуфхцчшщъыьэюяӏ")
+
+// maxSeedRunes stops -expand from descending forever on a seed the API keeps
+// answering in full.
+const maxSeedRunes = 4
+
+func main() {
+	dbPath := flag.String("db", "./database.db", "path to the SQLite database")
+	dump := flag.String("dump", "", "ingest dosham entries from this JSON-lines file (- for stdin) instead of crawling")
+	refresh := flag.Duration("refresh", 0, "re-fetch headwords not confirmed within this window instead of crawling")
+	limit := flag.Int("limit", 1000, "how many headwords one -refresh run re-fetches")
+	recrawl := flag.Duration("recrawl", 30*24*time.Hour, "skip seeds crawled within this window")
+	expand := flag.Int("expand", 0, "extend seeds that return at least this many entries (0 = never)")
+	delay := flag.Duration("delay", 300*time.Millisecond, "pause between requests; dosham is a non-commercial service")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	db, err := sql.Open("sqlite", "file:"+*dbPath+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "open db:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	// The mirror may be the first thing to touch a fresh database meant for an
+	// offline deployment, so it brings the schema up itself.
+	if err := migrations.Up(db); err != nil {
+		fmt.Fprintln(os.Stderr, "migrations:", err)
+		os.Exit(1)
+	}
+
+	m := &mirror{repo: repository.NewRepository(db), client: business.NewDoshamClient(), delay: *delay, started: time.Now()}
+	switch {
+	case *dump != "":
+		err = m.ingest(ctx, *dump)
+	case *refresh > 0:
+		err = m.refresh(ctx, *refresh, *limit)
+	default:
+		err = m.crawl(ctx, *recrawl, *expand)
+	}
+	m.report("итого")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+type mirror struct {
+	repo    *repository.Repository
+	client  *business.DoshamClient
+	delay   time.Duration
+	started time.Time
+
+	queries, entries, added, refreshed, failed int
+}
+
+func (m *mirror) crawl(ctx context.Context, recrawl time.Duration, expand int) error {
+	queue := make([]string, 0, len(alphabet)*len(alphabet))
+	for _, a := range alphabet {
+		for _, b := range alphabet {
+			queue = append(queue, string([]rune{a, b}))
+		}
+	}
+
+	for len(queue) > 0 && ctx.Err() == nil {
+		seed := queue[0]
+		queue = queue[1:]
+
+		found, fresh, err := m.repo.MirrorSeed(ctx, seed, recrawl)
+		if err != nil {
+			return fmt.Errorf("seed %q: %w", seed, err)
+		}
+		if !fresh {
+			entries, err := m.fetch(ctx, seed)
+			if err != nil {
+				if errors.Is(err, business.ErrDoshamUnavailable) {
+					return fmt.Errorf("dosham недоступен, остановлено на %q: %w", seed, err)
+				}
+				fmt.Fprintf(os.Stderr, "  %q: %v\n", seed, err)
+				continue
+			}
+			found = len(entries)
+			if err := m.repo.MarkMirrorSeed(ctx, seed, found); err != nil {
+				return fmt.Errorf("seed %q: %w", seed, err)
+			}
+		}
+
+		if expand > 0 && found >= expand && utf8.RuneCountInString(seed) < maxSeedRunes {
+			for _, r := range alphabet {
+				queue = append(queue, seed+string(r))
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+func (m *mirror) refresh(ctx context.Context, maxAge time.Duration, limit int) error {
+	words, err := m.repo.ListStaleHeadwords(ctx, maxAge, limit)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("устаревших заголовков: %d\n", len(words))
+	for _, w := range words {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := m.fetch(ctx, w); err != nil {
+			if errors.Is(err, business.ErrDoshamUnavailable) {
+				return fmt.Errorf("dosham недоступен, остановлено на %q: %w", w, err)
+			}
+			fmt.Fprintf(os.Stderr, "  %q: %v\n", w, err)
+		}
+	}
+	return nil
+}
+
+// fetch runs one search and stores what it returned.
+func (m *mirror) fetch(ctx context.Context, word string) ([]models.Entry, error) {
+	defer time.Sleep(m.delay)
+	m.queries++
+	entries, err := m.client.Find(ctx, word)
+	if err != nil {
+		m.failed++
+		return nil, err
+	}
+	m.store(ctx, entries)
+	if m.queries%50 == 0 {
+		m.report("  ")
+	}
+	return entries, nil
+}
+
+func (m *mirror) ingest(ctx context.Context, path string) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for sc.Scan() && ctx.Err() == nil {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		var entry models.Entry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		m.store(ctx, []models.Entry{entry})
+	}
+	return sc.Err()
+}
+
+// store writes every Russian/Chechen translation of entries through the same
+// builder the bot uses, so a mirrored pair is the row a live lookup writes.
+func (m *mirror) store(ctx context.Context, entries []models.Entry) {
+	m.entries += len(entries)
+	for _, entry := range entries {
+		for _, translation := range entry.Translations {
+			pair, ok := business.NewTranslationPair(entry, translation, "api")
+			if !ok {
+				continue
+			}
+			_, inserted, err := m.repo.RefreshTranslationPair(ctx, pair)
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "  запись %q: %v\n", pair.OriginalRaw, err)
+			case inserted:
+				m.added++
+			default:
+				m.refreshed++
+			}
+		}
+	}
+}
+
+func (m *mirror) report(prefix string) {
+	fmt.Printf("%s запросов %d (ошибок %d), статей %d, новых пар %d, обновлено %d, %s\n",
+		prefix, m.queries, m.failed, m.entries, m.added, m.refreshed, time.Since(m.started).Round(time.Second))
+}
@@ -2,23 +2,232 @@ package business
 
 import (
 	"bytes"
+	"chetoru/internal/models"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// doshamHTTPClient is shared across all dosham API calls so connections are
-// reused and every request is bounded by a timeout. Without a timeout a hung
-// API would block request goroutines indefinitely.
-var doshamHTTPClient = &http.Client{Timeout: 15 * time.Second}
+// ErrDoshamUnavailable is returned without a network call while the circuit
+// breaker is open. Callers treat it like any other dosham failure — it is an
+// outage, not an answer — and it is what lets Translate reach the
+// "Словарь сейчас недоступен" path in microseconds instead of after a timeout.
+var ErrDoshamUnavailable = errors.New("dosham API: circuit open")
 
-// doDoshamQuery runs a GraphQL query against the dosham API and decodes the
-// response into out. It centralizes the endpoint, timeout, and JSON plumbing
-// shared by the translation, /random, and /quiz features.
-func doDoshamQuery(ctx context.Context, query string, variables map[string]any, out any) error {
+const (
+	// doshamTimeout bounds every request. Without a timeout a hung API would
+	// block request goroutines indefinitely.
+	doshamTimeout = 15 * time.Second
+	// doshamMaxInFlight caps concurrent requests across the whole process. One
+	// miss fans out into ~25 queries and eight handlers run at once, so without
+	// a cap a bad minute turns into 200 parallel requests against an API we are
+	// a non-commercial guest of.
+	doshamMaxInFlight = 6
+	// doshamFailureThreshold consecutive failures open the breaker for
+	// doshamCooldown. Five is one failed ё-cascade: enough to tell an outage
+	// from a blip, few enough that the next user is not the one who waits out
+	// fifteen seconds of timeout to hear the dictionary is down.
+	doshamFailureThreshold = 5
+	doshamCooldown         = 30 * time.Second
+)
+
+// DoshamClient is the one door to the dosham GraphQL API. Translation, grammar,
+// /random and /quiz all go through the same instance, so the limits below hold
+// for the process rather than per feature:
+//
+//   - identical in-flight lookups collapse into one request, keyed by the
+//     query name and the normalized word;
+//   - at most maxInFlight requests run at once, the rest wait for a slot;
+//   - after failureThreshold consecutive failures the breaker opens and calls
+//     fail fast with ErrDoshamUnavailable until cooldown passes, when a single
+//     probe decides whether it closes again.
+//
+// The endpoint is read per request from DOSHAM_API_URL, which is how tests
+// point it at a local fake server.
+type DoshamClient struct {
+	http             *http.Client
+	sem              chan struct{}
+	failureThreshold int
+	cooldown         time.Duration
+
+	flightMu sync.Mutex
+	flights  map[string]*doshamFlight
+
+	breakerMu sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+
+	statsMu sync.Mutex
+	stats   map[string]*doshamCounters
+}
+
+// doshamFlight is one in-flight request that identical lookups wait on. The
+// raw body is shared, not a decoded value: each caller decodes into its own
+// out, so nobody hands another goroutine a slice it may go on to mutate.
+type doshamFlight struct {
+	done chan struct{}
+	raw  []byte
+	err  error
+}
+
+type doshamCounters struct {
+	requests, shared, failures, rejected atomic.Int64
+}
+
+// NewDoshamClient returns a client with the production limits.
+func NewDoshamClient() *DoshamClient {
+	return newDoshamClient(doshamMaxInFlight, doshamFailureThreshold, doshamCooldown)
+}
+
+func newDoshamClient(maxInFlight, failureThreshold int, cooldown time.Duration) *DoshamClient {
+	return &DoshamClient{
+		http:             &http.Client{Timeout: doshamTimeout},
+		sem:              make(chan struct{}, maxInFlight),
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		flights:          make(map[string]*doshamFlight),
+		stats:            make(map[string]*doshamCounters),
+	}
+}
+
+// Lookup runs a word-keyed query. A caller asking for a word another caller is
+// already fetching waits for that request instead of sending its own. The key
+// folds case and surrounding space only: ё and the palochka stand-ins are
+// exactly what the ё-fallback varies between requests, so folding them here
+// would answer "берёза" with the result for "береза".
+func (c *DoshamClient) Lookup(ctx context.Context, name, query, word string, out any) error {
+	key := name + "\x00" + strings.ToLower(strings.TrimSpace(word))
+	raw, err := c.shared(ctx, name, key, query, map[string]any{"inputText": word})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// Query runs a query that must not be collapsed — randomEntries answers
+// differently every time, and two callers asking for a batch each want their
+// own.
+func (c *DoshamClient) Query(ctx context.Context, name, query string, variables map[string]any, out any) error {
+	raw, err := c.do(ctx, name, query, variables)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// findQuery is the `find` query behind translation lookups and the mirror
+// crawler: every entry field the card renders from.
+const findQuery = `
+	query Find($inputText: String!) {
+		find(inputText: $inputText) {
+			entryId
+			content
+			type
+			subtype
+			entryIndex
+			notes
+			rate
+			details
+			translations {
+				translationId
+				content
+				languageCode
+				notes
+			}
+		}
+	}
+`
+
+// Find runs dosham's substring search for word and returns the raw entries.
+func (c *DoshamClient) Find(ctx context.Context, word string) ([]models.Entry, error) {
+	var response models.TranslationResponse
+	if err := c.Lookup(ctx, "find", findQuery, word, &response); err != nil {
+		return nil, fmt.Errorf("dosham find %q: %w", word, err)
+	}
+	return response.Data.Find, nil
+}
+
+// shared joins the in-flight request for key, or becomes it. The request runs
+// on the first caller's context, so a leader that gives up fails its
+// followers too; they see an ordinary error, never a false "no results".
+func (c *DoshamClient) shared(ctx context.Context, name, key, query string, variables map[string]any) ([]byte, error) {
+	c.flightMu.Lock()
+	if f, ok := c.flights[key]; ok {
+		c.flightMu.Unlock()
+		c.counters(name).shared.Add(1)
+		select {
+		case <-f.done:
+			return f.raw, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	f := &doshamFlight{done: make(chan struct{})}
+	c.flights[key] = f
+	c.flightMu.Unlock()
+
+	f.raw, f.err = c.do(ctx, name, query, variables)
+
+	c.flightMu.Lock()
+	delete(c.flights, key)
+	c.flightMu.Unlock()
+	close(f.done)
+	return f.raw, f.err
+}
+
+// do sends one request through the breaker and the concurrency cap.
+func (c *DoshamClient) do(ctx context.Context, name, query string, variables map[string]any) ([]byte, error) {
+	counters := c.counters(name)
+	allowed, probe := c.allow()
+	if !allowed {
+		counters.rejected.Add(1)
+		return nil, ErrDoshamUnavailable
+	}
+
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		c.release(probe)
+		return nil, ctx.Err()
+	}
+	defer func() { <-c.sem }()
+
+	counters.requests.Add(1)
+	raw, err := c.post(ctx, query, variables)
+	switch {
+	case err == nil:
+		c.record(true, probe)
+	case errors.Is(err, errGraphQL):
+		// The server answered; it just did not like the question. That is
+		// not an outage, and tripping the breaker on it would take the whole
+		// dictionary down over one malformed query.
+		c.record(true, probe)
+		counters.failures.Add(1)
+	case ctx.Err() != nil:
+		// The caller gave up; dosham has not been shown to be down.
+		c.release(probe)
+		counters.failures.Add(1)
+	default:
+		c.record(false, probe)
+		counters.failures.Add(1)
+	}
+	return raw, err
+}
+
+// errGraphQL marks an error the API reported in a well-formed response.
+var errGraphQL = errors.New("graphql error")
+
+// post is the HTTP and JSON plumbing of a single request.
+func (c *DoshamClient) post(ctx context.Context, query string, variables map[string]any) ([]byte, error) {
 	body := map[string]any{"query": query}
 	if variables != nil {
 		body["variables"] = variables
@@ -26,30 +235,30 @@ func doDoshamQuery(ctx context.Context, query string, variables map[string]any,
 
 	data, err := json.Marshal(body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", doshamAPIURL(), bytes.NewBuffer(data))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := doshamHTTPClient.Do(req)
+	resp, err := c.http.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("dosham API: status %d", resp.StatusCode)
+		return nil, fmt.Errorf("dosham API: status %d", resp.StatusCode)
 	}
 
 	// A failed GraphQL query still decodes cleanly into an empty result, which
 	// callers would mistake for a real "no results" answer (and cache it).
 	raw, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	var gqlErrs struct {
 		Errors []struct {
@@ -57,11 +266,92 @@ func doDoshamQuery(ctx context.Context, query string, variables map[string]any,
 		} `json:"errors"`
 	}
 	if err := json.Unmarshal(raw, &gqlErrs); err != nil {
-		return err
+		return nil, err
 	}
 	if len(gqlErrs.Errors) > 0 {
-		return fmt.Errorf("dosham API: %s", gqlErrs.Errors[0].Message)
+		return nil, fmt.Errorf("dosham API: %s: %w", gqlErrs.Errors[0].Message, errGraphQL)
 	}
+	return raw, nil
+}
 
-	return json.Unmarshal(raw, out)
+// allow reports whether a request may go out. While open it refuses
+// everything; once the cooldown has passed it lets exactly one probe through
+// and keeps refusing the rest until that probe reports back. probe reports
+// whether this request is that probe, the only one that may end probing.
+func (c *DoshamClient) allow() (allowed, probe bool) {
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+	if c.failures < c.failureThreshold {
+		return true, false
+	}
+	if time.Now().Before(c.openUntil) || c.probing {
+		return false, false
+	}
+	c.probing = true
+	return true, true
+}
+
+// record feeds a request's outcome into the breaker. A request allowed before
+// the breaker opened still counts, but only the probe ends the half-open
+// state: a straggler finishing mid-probe must not let a second probe out.
+func (c *DoshamClient) record(ok, probe bool) {
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+	if probe {
+		c.probing = false
+	}
+	if ok {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.failures >= c.failureThreshold {
+		c.openUntil = time.Now().Add(c.cooldown)
+	}
+}
+
+// release returns an allowed slot that never produced a verdict, so a probe
+// cancelled by its caller does not wedge the breaker half-open.
+func (c *DoshamClient) release(probe bool) {
+	if !probe {
+		return
+	}
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+	c.probing = false
+}
+
+func (c *DoshamClient) counters(name string) *doshamCounters {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	s, ok := c.stats[name]
+	if !ok {
+		s = &doshamCounters{}
+		c.stats[name] = s
+	}
+	return s
+}
+
+// Stats returns per-query counters since process start and whether the
+// breaker is refusing requests right now.
+func (c *DoshamClient) Stats() models.DoshamStats {
+	c.breakerMu.Lock()
+	open := c.failures >= c.failureThreshold && time.Now().Before(c.openUntil)
+	c.breakerMu.Unlock()
+
+	c.statsMu.Lock()
+	out := models.DoshamStats{BreakerOpen: open, Queries: make([]models.DoshamQueryStats, 0, len(c.stats))}
+	for name, s := range c.stats {
+		out.Queries = append(out.Queries, models.DoshamQueryStats{
+			Query:    name,
+			Requests: s.requests.Load(),
+			Shared:   s.shared.Load(),
+			Failures: s.failures.Load(),
+			Rejected: s.rejected.Load(),
+		})
+	}
+	c.statsMu.Unlock()
+
+	sort.Slice(out.Queries, func(i, j int) bool { return out.Queries[i].Query < out.Queries[j].Query })
+	return out
 }
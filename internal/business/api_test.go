@@ -6,10 +6,14 @@ import (
 	"chetoru/internal/repository"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -28,7 +32,7 @@ func TestFetchTranslations_EmptyAnswerIsNotNil(t *testing.T) {
 	stubDoshamAPI(t, http.StatusOK, `{"data":{"find":[]}}`)
 	b := &Business{log: logrus.New()}
 
-	got, err := b.fetchTranslationsWithFallback("яблоками")
+	got, _, err := b.fetchTranslationsWithFallback("яблоками")
 	if err != nil {
 		t.Fatalf("real empty answer must not be an error: %v", err)
 	}
@@ -48,7 +52,7 @@ func TestFetchTranslations_CarriesLanguages(t *testing.T) {
 		 "translations":[{"translationId":"t1","content":"цӏа","languageCode":"ce"}]}]}}`)
 	b := &Business{log: logrus.New()}
 
-	got, err := b.fetchTranslationsWithFallback("Дом")
+	got, _, err := b.fetchTranslationsWithFallback("Дом")
 	if err != nil {
 		t.Fatalf("fetch: %v", err)
 	}
@@ -64,7 +68,7 @@ func TestFetchTranslations_HTTPErrorIsNil(t *testing.T) {
 	stubDoshamAPI(t, http.StatusInternalServerError, `{"data":{"find":[]}}`)
 	b := &Business{log: logrus.New()}
 
-	if got, err := b.fetchTranslationsWithFallback("яблоками"); err == nil {
+	if got, _, err := b.fetchTranslationsWithFallback("яблоками"); err == nil {
 		t.Fatalf("HTTP failure must be reported as an error so it is not cached, got %#v", got)
 	}
 }
@@ -106,7 +110,7 @@ func TestFetchTranslations_FailedVariantIsNotAMiss(t *testing.T) {
 	t.Setenv("DOSHAM_API_URL", srv.URL)
 	b := &Business{log: logrus.New()}
 
-	got, err := b.fetchTranslationsWithFallback("береза")
+	got, _, err := b.fetchTranslationsWithFallback("береза")
 	if err == nil {
 		t.Fatalf("a failed variant with no results anywhere must be an error, got %#v", got)
 	}
@@ -114,7 +118,7 @@ func TestFetchTranslations_FailedVariantIsNotAMiss(t *testing.T) {
 	// The mirror case: a variant that fails after something was already found
 	// is not an outage — at worst an extra spelling went unchecked.
 	stubDoshamFind(t, map[string]string{"береза": "Береза"})
-	if got, err := b.fetchTranslationsWithFallback("береза"); err != nil || len(got) != 1 {
+	if got, _, err := b.fetchTranslationsWithFallback("береза"); err != nil || len(got) != 1 {
 		t.Fatalf("got %+v (err %v), want the found pair and no error", got, err)
 	}
 }
@@ -168,7 +172,7 @@ func TestFetchTranslations_YoFallbackFindsVariant(t *testing.T) {
 	stubDoshamFind(t, map[string]string{"берёза": "Берёза"})
 	b := &Business{log: logrus.New()}
 
-	got, err := b.fetchTranslationsWithFallback("береза")
+	got, _, err := b.fetchTranslationsWithFallback("береза")
 	if err != nil {
 		t.Fatalf("fetch: %v", err)
 	}
@@ -213,6 +217,34 @@ func (r *recordingDictRepo) FindTranslationPairsByPrefix(context.Context, string
 	return nil, nil
 }
 
+func (r *recordingDictRepo) FindTranslationPairsContaining(context.Context, string, int) ([]models.TranslationPairs, error) {
+	return nil, nil
+}
+
+func (r *recordingDictRepo) SearchTranslationPairs(context.Context, string, int) ([]models.TranslationPairs, error) {
+	return nil, nil
+}
+
+func (r *recordingDictRepo) StoreEntryForms(context.Context, string, string, []string) (int, error) {
+	return 0, nil
+}
+
+func (r *recordingDictRepo) FindFormHeadwords(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+func (r *recordingDictRepo) ListLexicon(context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (r *recordingDictRepo) ListChechenLexicon(context.Context) ([]string, []string, error) {
+	return nil, nil, nil
+}
+
+func (r *recordingDictRepo) RandomTranslationPairs(context.Context, int) ([]models.TranslationPairs, error) {
+	return nil, nil
+}
+
 func (r *recordingDictRepo) InsertTranslationPair(_ context.Context, pair repository.TranslationPair) (int64, bool, error) {
 	r.inserted <- pair
 	return 1, true, nil
@@ -250,6 +282,64 @@ func TestFetchTranslations_StoresPairsDetached(t *testing.T) {
 	}
 }
 
+// «шёл» shares no prefix with «идти», so only the lemmatizer can rescue it.
+func TestSuggestTranslations_LemmaBeforePrefixes(t *testing.T) {
+	stubDoshamAPI(t, http.StatusInternalServerError, ``)
+	repo := newMirrorTestRepo(t)
+	if _, _, err := repo.InsertTranslationPair(context.Background(), repository.TranslationPair{
+		OriginalRaw: "Идти", OriginalClean: "идти", OriginalLang: "RUS",
+		TranslationRaw: "даха", TranslationClean: "даха", TranslationLang: "CHE", Source: "api",
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	b := &Business{log: logrus.New(), dictRepo: repo, cache: cache.NewCache("127.0.0.1:1", "")}
+
+	got := b.SuggestTranslations("шёл")
+	if len(got) != 1 || got[0].Original != "Идти" {
+		t.Fatalf("suggestions for шёл = %+v, want [Идти]", got)
+	}
+	b.WaitBackground()
+}
+
+// On a fresh database only dosham knows «идти»: the likeliest lemma gets one
+// live lookup, and the rules' other guesses none.
+func TestSuggestTranslations_LemmaLiveLookupCapped(t *testing.T) {
+	var mu sync.Mutex
+	var asked []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				InputText string `json:"inputText"`
+			} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		asked = append(asked, req.Variables.InputText)
+		mu.Unlock()
+		if req.Variables.InputText != "идти" {
+			fmt.Fprint(w, `{"data":{"find":[]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"find":[{"entryId":"e1","content":"Идти","type":"WORD","translations":[{"translationId":"t1","content":"даха","languageCode":"che"}]}]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+	b := &Business{log: logrus.New(), dictRepo: newMirrorTestRepo(t), cache: cache.NewCache("127.0.0.1:1", "")}
+
+	got := b.suggestFromLemmas("шёл")
+	if len(got) != 1 || got[0].Original != "Идти" {
+		t.Fatalf("suggestFromLemmas(шёл) = %+v, want идти's pair from dosham", got)
+	}
+	b.WaitBackground()
+	mu.Lock()
+	defer mu.Unlock()
+	for _, q := range asked {
+		if q != "идти" {
+			t.Fatalf("lemma probing asked dosham for %q, want only the likeliest lemma", asked)
+		}
+	}
+}
+
 type prefixDictRepo struct {
 	recordingDictRepo
 	byPrefix map[string][]models.TranslationPairs
@@ -279,7 +369,229 @@ func TestFetchTranslations_GraphQLErrorIsNil(t *testing.T) {
 	stubDoshamAPI(t, http.StatusOK, `{"errors":[{"message":"internal error"}],"data":null}`)
 	b := &Business{log: logrus.New()}
 
-	if got, err := b.fetchTranslationsWithFallback("яблоками"); err == nil {
+	if got, _, err := b.fetchTranslationsWithFallback("яблоками"); err == nil {
 		t.Fatalf("GraphQL error must be reported as an error so it is not cached, got %#v", got)
 	}
 }
+
+// Concurrent lookups of one word are one request: the fan-out of eight
+// handlers missing on the same word used to multiply every query by eight.
+func TestDoshamClient_CollapsesIdenticalLookups(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		<-release
+		fmt.Fprint(w, `{"data":{"find":[]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	c := NewDoshamClient()
+	const callers = 5
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Go(func() {
+			word := "Дом"
+			if i%2 == 0 {
+				word = " дом"
+			}
+			var out models.TranslationResponse
+			if err := c.Lookup(context.Background(), "find", "q", word, &out); err != nil {
+				t.Errorf("lookup: %v", err)
+			}
+		})
+	}
+	waitFor(t, func() bool { return doshamQuery(c, "find").Shared == callers-1 })
+	close(release)
+	wg.Wait()
+
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("server saw %d requests, want 1", got)
+	}
+}
+
+// ё is what the fallback varies, so it must not fold into the flight key —
+// otherwise "берёза" would be answered with the result for "береза".
+func TestDoshamClient_YoVariantsAreDistinctFlights(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		<-release
+		fmt.Fprint(w, `{"data":{"find":[]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	c := NewDoshamClient()
+	var wg sync.WaitGroup
+	for _, word := range []string{"береза", "берёза"} {
+		wg.Go(func() {
+			var out models.TranslationResponse
+			c.Lookup(context.Background(), "find", "q", word, &out)
+		})
+	}
+	waitFor(t, func() bool { return hits.Load() == 2 })
+	close(release)
+	wg.Wait()
+}
+
+func TestDoshamClient_CapsConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		inFlight.Add(-1)
+		fmt.Fprint(w, `{"data":{"randomEntries":[]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	c := newDoshamClient(2, doshamFailureThreshold, doshamCooldown)
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			var out struct{}
+			c.Query(context.Background(), "randomEntries", "q", nil, &out)
+		})
+	}
+	waitFor(t, func() bool { return inFlight.Load() == 2 })
+	close(release)
+	wg.Wait()
+
+	if got := peak.Load(); got != 2 {
+		t.Fatalf("peak concurrency = %d, want 2", got)
+	}
+	if got := doshamQuery(c, "randomEntries").Requests; got != 5 {
+		t.Fatalf("requests = %d, want all 5 to go out eventually", got)
+	}
+}
+
+// Once dosham is down, the next user hears so at once instead of after another
+// round of timeouts, and a probe after the cooldown closes the breaker again.
+func TestDoshamClient_BreakerOpensAndRecovers(t *testing.T) {
+	var hits atomic.Int32
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"find":[]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	c := newDoshamClient(doshamMaxInFlight, 3, 50*time.Millisecond)
+	lookup := func(word string) error {
+		var out models.TranslationResponse
+		return c.Lookup(context.Background(), "find", "q", word, &out)
+	}
+	for i := range 3 {
+		if err := lookup(fmt.Sprint("слово", i)); err == nil || errors.Is(err, ErrDoshamUnavailable) {
+			t.Fatalf("failure %d: err = %v, want the upstream error", i, err)
+		}
+	}
+	if err := lookup("дом"); !errors.Is(err, ErrDoshamUnavailable) {
+		t.Fatalf("open breaker: err = %v, want ErrDoshamUnavailable", err)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Fatalf("server saw %d requests, want the open breaker to send none", got)
+	}
+	if s := c.Stats(); !s.BreakerOpen || doshamQuery(c, "find").Rejected != 1 {
+		t.Fatalf("stats = %+v, want open with one rejection", s)
+	}
+
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	if err := lookup("дом"); err != nil {
+		t.Fatalf("probe after cooldown: %v", err)
+	}
+	if err := lookup("дитт"); err != nil {
+		t.Fatalf("closed breaker: %v", err)
+	}
+}
+
+// A request allowed before the breaker opened may finish while the half-open
+// probe is out; its verdict counts, but it must not let a second probe through.
+func TestDoshamClient_StragglerDoesNotEndProbe(t *testing.T) {
+	c := newDoshamClient(doshamMaxInFlight, 1, time.Millisecond)
+	_, straggler := c.allow()
+	c.record(false, false)
+	time.Sleep(2 * time.Millisecond)
+
+	allowed, probe := c.allow()
+	if !allowed || !probe {
+		t.Fatalf("allow after cooldown = %v, %v; want the probe", allowed, probe)
+	}
+	time.Sleep(2 * time.Millisecond)
+	c.release(straggler)
+	if allowed, _ := c.allow(); allowed {
+		t.Fatal("second probe allowed while the first is in flight")
+	}
+	c.record(true, probe)
+	if allowed, _ := c.allow(); !allowed {
+		t.Fatal("breaker still refusing after a successful probe")
+	}
+}
+
+// A GraphQL error is the server answering, not the server being down: it
+// fails the query but must not count toward opening the breaker.
+func TestDoshamClient_GraphQLErrorDoesNotTrip(t *testing.T) {
+	stubDoshamAPI(t, http.StatusOK, `{"errors":[{"message":"bad query"}],"data":null}`)
+	c := newDoshamClient(doshamMaxInFlight, 2, time.Minute)
+	for range 4 {
+		var out models.TranslationResponse
+		if err := c.Lookup(context.Background(), "find", "q", "дом", &out); err == nil || errors.Is(err, ErrDoshamUnavailable) {
+			t.Fatalf("err = %v, want the GraphQL error", err)
+		}
+	}
+	if c.Stats().BreakerOpen {
+		t.Fatal("GraphQL errors must not open the breaker")
+	}
+}
+
+// Translate is what HandleText calls; with the breaker open it must still
+// answer with an error — the "dictionary unavailable" path — not a miss.
+func TestTranslate_OpenBreakerIsAnError(t *testing.T) {
+	stubDoshamAPI(t, http.StatusOK, `{"data":{"find":[]}}`)
+	c := newDoshamClient(doshamMaxInFlight, 1, time.Minute)
+	c.record(false, false)
+	b := &Business{log: logrus.New(), cache: cache.NewCache("127.0.0.1:1", ""), dosham: c}
+
+	if got, err := b.Translate("дом"); !errors.Is(err, ErrDoshamUnavailable) {
+		t.Fatalf("Translate = %+v, %v; want ErrDoshamUnavailable", got, err)
+	}
+	b.WaitBackground()
+}
+
+func doshamQuery(c *DoshamClient, name string) models.DoshamQueryStats {
+	for _, q := range c.Stats().Queries {
+		if q.Query == name {
+			return q
+		}
+	}
+	return models.DoshamQueryStats{}
+}
+
+// waitFor polls cond for up to a second; the fake servers above block until
+// the test has seen every caller arrive, so the ordering is deterministic.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached within a second")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
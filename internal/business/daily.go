@@ -0,0 +1,113 @@
+package business
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+)
+
+// DailyChallengeSize is how many questions a daily challenge has.
+const DailyChallengeSize = 10
+
+// dailyDrawTries bounds how many headwords the challenge draws before giving
+// up: a headword without a clean meaning, or with one already taken, is
+// skipped, and a small lexicon must not spin forever.
+const dailyDrawTries = 20
+
+// DailyChallenge builds the challenge for day (a time.DateOnly date):
+// DailyChallengeSize translation questions, half asked each way, with
+// distractors chosen as /quiz chooses them. Everything in it — the words,
+// the order of directions, the distractors and the order of options — is
+// drawn from the sorted local lexicon by a source seeded by day, as
+// WordleWord is. The lexicon grows between days, so the caller still stores
+// the result, and that stored copy is what every player gets.
+func (b *Business) DailyChallenge(ctx context.Context, day string) ([]models.QuizQuestion, error) {
+	rng := rand.New(rand.NewPCG(daySeed("daily", day)))
+	headwords := b.gameVocabulary(ctx).headwords
+
+	words := b.drawDailyWords(ctx, headwords, DailyChallengeSize, nil, rng)
+	if len(words) < DailyChallengeSize {
+		return nil, fmt.Errorf("not enough words in the lexicon for a daily challenge: %d", len(words))
+	}
+
+	reversed := make([]bool, len(words))
+	for i := range reversed {
+		reversed[i] = i%2 == 1
+	}
+	rng.Shuffle(len(reversed), func(i, j int) { reversed[i], reversed[j] = reversed[j], reversed[i] })
+
+	questions := make([]models.QuizQuestion, 0, len(words))
+	for i, w := range words {
+		candidates := b.drawDailyWords(ctx, headwords, quizCandidateCount, words, rng)
+		if same := slices.DeleteFunc(slices.Clone(candidates), func(c models.RandomWord) bool {
+			return c.Subtype != w.Subtype
+		}); w.Subtype != 0 && len(same) >= quizOptionCount-1 {
+			candidates = same
+		}
+		if len(candidates) < quizOptionCount-1 {
+			return nil, fmt.Errorf("not enough distractors for %q", w.Chechen)
+		}
+		distractors, _ := pickDistractors(w, candidates, quizOptionCount-1, reversed[i], b.quizDifficulty, rng)
+		questions = append(questions, *buildQuiz(w, distractors, reversed[i], rng))
+	}
+	return questions, nil
+}
+
+// drawDailyWords draws up to count distinct words from headwords with rng,
+// none sharing a meaning with another or with any of avoid.
+func (b *Business) drawDailyWords(ctx context.Context, headwords []string, count int, avoid []models.RandomWord, rng quizRand) []models.RandomWord {
+	if len(headwords) == 0 {
+		return nil
+	}
+	var words []models.RandomWord
+	for range count * dailyDrawTries {
+		if len(words) == count {
+			break
+		}
+		w, ok := b.localWord(ctx, headwords[rng.IntN(len(headwords))])
+		if !ok {
+			continue
+		}
+		taken := func(have models.RandomWord) bool { return sameMeaning(w, have) }
+		if slices.ContainsFunc(words, taken) || slices.ContainsFunc(avoid, taken) {
+			continue
+		}
+		words = append(words, w)
+	}
+	return words
+}
+
+// localWord reads a Chechen headword and its first sense from the local
+// table, as CardWord does from a lookup — without one: the challenge must
+// come out the same however dosham and the cache answer today.
+func (b *Business) localWord(ctx context.Context, headword string) (models.RandomWord, bool) {
+	pairs := b.loadLocalTranslations(ctx, headword)
+	head, ok := tools.ChechenHead(headword, pairs)
+	if !ok {
+		return models.RandomWord{}, false
+	}
+	_, sense, ok := tools.Gloss(headword, pairs)
+	if !ok {
+		return models.RandomWord{}, false
+	}
+	w := makeRandomWord(head, sense)
+	if w == nil || !isLearnableWord(w.Chechen) || !isCleanMeaning(w.Russian) {
+		return models.RandomWord{}, false
+	}
+	w.Subtype, w.Rate = pairs[0].Subtype, pairs[0].Rate
+	return *w, true
+}
+
+// daySeed derives a daily game's random seed from its name and date, so each
+// game draws its own sequence for the same day.
+func daySeed(game, day string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(game + ":" + day))
+	sum := h.Sum64()
+	return sum, sum ^ 0x9e3779b97f4a7c15
+}
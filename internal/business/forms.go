@@ -0,0 +1,90 @@
+package business
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"context"
+	"strings"
+)
+
+// storeEntryForms records the paradigm of every analyzed entry in a grammar
+// response, not just the one the card shows: the other entries arrived in
+// the same payload and their forms are just as real.
+func (b *Business) storeEntryForms(ctx context.Context, entries []grammarEntry) (int, error) {
+	if b.dictRepo == nil {
+		return 0, nil
+	}
+	added := 0
+	for _, e := range entries {
+		if e.Type != "WORD" || len(e.EntryForms) == 0 {
+			continue
+		}
+		headword := strings.TrimSpace(e.Content)
+		headwordClean := normalizeForRank(headword)
+		// The base form goes in with the rest, so the headword itself is
+		// recognized as one and never resolved to another word.
+		forms := []string{headwordClean}
+		for _, f := range e.EntryForms {
+			if clean := normalizeForRank(f.Content); clean != "" {
+				forms = append(forms, clean)
+			}
+		}
+		n, err := b.dictRepo.StoreEntryForms(ctx, headword, headwordClean, forms)
+		if err != nil {
+			return added, err
+		}
+		added += n
+	}
+	return added, nil
+}
+
+// IndexEntryForms fetches word's grammar from dosham and stores the paradigms
+// it carries, returning how many forms were new. It backs cmd/backfill_forms;
+// the bot fills the same table as a side effect of GrammarFor.
+func (b *Business) IndexEntryForms(ctx context.Context, word string) (int, error) {
+	entries, err := b.queryGrammarEntries(ctx, word)
+	if err != nil {
+		return 0, err
+	}
+	return b.storeEntryForms(ctx, entries)
+}
+
+// resolveEntryForm returns the headword an inflected Chechen form belongs to,
+// or "" when the form index does not know it.
+func (b *Business) resolveEntryForm(ctx context.Context, word string) string {
+	if b.dictRepo == nil {
+		return ""
+	}
+	key := normalizeForRank(word)
+	if key == "" || strings.ContainsAny(key, " \t\n") {
+		return ""
+	}
+	headwords, err := b.dictRepo.FindFormHeadwords(ctx, key)
+	if err != nil {
+		b.log.Printf("entry form lookup failed for %q: %v\n", word, err)
+		return ""
+	}
+	if len(headwords) == 0 {
+		return ""
+	}
+	return headwords[0]
+}
+
+// translateEntryForm answers an inflected Chechen form with its headword's
+// pairs, each marked FormOf so the card can say whose form it is.
+func (b *Business) translateEntryForm(ctx context.Context, word string) []models.TranslationPairs {
+	headword := b.resolveEntryForm(ctx, word)
+	if headword == "" || tools.NormalizeSearch(headword) == tools.NormalizeSearch(word) {
+		return nil
+	}
+	pairs, err := b.Translate(headword)
+	if err != nil || len(pairs) == 0 {
+		return nil
+	}
+	out := make([]models.TranslationPairs, len(pairs))
+	for i, p := range pairs {
+		p.FormOf = headword
+		out[i] = p
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package business
+
+import (
+	"chetoru/internal/cache"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dosham's search knows «дийца» but not its past tense; only the grammar
+// payload's entryForms connects the two.
+func TestTranslate_ResolvesEntryFormToHeadword(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string `json:"query"`
+			Variables struct {
+				InputText string `json:"inputText"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if strings.ToLower(req.Variables.InputText) != "дийца" {
+			fmt.Fprint(w, `{"data":{"find":[]}}`)
+			return
+		}
+		if strings.Contains(req.Query, "entryForms") {
+			fmt.Fprint(w, `{"data":{"find":[{"content":"Дийца","type":"WORD","rate":10000,"details":"{}",
+				"entryForms":[{"content":"ди́йцира"},{"content":"дийцина"}],"relatedEntries":[]}]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"find":[{"entryId":"e1","content":"Дийца","type":"WORD","rate":10000,"translations":[
+			{"translationId":"t1","content":"рассказать","languageCode":"ru"}]}]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	b := &Business{log: logrus.New(), dictRepo: newMirrorTestRepo(t), cache: cache.NewCache("127.0.0.1:1", "")}
+
+	// Before any grammar fetch the form is unknown.
+	if got, err := b.Translate("дийцира"); err != nil || len(got) != 0 {
+		t.Fatalf("Translate before indexing = %+v, %v; want nothing", got, err)
+	}
+
+	if _, err := b.GrammarFor(t.Context(), "дийца"); err != nil {
+		t.Fatalf("GrammarFor: %v", err)
+	}
+	b.WaitBackground()
+
+	got, err := b.Translate("Дийцира")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if len(got) != 1 || got[0].Translate != "рассказать" || got[0].FormOf != "Дийца" {
+		t.Fatalf("Translate(дийцира) = %+v, want the headword's pair marked FormOf Дийца", got)
+	}
+
+	// The headword itself is not a form of anything.
+	got, err = b.Translate("дийца")
+	if err != nil || len(got) != 1 || got[0].FormOf != "" {
+		t.Fatalf("Translate(дийца) = %+v, %v; want its own pair", got, err)
+	}
+}
+
+// «цӏа» is a headword of its own and also a form of «цӏе»; typed as itself
+// it must open its own entry, not "форма слова Цӏе".
+func TestTranslate_HeadwordBeforeEntryForm(t *testing.T) {
+	stubDoshamFind(t, map[string]string{"цӏа": "Цӏа", "цӏе": "Цӏе", "Цӏе": "Цӏе"})
+	repo := newMirrorTestRepo(t)
+	if _, err := repo.StoreEntryForms(t.Context(), "Цӏе", "цӏе", []string{"цӏе", "цӏа"}); err != nil {
+		t.Fatalf("StoreEntryForms: %v", err)
+	}
+	b := &Business{log: logrus.New(), dictRepo: repo, cache: cache.NewCache("127.0.0.1:1", "")}
+
+	got, err := b.Translate("цӏа")
+	b.WaitBackground()
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if len(got) != 1 || got[0].Original != "Цӏа" || got[0].FormOf != "" {
+		t.Fatalf("Translate(цӏа) = %+v, want its own entry", got)
+	}
+}
@@ -0,0 +1,164 @@
+package business
+
+import (
+	"chetoru/pkg/tools"
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// lexiconTTL is how long a built index answers before it is rebuilt. Every
+	// successful lookup adds pairs and every grammar fetch adds forms, so a
+	// stale index misses only words learned within the window.
+	lexiconTTL = time.Hour
+	// maxDidYouMean is how many suggestion buttons a miss carries.
+	maxDidYouMean = 5
+)
+
+// lexiconIndex is the fuzzy index over the local lexicon. Building it reads
+// every headword, so it happens once on first use and then in the background
+// when the index ages out, while the old one keeps answering.
+type lexiconIndex struct {
+	mu       sync.Mutex
+	tree     *tools.BKTree
+	built    time.Time
+	building bool
+	// first is closed when the build the first miss started is done. Other
+	// misses meanwhile wait on it rather than build their own.
+	first chan struct{}
+}
+
+// fuzzyBudget is how many edits a query of n letters may be away from a
+// suggestion. Short words get none: «дом» is one edit from dozens of words,
+// and offering all of them is noise, not help.
+func fuzzyBudget(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 6:
+		return 1
+	case n < 10:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// DidYouMean returns the lexicon words closest to a query that found nothing,
+// nearest first. It is local and cheap — the answer to a typo before anything
+// is asked of the AI spellchecker.
+func (b *Business) DidYouMean(word string) []tools.FuzzyMatch {
+	key := tools.FuzzyKey(word)
+	budget := fuzzyBudget(utf8.RuneCountInString(key))
+	if budget == 0 || !isSingleWord(key) {
+		return nil
+	}
+	tree := b.lexiconTree(context.Background())
+	if tree == nil {
+		return nil
+	}
+	// One more than shown, since the query itself may be in the lexicon: a
+	// stored headword dosham no longer answers for is still a miss, and a
+	// button that reopens the same miss is worse than none.
+	matches := tree.Search(key, budget, maxDidYouMean+1)
+	out := matches[:0]
+	for _, m := range matches {
+		if m.Distance > 0 && len(out) < maxDidYouMean {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func isSingleWord(s string) bool {
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+// lexiconTree returns the current index, building it on first use and
+// scheduling a rebuild once it is older than lexiconTTL.
+func (b *Business) lexiconTree(ctx context.Context) *tools.BKTree {
+	if b.dictRepo == nil {
+		return nil
+	}
+	idx := &b.lexicon
+	idx.mu.Lock()
+	tree, built, building := idx.tree, idx.built, idx.building
+	stale := tree != nil && time.Since(built) > lexiconTTL
+	if stale && !building {
+		idx.building = true
+	}
+	idx.mu.Unlock()
+
+	if tree == nil {
+		// The first miss after startup pays for the build, once: a burst of
+		// misses at startup would otherwise read the whole lexicon each.
+		return b.firstLexiconBuild(ctx)
+	}
+	if stale && !building {
+		b.bg.Go(func() { b.rebuildLexicon(context.Background()) })
+	}
+	return tree
+}
+
+// firstLexiconBuild builds the index for the first miss, or waits for the
+// build another one started. The build runs on the first caller's context; a
+// failed one leaves the index empty for the next miss to try again.
+func (b *Business) firstLexiconBuild(ctx context.Context) *tools.BKTree {
+	idx := &b.lexicon
+	idx.mu.Lock()
+	if idx.tree != nil {
+		tree := idx.tree
+		idx.mu.Unlock()
+		return tree
+	}
+	if first := idx.first; first != nil {
+		idx.mu.Unlock()
+		select {
+		case <-first:
+		case <-ctx.Done():
+			return nil
+		}
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+		return idx.tree
+	}
+	first := make(chan struct{})
+	idx.first = first
+	idx.mu.Unlock()
+
+	tree := b.rebuildLexicon(ctx)
+
+	idx.mu.Lock()
+	idx.first = nil
+	idx.mu.Unlock()
+	close(first)
+	return tree
+}
+
+func (b *Business) rebuildLexicon(ctx context.Context) *tools.BKTree {
+	words, err := b.dictRepo.ListLexicon(ctx)
+	idx := &b.lexicon
+	if err != nil {
+		b.log.Printf("lexicon load failed: %v\n", err)
+		idx.mu.Lock()
+		idx.building = false
+		tree := idx.tree
+		idx.mu.Unlock()
+		return tree
+	}
+	tree := &tools.BKTree{}
+	for _, w := range words {
+		tree.Add(w)
+	}
+	idx.mu.Lock()
+	idx.tree, idx.built, idx.building = tree, time.Now(), false
+	idx.mu.Unlock()
+	return tree
+}
@@ -0,0 +1,91 @@
+package business
+
+import (
+	"chetoru/internal/repository"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestDidYouMean_LexiconAndForms(t *testing.T) {
+	repo := newMirrorTestRepo(t)
+	ctx := context.Background()
+	for _, p := range []repository.TranslationPair{
+		{OriginalRaw: "Гӏала", OriginalClean: "гӏала", OriginalLang: "CHE", TranslationRaw: "город", TranslationClean: "город", TranslationLang: "RUS"},
+		{OriginalRaw: "Яблоко", OriginalClean: "яблоко", OriginalLang: "RUS", TranslationRaw: "Ӏаж", TranslationClean: "ӏаж", TranslationLang: "CHE"},
+		{OriginalRaw: "Гӏалин кӏант", OriginalClean: "гӏалин кӏант", OriginalLang: "CHE", TranslationRaw: "горожанин", TranslationClean: "горожанин", TranslationLang: "RUS", EntryType: "TEXT"},
+	} {
+		p.Source = "api"
+		if _, _, err := repo.InsertTranslationPair(ctx, p); err != nil {
+			t.Fatalf("insert %q: %v", p.OriginalRaw, err)
+		}
+	}
+	if _, err := repo.StoreEntryForms(ctx, "Дийца", "дийца", []string{"дийца", "дийцира"}); err != nil {
+		t.Fatalf("StoreEntryForms: %v", err)
+	}
+
+	b := &Business{log: logrus.New(), dictRepo: repo}
+
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"гӏалла", "Гӏала"},  // doubled letter
+		{"г1алла", "Гӏала"},  // and typed with the digit stand-in
+		{"яблака", "Яблоко"}, // two edits in a six-letter word
+		{"дийцара", "дийцира"},
+	}
+	for _, c := range cases {
+		got := b.DidYouMean(c.query)
+		if len(got) == 0 || got[0].Word != c.want {
+			t.Errorf("DidYouMean(%q) = %+v, want %q first", c.query, got, c.want)
+		}
+	}
+
+	// The stored word itself is not a suggestion, a three-letter query gets
+	// none, and neither does a phrase.
+	if got := b.DidYouMean("Гӏала"); len(got) != 0 {
+		t.Errorf("DidYouMean(Гӏала) = %+v, want no button back to the same miss", got)
+	}
+	if got := b.DidYouMean("ӏаш"); got != nil {
+		t.Errorf("DidYouMean(ӏаш) = %+v, want nothing for a short word", got)
+	}
+	if got := b.DidYouMean("гӏалин кӏан"); got != nil {
+		t.Errorf("DidYouMean(phrase) = %+v, want nothing", got)
+	}
+}
+
+// countingLexiconRepo counts lexicon reads, each slow enough for concurrent
+// first misses to overlap.
+type countingLexiconRepo struct {
+	recordingDictRepo
+	reads atomic.Int32
+}
+
+func (r *countingLexiconRepo) ListLexicon(context.Context) ([]string, error) {
+	r.reads.Add(1)
+	time.Sleep(50 * time.Millisecond)
+	return []string{"гӏала"}, nil
+}
+
+func TestLexiconTree_ConcurrentFirstMissesBuildOnce(t *testing.T) {
+	repo := &countingLexiconRepo{}
+	b := &Business{log: logrus.New(), dictRepo: repo}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if b.lexiconTree(context.Background()) == nil {
+				t.Error("lexiconTree = nil, want the built index")
+			}
+		})
+	}
+	wg.Wait()
+	if n := repo.reads.Load(); n != 1 {
+		t.Fatalf("lexicon read %d times, want once", n)
+	}
+}
@@ -0,0 +1,144 @@
+package business
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/morph"
+	"chetoru/pkg/tools"
+	"regexp"
+	"sync"
+)
+
+const (
+	// maxGlossWords bounds one /gloss: every distinct word is a lookup, and a
+	// pasted article would otherwise be hundreds of them.
+	maxGlossWords = 200
+	// glossWorkers is how many words are looked up at once. The dosham client
+	// caps its own concurrency; this keeps one long text from filling it.
+	glossWorkers = 4
+)
+
+// glossWordRe finds the words of a text: letters with their combining stress
+// marks, the digit-1 palochka stand-in, and hyphenated compounds.
+var glossWordRe = regexp.MustCompile(`[\p{L}\p{M}1]+(?:-[\p{L}\p{M}1]+)*`)
+
+// Gloss reads text word by word, Chechen or Russian, the way a learner reads
+// with a dictionary open: each word is looked up as typed, then — a Chechen
+// form through the form index, a Russian one through its lemmas — as the
+// headword it belongs to. A word repeated in the text is looked up once.
+func (b *Business) Gloss(text string) models.Gloss {
+	var g models.Gloss
+	for _, w := range glossWordRe.FindAllString(text, -1) {
+		if !hasLetter(w) {
+			continue
+		}
+		if len(g.Tokens) == maxGlossWords {
+			g.Truncated = true
+			break
+		}
+		g.Tokens = append(g.Tokens, models.GlossToken{Word: w})
+	}
+
+	byKey := map[string][]int{}
+	var keys []string
+	for i, t := range g.Tokens {
+		k := normalizeForRank(t.Word)
+		if _, ok := byKey[k]; !ok {
+			keys = append(keys, k)
+		}
+		byKey[k] = append(byKey[k], i)
+	}
+
+	resolved := make([]models.GlossToken, len(keys))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(glossWorkers, len(keys)) {
+		wg.Go(func() {
+			for i := range work {
+				resolved[i] = b.glossWord(g.Tokens[byKey[keys[i]][0]].Word)
+			}
+		})
+	}
+	for i := range keys {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	for i, k := range keys {
+		for _, at := range byKey[k] {
+			word := g.Tokens[at].Word
+			g.Tokens[at] = resolved[i]
+			g.Tokens[at].Word = word
+		}
+	}
+	return g
+}
+
+// glossWord resolves one word to its headword and first sense.
+func (b *Business) glossWord(word string) models.GlossToken {
+	t := models.GlossToken{Word: word}
+	pairs, err := b.Translate(word)
+	if err != nil {
+		t.Failed = true
+		return t
+	}
+	query := answeredQuery(word, pairs)
+	if head, sense, ok := tools.Gloss(query, pairs); ok {
+		t.Lemma, t.Gloss, t.Known = head, sense, true
+		return t
+	}
+
+	// An inflected Russian word: the dictionary has its lemma, under which
+	// the pairs suggestFromLemmas found are filed.
+	if lemmaPairs := b.suggestFromLemmas(word); len(lemmaPairs) > 0 {
+		for _, l := range morph.Lemmas(word) {
+			if head, sense, ok := tools.Gloss(l.Word, lemmaPairs); ok {
+				t.Lemma, t.Gloss, t.Known = head, sense, true
+				return t
+			}
+		}
+	}
+	return t
+}
+
+// CardWord returns the Chechen headword the card for word opens with and its
+// first sense — what «⭐ Сохранить» under the card saves. It returns nil
+// when the card opens on a Russian headword or on nothing.
+func (b *Business) CardWord(word string) (*models.RandomWord, error) {
+	pairs, err := b.Translate(word)
+	if err != nil {
+		return nil, err
+	}
+	query := answeredQuery(word, pairs)
+	head, ok := tools.ChechenHead(query, pairs)
+	if !ok {
+		return nil, nil
+	}
+	_, sense, ok := tools.Gloss(query, pairs)
+	if !ok {
+		return nil, nil
+	}
+	return makeRandomWord(head, sense), nil
+}
+
+// answeredQuery is the word Translate actually answered for — the spelling
+// it corrected word to, or the headword of the form word is — which the
+// answer is read for, as the card does.
+func answeredQuery(word string, pairs []models.TranslationPairs) string {
+	if len(pairs) > 0 && pairs[0].FormOf != "" {
+		return pairs[0].FormOf
+	}
+	if len(pairs) > 0 && pairs[0].Respelled != "" {
+		return pairs[0].Respelled
+	}
+	return word
+}
+
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if r != '1' && r != '-' {
+			return true
+		}
+	}
+	return false
+}
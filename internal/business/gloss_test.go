@@ -0,0 +1,99 @@
+package business
+
+import (
+	"chetoru/internal/cache"
+	"chetoru/internal/repository"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestGloss_WordByWord(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fmt.Fprint(w, `{"data":{"find":[]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	repo := newMirrorTestRepo(t)
+	ctx := context.Background()
+	for _, p := range []repository.TranslationPair{
+		{OriginalRaw: "Гӏала", OriginalClean: "гӏала", OriginalLang: "CHE", TranslationRaw: "город", TranslationClean: "город", TranslationLang: "RUS"},
+		{OriginalRaw: "Яблоко", OriginalClean: "яблоко", OriginalLang: "RUS", TranslationRaw: "Ӏаж", TranslationClean: "ӏаж", TranslationLang: "CHE"},
+	} {
+		p.Source = "api"
+		if _, _, err := repo.InsertTranslationPair(ctx, p); err != nil {
+			t.Fatalf("insert %q: %v", p.OriginalRaw, err)
+		}
+	}
+	b := &Business{log: logrus.New(), dictRepo: repo, cache: cache.NewCache("127.0.0.1:1", "")}
+
+	g := b.Gloss("Г1ала, яблоками! зузук — гӏала.")
+	if g.Truncated || len(g.Tokens) != 4 {
+		t.Fatalf("tokens = %+v, want four words and no punctuation", g.Tokens)
+	}
+	want := []struct {
+		word, lemma, gloss string
+		known              bool
+	}{
+		{"Г1ала", "Гӏала", "город", true},
+		{"яблоками", "Яблоко", "Ӏаж", true},
+		{"зузук", "", "", false},
+		{"гӏала", "Гӏала", "город", true},
+	}
+	for i, w := range want {
+		got := g.Tokens[i]
+		if got.Word != w.word || got.Lemma != w.lemma || got.Gloss != w.gloss || got.Known != w.known || got.Failed {
+			t.Errorf("token %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestGloss_TruncatesLongText(t *testing.T) {
+	b := &Business{log: logrus.New(), dictRepo: newMirrorTestRepo(t), cache: cache.NewCache("127.0.0.1:1", "")}
+	b.SetOfflineMode(true)
+	text := ""
+	for i := range maxGlossWords + 5 {
+		text += fmt.Sprintf("слово%c ", 'а'+rune(i%30))
+	}
+	g := b.Gloss(text)
+	if !g.Truncated || len(g.Tokens) != maxGlossWords {
+		t.Fatalf("Gloss of %d words: %d tokens, truncated %v", maxGlossWords+5, len(g.Tokens), g.Truncated)
+	}
+}
+
+func TestCardWord_ChechenHeadOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"find":[]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	repo := newMirrorTestRepo(t)
+	ctx := context.Background()
+	for _, p := range []repository.TranslationPair{
+		{OriginalRaw: "Гӏала", OriginalClean: "гӏала", OriginalLang: "CHE", TranslationRaw: "город, крепость", TranslationClean: "город, крепость", TranslationLang: "RUS"},
+		{OriginalRaw: "Яблоко", OriginalClean: "яблоко", OriginalLang: "RUS", TranslationRaw: "Ӏаж", TranslationClean: "ӏаж", TranslationLang: "CHE"},
+	} {
+		p.Source = "api"
+		if _, _, err := repo.InsertTranslationPair(ctx, p); err != nil {
+			t.Fatalf("insert %q: %v", p.OriginalRaw, err)
+		}
+	}
+	b := &Business{log: logrus.New(), dictRepo: repo, cache: cache.NewCache("127.0.0.1:1", "")}
+
+	w, err := b.CardWord("г1ала")
+	if err != nil || w == nil || w.Chechen != "Гӏала" || w.Russian != "город, крепость" {
+		t.Fatalf("CardWord(г1ала) = %+v, %v; want Гӏала — город, крепость", w, err)
+	}
+	if w, err := b.CardWord("яблоко"); err != nil || w != nil {
+		t.Fatalf("CardWord(яблоко) = %+v, %v; want nothing for a Russian headword", w, err)
+	}
+}
@@ -0,0 +1,130 @@
+package business
+
+import (
+	"bufio"
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+)
+
+// Glossary is a dictionary source read from a file: a hand-kept word list
+// for what dosham lacks — regional words, new coinages, a teacher's course
+// vocabulary. One pair per line, tab-separated:
+//
+//	chechen<TAB>russian[<TAB>note]
+//
+// Blank lines and lines starting with # are skipped. The file is read once,
+// at startup; edit it and restart.
+type Glossary struct {
+	entries []glossaryEntry
+}
+
+type glossaryEntry struct {
+	chechen, russian, note string
+	// Search keys, folded the way dictionary_pairs' clean columns are.
+	chechenKey, russianKey string
+}
+
+// LoadGlossary reads a glossary file.
+func LoadGlossary(path string) (*Glossary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("glossary: %w", err)
+	}
+	defer f.Close()
+
+	g := &Glossary{}
+	sc := bufio.NewScanner(f)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 2 || strings.TrimSpace(fields[0]) == "" || strings.TrimSpace(fields[1]) == "" {
+			return nil, fmt.Errorf("glossary %s:%d: want chechen<TAB>russian[<TAB>note]", path, line)
+		}
+		e := glossaryEntry{
+			chechen: strings.TrimSpace(fields[0]),
+			russian: strings.TrimSpace(fields[1]),
+		}
+		if len(fields) > 2 {
+			e.note = strings.TrimSpace(fields[2])
+		}
+		e.chechenKey = normalizeText(e.chechen)
+		e.russianKey = normalizeText(e.russian)
+		g.entries = append(g.entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("glossary %s: %w", path, err)
+	}
+	return g, nil
+}
+
+// Len reports how many pairs the glossary holds.
+func (g *Glossary) Len() int {
+	return len(g.entries)
+}
+
+func (g *Glossary) Name() string { return SourceGlossary }
+
+// Search matches a substring of either side, as dosham does, and orients
+// each hit the way dosham would: a Chechen-side hit is a Chechen headword
+// with its Russian translation, a Russian-side hit the reverse.
+func (g *Glossary) Search(_ context.Context, word string) ([]models.TranslationPairs, error) {
+	key := normalizeText(word)
+	if key == "" {
+		return nil, nil
+	}
+	var out []models.TranslationPairs
+	for _, e := range g.entries {
+		switch {
+		case strings.Contains(e.chechenKey, key):
+			out = append(out, models.TranslationPairs{
+				Original:      tools.EscapeUnclosedTags(e.chechen),
+				Translate:     tools.EscapeUnclosedTags(e.russian),
+				OriginalLang:  "CHE",
+				TranslateLang: "RUS",
+				EntryType:     "WORD",
+				Notes:         e.note,
+			})
+		case strings.Contains(e.russianKey, key):
+			out = append(out, models.TranslationPairs{
+				Original:      tools.EscapeUnclosedTags(e.russian),
+				Translate:     tools.EscapeUnclosedTags(e.chechen),
+				OriginalLang:  "RUS",
+				TranslateLang: "CHE",
+				EntryType:     "WORD",
+				Notes:         e.note,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (g *Glossary) Random(_ context.Context, count int) ([]models.Entry, error) {
+	if count > len(g.entries) {
+		count = len(g.entries)
+	}
+	out := make([]models.Entry, 0, count)
+	for _, i := range rand.Perm(len(g.entries))[:count] {
+		e := g.entries[i]
+		out = append(out, models.Entry{
+			Content:      e.chechen,
+			Type:         "WORD",
+			Translations: []models.Translation{{Content: e.russian, LanguageCode: "ru"}},
+		})
+	}
+	return out, nil
+}
+
+// Grammar is nil: a glossary line carries no paradigm.
+func (g *Glossary) Grammar(context.Context, string) (*models.WordGrammar, error) {
+	return nil, nil
+}
@@ -65,7 +65,14 @@ func (b *Business) GrammarFor(ctx context.Context, word string) (*models.WordGra
 		b.log.Printf("grammar cache get failed for %q: %v\n", cacheKey, err)
 	}
 
-	g, err := b.computeGrammar(ctx, word)
+	// The mirror stores pairs, not dosham's morphology, so offline there is no
+	// grammar to show; the card renders without its grammar line, as it does
+	// for any word dosham never analyzed.
+	if b.OfflineMode() {
+		return nil, nil
+	}
+
+	g, err := b.grammarFromSources(ctx, word)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +167,22 @@ func firstRussian(translations []grammarTranslation) string {
 }
 
 // findGrammarEntries runs the grammar `find` query and returns the raw entries.
+// The paradigms it carries go into the form index on the way past, detached —
+// that write must not sit between the user and the grammar card.
 func (b *Business) findGrammarEntries(ctx context.Context, word string) ([]grammarEntry, error) {
+	entries, err := b.queryGrammarEntries(ctx, word)
+	if err != nil {
+		return nil, err
+	}
+	b.bg.Go(func() {
+		if _, err := b.storeEntryForms(context.Background(), entries); err != nil {
+			b.log.Printf("failed to store entry forms for %q: %v\n", word, err)
+		}
+	})
+	return entries, nil
+}
+
+func (b *Business) queryGrammarEntries(ctx context.Context, word string) ([]grammarEntry, error) {
 	query := `
 		query Grammar($inputText: String!) {
 			find(inputText: $inputText) {
@@ -174,7 +196,7 @@ func (b *Business) findGrammarEntries(ctx context.Context, word string) ([]gramm
 		}
 	`
 	var resp grammarResponse
-	if err := doDoshamQuery(ctx, query, map[string]any{"inputText": word}, &resp); err != nil {
+	if err := b.doshamClient().Lookup(ctx, "grammar", query, word, &resp); err != nil {
 		return nil, fmt.Errorf("dosham grammar %q: %w", word, err)
 	}
 	return resp.Data.Find, nil
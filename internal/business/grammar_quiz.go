@@ -0,0 +1,222 @@
+package business
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// grammarQuizDrawAttempts bounds how many pool words a grammar question
+	// looks up before giving up: most words dosham never analyzed have no
+	// forms and no set phrases to ask about.
+	grammarQuizDrawAttempts = 6
+	// quizMaxOptionRunes is Telegram's limit on a poll option. A set phrase's
+	// meaning can run longer, and the question must play as a poll too.
+	quizMaxOptionRunes = 100
+	// quizBlank stands in for the word a QuizKindPhrase question leaves out.
+	quizBlank = "…"
+)
+
+// grammarQuiz builds a question about a word's grammar rather than its
+// translation — which option is one of its forms, what one of its set phrases
+// means, or which word a set phrase is missing — from what GrammarFor has on
+// a pool word of filter's category. The grammar is cached, so a word asked
+// about again costs no lookup. A kind that cannot be built for the word, for
+// want of distractors or otherwise, gives way to the next; the words drawn
+// and not asked about go back to the pool.
+func (b *Business) grammarQuiz(ctx context.Context, filter models.QuizFilter) (*models.QuizQuestion, error) {
+	words := filter
+	words.Grammar = false
+	var unused []models.RandomWord
+	defer func() {
+		for _, w := range unused {
+			b.pool.insert(w)
+		}
+	}()
+	var lastErr error
+	for range grammarQuizDrawAttempts {
+		drawn, err := b.randomCleanWords(ctx, 1, words)
+		if err != nil {
+			return nil, err
+		}
+		word := drawn[0]
+		g, err := b.GrammarFor(ctx, word.Chechen)
+		if err != nil {
+			unused = append(unused, word)
+			return nil, err
+		}
+		if g == nil {
+			unused = append(unused, word)
+			continue
+		}
+
+		kinds := grammarQuizKinds(g)
+		rand.Shuffle(len(kinds), func(i, j int) { kinds[i], kinds[j] = kinds[j], kinds[i] })
+		for _, kind := range kinds {
+			var q *models.QuizQuestion
+			switch kind {
+			case models.QuizKindForm:
+				q, err = b.formQuiz(ctx, word, g)
+			case models.QuizKindIdiom:
+				q, err = b.idiomQuiz(ctx, word, g)
+			case models.QuizKindPhrase:
+				q, err = b.phraseQuiz(ctx, word, g)
+			}
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if q != nil {
+				q.Kind, q.Filter = kind, filter
+				return q, nil
+			}
+		}
+		unused = append(unused, word)
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("no grammar question in %d draws: %w", grammarQuizDrawAttempts, lastErr)
+	}
+	return nil, fmt.Errorf("no word with grammar in %d draws", grammarQuizDrawAttempts)
+}
+
+// grammarQuizKinds lists the question kinds g has material for.
+func grammarQuizKinds(g *models.WordGrammar) []string {
+	var kinds []string
+	if len(quizForms(g)) > 0 {
+		kinds = append(kinds, models.QuizKindForm)
+	}
+	if len(quizIdioms(g)) > 0 {
+		kinds = append(kinds, models.QuizKindIdiom, models.QuizKindPhrase)
+	}
+	return kinds
+}
+
+// quizForms returns g's inflected forms as options can show them, the
+// headword itself left out: «is this a form of X» is no question for X.
+func quizForms(g *models.WordGrammar) []string {
+	head := tools.FuzzyKey(g.Headword)
+	var forms []string
+	for _, f := range g.Forms {
+		f = stripStressMarks(strings.TrimSpace(tools.Clean(f)))
+		if f != "" && tools.FuzzyKey(f) != head && !slices.Contains(forms, f) {
+			forms = append(forms, f)
+		}
+	}
+	return forms
+}
+
+// quizIdioms returns g's set phrases whose meaning fits a poll option.
+func quizIdioms(g *models.WordGrammar) []models.Idiom {
+	var idioms []models.Idiom
+	for _, idiom := range g.Idioms {
+		idiom.Chechen = stripStressMarks(strings.TrimSpace(tools.Clean(idiom.Chechen)))
+		idiom.Russian = stripStressMarks(strings.TrimSpace(tools.Clean(idiom.Russian)))
+		if idiom.Chechen != "" && idiom.Russian != "" && len([]rune(idiom.Russian)) <= quizMaxOptionRunes {
+			idioms = append(idioms, idiom)
+		}
+	}
+	return idioms
+}
+
+// formQuiz asks which option is a form of the headword. The wrong options are
+// pool words chosen to look like the form, and none of them may be another
+// form of the same word.
+func (b *Business) formQuiz(ctx context.Context, word models.RandomWord, g *models.WordGrammar) (*models.QuizQuestion, error) {
+	forms := quizForms(g)
+	form := forms[rand.IntN(len(forms))]
+	answer := models.RandomWord{Chechen: form, Subtype: word.Subtype}
+	distractors, err := b.drawDistractors(ctx, answer, models.QuizFilter{Subtype: word.Subtype}, true, sharedRand{}, append(forms, g.Headword)...)
+	if err != nil {
+		return nil, err
+	}
+	q := buildQuiz(answer, distractors, true, sharedRand{})
+	q.Prompt, q.Reversed = g.Headword, false
+	return q, nil
+}
+
+// idiomQuiz asks what a set phrase means. The other phrases of the same word
+// make the best wrong options — a phrase among single words stands out by its
+// shape alone — so the pool only makes up the shortfall.
+func (b *Business) idiomQuiz(ctx context.Context, word models.RandomWord, g *models.WordGrammar) (*models.QuizQuestion, error) {
+	idioms := quizIdioms(g)
+	i := rand.IntN(len(idioms))
+	answer := models.RandomWord{Chechen: idioms[i].Chechen, Russian: idioms[i].Russian}
+
+	var distractors []models.RandomWord
+	for j, other := range idioms {
+		if j != i && !strings.EqualFold(other.Russian, answer.Russian) && len(distractors) < quizOptionCount-1 {
+			distractors = append(distractors, models.RandomWord{Chechen: other.Chechen, Russian: other.Russian})
+		}
+	}
+	if short := quizOptionCount - 1 - len(distractors); short > 0 {
+		more, err := b.drawDistractors(ctx, answer, models.QuizFilter{}, false, sharedRand{})
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range more {
+			if len(distractors) < quizOptionCount-1 && !slices.ContainsFunc(distractors, func(have models.RandomWord) bool {
+				return sameMeaning(have, d)
+			}) {
+				distractors = append(distractors, d)
+			} else {
+				b.pool.insert(d)
+			}
+		}
+	}
+	if len(distractors) < quizOptionCount-1 {
+		return nil, nil
+	}
+	return buildQuiz(answer, distractors, false, sharedRand{}), nil
+}
+
+// phraseQuiz shows a set phrase with one word left out and its meaning, and
+// asks for the missing word. The word left out is the headword where the
+// phrase has it, in any form — that is the word being learned — and the
+// longest word otherwise. A phrase of one word has nothing to leave out.
+func (b *Business) phraseQuiz(ctx context.Context, word models.RandomWord, g *models.WordGrammar) (*models.QuizQuestion, error) {
+	idioms := quizIdioms(g)
+	idiom := idioms[rand.IntN(len(idioms))]
+	tokens := strings.Fields(idiom.Chechen)
+	if len(tokens) < 2 {
+		return nil, nil
+	}
+
+	known := map[string]bool{tools.FuzzyKey(g.Headword): true}
+	for _, f := range quizForms(g) {
+		known[tools.FuzzyKey(f)] = true
+	}
+	trim := func(t string) string {
+		return strings.TrimFunc(t, func(r rune) bool { return unicode.IsPunct(r) && r != '-' })
+	}
+	blank := -1
+	for i, t := range tokens {
+		if known[tools.FuzzyKey(trim(t))] {
+			blank = i
+			break
+		}
+		if blank < 0 || len([]rune(trim(t))) > len([]rune(trim(tokens[blank]))) {
+			blank = i
+		}
+	}
+	missing := trim(tokens[blank])
+	if missing == "" {
+		return nil, nil
+	}
+	tokens[blank] = strings.Replace(tokens[blank], missing, quizBlank, 1)
+
+	answer := models.RandomWord{Chechen: missing, Subtype: word.Subtype}
+	distractors, err := b.drawDistractors(ctx, answer, models.QuizFilter{}, true, sharedRand{}, missing)
+	if err != nil {
+		return nil, err
+	}
+	q := buildQuiz(answer, distractors, true, sharedRand{})
+	q.Prompt, q.Hint, q.Reversed = strings.Join(tokens, " "), idiom.Russian, false
+	return q, nil
+}
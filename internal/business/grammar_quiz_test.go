@@ -0,0 +1,149 @@
+package business
+
+import (
+	"chetoru/internal/cache"
+	"chetoru/internal/models"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func grammarQuizTestBusiness() *Business {
+	b := &Business{log: logrus.New()}
+	for _, w := range []models.RandomWord{
+		{Chechen: "цӏа", Russian: "дом", Subtype: 2},
+		{Chechen: "дитт", Russian: "дерево", Subtype: 2},
+		{Chechen: "хи", Russian: "вода", Subtype: 2},
+		{Chechen: "ӏаж", Russian: "яблоко", Subtype: 2},
+		{Chechen: "кхор", Russian: "груша", Subtype: 2},
+	} {
+		b.pool.insert(w)
+	}
+	return b
+}
+
+var testGrammar = &models.WordGrammar{
+	Headword: "дог",
+	Forms:    []string{"дог", "дегнан", "дегнах", "дегнаш"},
+	Idioms: []models.Idiom{
+		{Chechen: "дог дика", Russian: "добросердечный"},
+		{Chechen: "дог доха", Russian: "огорчаться"},
+	},
+}
+
+// A form question must offer exactly one form of the word: another form among
+// the wrong options would be a second right answer.
+func TestFormQuiz_OneFormAmongTheOptions(t *testing.T) {
+	stubDoshamAPI(t, http.StatusInternalServerError, ``)
+
+	for range 20 {
+		b := grammarQuizTestBusiness()
+		b.pool.insert(models.RandomWord{Chechen: "дегнаш", Russian: "сердца", Subtype: 2})
+		q, err := b.formQuiz(context.Background(), models.RandomWord{Chechen: "дог", Subtype: 2}, testGrammar)
+		if err != nil {
+			t.Fatalf("formQuiz: %v", err)
+		}
+		if q.Prompt != "дог" || len(q.Options) != quizOptionCount {
+			t.Fatalf("question = %+v, want four options about дог", q)
+		}
+		var forms []string
+		for _, o := range q.Options {
+			if slices.Contains(testGrammar.Forms, o) {
+				forms = append(forms, o)
+			}
+		}
+		if len(forms) != 1 || forms[0] != q.Options[q.CorrectIdx] || forms[0] == "дог" {
+			t.Fatalf("options = %v (correct %d), want one form of дог other than itself, and it correct", q.Options, q.CorrectIdx)
+		}
+	}
+}
+
+func TestIdiomQuiz_AsksTheMeaning(t *testing.T) {
+	stubDoshamAPI(t, http.StatusInternalServerError, ``)
+
+	meaning := map[string]string{}
+	for _, idiom := range testGrammar.Idioms {
+		meaning[idiom.Chechen] = idiom.Russian
+	}
+	for range 20 {
+		b := grammarQuizTestBusiness()
+		q, err := b.idiomQuiz(context.Background(), models.RandomWord{Chechen: "дог"}, testGrammar)
+		if err != nil || q == nil {
+			t.Fatalf("idiomQuiz = %+v, %v; want a question", q, err)
+		}
+		if meaning[q.Prompt] == "" || q.Options[q.CorrectIdx] != meaning[q.Prompt] {
+			t.Fatalf("question = %+v, want a set phrase answered by its meaning", q)
+		}
+		// The word's other phrase is the best wrong option there is.
+		for chechen, russian := range meaning {
+			if chechen != q.Prompt && !slices.Contains(q.Options, russian) {
+				t.Fatalf("options = %v, want the other phrase's meaning %q among them", q.Options, russian)
+			}
+		}
+	}
+}
+
+// The blank goes where the word being learned stands in the phrase, and the
+// completed phrase is the original.
+func TestPhraseQuiz_BlanksTheHeadword(t *testing.T) {
+	stubDoshamAPI(t, http.StatusInternalServerError, ``)
+
+	g := &models.WordGrammar{
+		Headword: "дог",
+		Forms:    []string{"дог", "дегнан"},
+		Idioms:   []models.Idiom{{Chechen: "дегнан хьашт, кхочушдан", Russian: "удовлетворять прихоть"}},
+	}
+	b := grammarQuizTestBusiness()
+	q, err := b.phraseQuiz(context.Background(), models.RandomWord{Chechen: "дог"}, g)
+	if err != nil || q == nil {
+		t.Fatalf("phraseQuiz = %+v, %v; want a question", q, err)
+	}
+	answer := q.Options[q.CorrectIdx]
+	if answer != "дегнан" || q.Prompt != "… хьашт, кхочушдан" || q.Hint != "удовлетворять прихоть" {
+		t.Fatalf("question = %+v, want дегнан blanked with the meaning as hint", q)
+	}
+	if got := strings.Replace(q.Prompt, quizBlank, answer, 1); got != g.Idioms[0].Chechen {
+		t.Fatalf("completed phrase = %q, want %q", got, g.Idioms[0].Chechen)
+	}
+
+	// A phrase of one word has nothing to leave out.
+	single := &models.WordGrammar{Headword: "дог", Idioms: []models.Idiom{{Chechen: "дог", Russian: "сердце"}}}
+	if q, err := b.phraseQuiz(context.Background(), models.RandomWord{Chechen: "дог"}, single); err != nil || q != nil {
+		t.Fatalf("phraseQuiz(one word) = %+v, %v; want no question", q, err)
+	}
+}
+
+// A meaning too long for a poll option must not be asked about at all.
+func TestQuizIdioms_DropsMeaningsTooLongForAPoll(t *testing.T) {
+	g := &models.WordGrammar{Idioms: []models.Idiom{
+		{Chechen: "дог дика", Russian: "добросердечный"},
+		{Chechen: "дог доха", Russian: strings.Repeat("очень ", 20)},
+	}}
+	if got := quizIdioms(g); len(got) != 1 || got[0].Chechen != "дог дика" {
+		t.Fatalf("quizIdioms = %+v, want only the short meaning", got)
+	}
+	if kinds := grammarQuizKinds(&models.WordGrammar{Headword: "дог", Forms: []string{"дог"}}); len(kinds) != 0 {
+		t.Fatalf("kinds = %v, want none for a word whose only form is itself", kinds)
+	}
+}
+
+// Words without grammar are not asked about, and go back to the pool for
+// the questions that can use them.
+func TestGrammarQuiz_GivesUnusedWordsBack(t *testing.T) {
+	stubDoshamAPI(t, http.StatusOK, `{"data":{"find":[]}}`)
+	b := grammarQuizTestBusiness()
+	b.cache = cache.NewCache("127.0.0.1:1", "")
+	before := b.pool.size()
+
+	if q, err := b.grammarQuiz(context.Background(), models.QuizFilter{Grammar: true}); err == nil {
+		t.Fatalf("grammarQuiz = %+v, want an error with no grammar anywhere", q)
+	}
+	b.WaitBackground()
+	if got := b.pool.size(); got != before {
+		t.Fatalf("pool size = %d after a failed grammar question, want the %d words back", got, before)
+	}
+}
@@ -0,0 +1,45 @@
+package business
+
+import (
+	"chetoru/internal/models"
+	"context"
+)
+
+// mirrorSearchLimit bounds an offline substring search. dosham's answer for
+// a two-letter query runs to a few hundred pairs and rankAndDedup cuts short
+// queries to ten anyway, so this only has to be generous enough that the
+// truncation never drops a pair ranking would have kept.
+const mirrorSearchLimit = 500
+
+// SetOfflineMode switches Translate to answer from the local mirror only. It
+// is the admin's switch for a planned dosham outage, or for running the bot
+// against a crawled database with no network at all.
+func (b *Business) SetOfflineMode(enabled bool) {
+	b.offline.Store(enabled)
+}
+
+func (b *Business) OfflineMode() bool {
+	return b.offline.Load()
+}
+
+// loadMirrorTranslations answers a query the way dosham's `find` would, from
+// the pairs cmd/mirror and earlier lookups stored: the same substring match,
+// the same orientation and the same escaping as fetchTranslationsFromAPI, then
+// the same ranking. That is what keeps the card identical whichever side
+// served it. It runs where the dosham call would have, after the exact-word
+// local path, so a word the table already knows renders the same with dosham
+// up, down or switched off.
+func (b *Business) loadMirrorTranslations(ctx context.Context, word string) []models.TranslationPairs {
+	if b.dictRepo == nil {
+		return nil
+	}
+	pairs, err := localSource{b.dictRepo}.Search(ctx, word)
+	if err != nil {
+		b.log.Printf("mirror lookup failed for %q: %v\n", word, err)
+		return nil
+	}
+	for i := range pairs {
+		pairs[i].Provenance = SourceLocal
+	}
+	return rankAndDedup(pairs, word)
+}
@@ -0,0 +1,171 @@
+package business
+
+import (
+	"chetoru/internal/cache"
+	"chetoru/internal/models"
+	"chetoru/internal/repository"
+	"chetoru/migrations"
+	"chetoru/pkg/tools"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	_ "modernc.org/sqlite"
+)
+
+// yablFind is dosham's answer for «ябло»: a substring hit on three headwords,
+// one of them a Chechen entry matched through its Russian gloss, and a
+// collocation — enough shapes that a reordered or reoriented mirror would show
+// up on the card.
+const yablFind = `[
+	{"entryId":"e1","content":"Яблоко","type":"WORD","rate":100,"translations":[
+		{"translationId":"t1","content":"Ӏаж","languageCode":"ce"},
+		{"translationId":"t2","content":"Ӏежа","languageCode":"ce"}]},
+	{"entryId":"e2","content":"Ӏаж","type":"WORD","subtype":2,"rate":10000,"translations":[
+		{"translationId":"t3","content":"яблоко","languageCode":"ru"}]},
+	{"entryId":"e3","content":"Яблоня","type":"WORD","rate":16,"translations":[
+		{"translationId":"t4","content":"Ӏежан дитт","languageCode":"ce"}]},
+	{"entryId":"e4","content":"яблочный сок","type":"TEXT","rate":16,"translations":[
+		{"translationId":"t5","content":"Ӏежан мутта","languageCode":"ce"}]}
+]`
+
+func newMirrorTestRepo(t *testing.T) *repository.Repository {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := migrations.Up(db); err != nil {
+		t.Fatalf("migrations.Up: %v", err)
+	}
+	return repository.NewRepository(db)
+}
+
+// The mirror exists so an outage is invisible to the user: the card served
+// from SQLite must be the card dosham would have produced, not merely a card
+// with the same words in it.
+func TestTranslate_MirrorCardMatchesLiveCard(t *testing.T) {
+	var down atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"find":%s}}`, yablFind)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	repo := newMirrorTestRepo(t)
+	live := &Business{log: logrus.New(), dictRepo: repo, cache: cache.NewCache("127.0.0.1:1", "")}
+	livePairs, err := live.Translate("ябло")
+	if err != nil {
+		t.Fatalf("live Translate: %v", err)
+	}
+	live.WaitBackground()
+	want := tools.FormatCard("ябло", livePairs)
+	if want == "" {
+		t.Fatal("live card is empty")
+	}
+
+	down.Store(true)
+	outage := &Business{log: logrus.New(), dictRepo: repo, cache: cache.NewCache("127.0.0.1:1", "")}
+	got, err := outage.Translate("ябло")
+	if err != nil {
+		t.Fatalf("Translate during outage: %v, want the mirror's answer", err)
+	}
+	if card := tools.FormatCard("ябло", got); card != want {
+		t.Fatalf("outage card differs from live card:\n--- live\n%s\n--- mirror\n%s", want, card)
+	}
+
+	offline := &Business{log: logrus.New(), dictRepo: repo, cache: cache.NewCache("127.0.0.1:1", "")}
+	offline.SetOfflineMode(true)
+	got, err = offline.Translate("ябло")
+	if err != nil {
+		t.Fatalf("offline Translate: %v", err)
+	}
+	if card := tools.FormatCard("ябло", got); card != want {
+		t.Fatalf("offline card differs from live card:\n--- live\n%s\n--- offline\n%s", want, card)
+	}
+}
+
+func TestTranslate_OfflineModeNeverCallsDosham(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fmt.Fprint(w, `{"data":{"find":[]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	b := &Business{log: logrus.New(), dictRepo: newMirrorTestRepo(t), cache: cache.NewCache("127.0.0.1:1", "")}
+	b.SetOfflineMode(true)
+
+	got, err := b.Translate("нетслова")
+	if err != nil || len(got) != 0 {
+		t.Fatalf("Translate = %+v, %v; want an empty answer from the mirror", got, err)
+	}
+	if b.RecheckTranslation("нетслова") {
+		t.Fatal("RecheckTranslation found a word offline")
+	}
+	if g, err := b.GrammarFor(t.Context(), "нетслова"); g != nil || err != nil {
+		t.Fatalf("GrammarFor = %+v, %v; want nothing offline", g, err)
+	}
+	b.WaitBackground()
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("dosham was called %d times in offline mode", n)
+	}
+}
+
+// /random and the quiz pool draw from the mirror offline, as lookups do.
+func TestRandomCleanWords_OfflineModeNeverCallsDosham(t *testing.T) {
+	var calls atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fmt.Fprint(w, `{"data":{"randomEntries":[]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	repo := newMirrorTestRepo(t)
+	// Enough that the draw leaves the pool above poolRefillBelow.
+	for i := range poolRefillBelow + 2 {
+		if _, _, err := repo.InsertTranslationPair(t.Context(), repository.TranslationPair{
+			OriginalRaw: fmt.Sprintf("дош%c", 'а'+i), OriginalClean: fmt.Sprintf("дош%c", 'а'+i), OriginalLang: "CHE",
+			TranslationRaw: fmt.Sprintf("слово%c", 'а'+i), TranslationClean: fmt.Sprintf("слово%c", 'а'+i), TranslationLang: "RUS",
+			EntryType: "WORD", Source: "api",
+		}); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+	b := &Business{log: logrus.New(), dictRepo: repo, cache: cache.NewCache("127.0.0.1:1", "")}
+	b.SetOfflineMode(true)
+
+	words, err := b.randomCleanWords(t.Context(), 1, models.QuizFilter{})
+	if err != nil || len(words) != 1 {
+		t.Fatalf("randomCleanWords = %+v, %v; want a word from the mirror", words, err)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("dosham was called %d times in offline mode", n)
+	}
+}
+
+// An outage with nothing mirrored is still an outage, not an empty answer.
+func TestTranslate_OutageWithEmptyMirrorIsAnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	b := &Business{log: logrus.New(), dictRepo: newMirrorTestRepo(t), cache: cache.NewCache("127.0.0.1:1", "")}
+	if _, err := b.Translate("ябло"); err == nil {
+		t.Fatal("Translate returned no error with dosham down and the mirror empty")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand/v2"
+	"slices"
 	"strings"
 	"sync"
 )
@@ -13,13 +14,21 @@ const (
 	poolFetchSize   = 40
 	poolRefillBelow = 12
 	poolFillTries   = 3
+	// A filtered draw fills more times: a part of speech is a fraction of
+	// each batch, and a question takes four words of it.
+	poolFilteredFillTries = 8
+	// poolMaxSize caps the pool. Filtered draws leave the other categories
+	// behind, and without a cap a run of verb quizzes would grow it forever.
+	poolMaxSize = 400
 )
 
 // wordPool holds prefetched clean Chechen→Russian pairs so /random and /quiz
 // answer instantly instead of paying a live randomEntries call each time.
 // Words are consumed on draw and the pool refills in the background. All
 // pooled pairs are mutually distinct on both sides, so any draw is directly
-// usable as quiz options.
+// usable as quiz options. Draws can be narrowed to one category (a
+// models.QuizFilter), which partitions the pool without splitting it: every
+// word stays available to every draw that its category matches.
 type wordPool struct {
 	mu        sync.Mutex
 	words     []models.RandomWord
@@ -32,7 +41,21 @@ func (p *wordPool) size() int {
 	return len(p.words)
 }
 
-// insert adds a pair unless either side duplicates a pooled entry.
+// count returns how many pooled pairs match f.
+func (p *wordPool) count(f models.QuizFilter) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, w := range p.words {
+		if f.Matches(w) {
+			n++
+		}
+	}
+	return n
+}
+
+// insert adds a pair unless either side duplicates a pooled entry. A full
+// pool makes room by dropping its oldest pair.
 func (p *wordPool) insert(w models.RandomWord) {
 	wordKey := strings.ToLower(w.Chechen)
 	meaningKey := strings.ToLower(w.Russian)
@@ -44,32 +67,55 @@ func (p *wordPool) insert(w models.RandomWord) {
 			return
 		}
 	}
+	if len(p.words) >= poolMaxSize {
+		p.words = slices.Delete(p.words, 0, 1)
+	}
 	p.words = append(p.words, w)
 }
 
-// draw removes and returns up to n random pairs.
-func (p *wordPool) draw(n int) []models.RandomWord {
+// draw removes and returns up to n random pairs matching f.
+func (p *wordPool) draw(n int, f models.QuizFilter) []models.RandomWord {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	if n > len(p.words) {
-		n = len(p.words)
+	var matching []int
+	for i, w := range p.words {
+		if f.Matches(w) {
+			matching = append(matching, i)
+		}
 	}
+	if n > len(matching) {
+		n = len(matching)
+	}
+	rand.Shuffle(len(matching), func(i, j int) { matching[i], matching[j] = matching[j], matching[i] })
+	picked := matching[:n]
 	out := make([]models.RandomWord, 0, n)
-	for range n {
-		i := rand.IntN(len(p.words))
+	for _, i := range picked {
 		out = append(out, p.words[i])
-		p.words[i] = p.words[len(p.words)-1]
-		p.words = p.words[:len(p.words)-1]
+	}
+	// Remove from the back so the indices still to go stay valid.
+	slices.Sort(picked)
+	for _, i := range slices.Backward(picked) {
+		p.words = slices.Delete(p.words, i, i+1)
 	}
 	return out
 }
 
-// startRefill marks the pool as refilling when it is below threshold and no
-// refill is already running. The caller must call endRefill when done.
-func (p *wordPool) startRefill(threshold int) bool {
+// startRefill marks the pool as refilling when fewer than threshold pairs
+// match f and no refill is already running. The caller must call endRefill
+// when done.
+func (p *wordPool) startRefill(threshold int, f models.QuizFilter) bool {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	if p.refilling || len(p.words) >= threshold {
+	if p.refilling {
+		return false
+	}
+	n := 0
+	for _, w := range p.words {
+		if f.Matches(w) {
+			n++
+		}
+	}
+	if n >= threshold {
 		return false
 	}
 	p.refilling = true
@@ -102,7 +148,7 @@ func (b *Business) fillPool(ctx context.Context) error {
 // WarmWordPool fills the pool ahead of demand, so the first /random or /quiz
 // after a deploy doesn't pay the API round trips.
 func (b *Business) WarmWordPool(ctx context.Context) {
-	if !b.pool.startRefill(poolRefillBelow) {
+	if !b.pool.startRefill(poolRefillBelow, models.QuizFilter{}) {
 		return
 	}
 	defer b.pool.endRefill()
@@ -111,13 +157,18 @@ func (b *Business) WarmWordPool(ctx context.Context) {
 	}
 }
 
-// randomCleanWords draws n mutually distinct pairs, filling the pool
-// synchronously only when it cannot cover the request, and kicks off a
-// background refill when the pool runs low.
-func (b *Business) randomCleanWords(ctx context.Context, n int) ([]models.RandomWord, error) {
+// randomCleanWords draws n mutually distinct pairs matching f, filling the
+// pool synchronously only when it cannot cover the request, and kicks off a
+// background refill when the pool runs low on f's category — a pool full of
+// other categories is low for a filtered draw all the same.
+func (b *Business) randomCleanWords(ctx context.Context, n int, f models.QuizFilter) ([]models.RandomWord, error) {
+	tries := poolFillTries
+	if f != (models.QuizFilter{}) {
+		tries = poolFilteredFillTries
+	}
 	var lastErr error
-	for range poolFillTries {
-		if b.pool.size() >= n {
+	for range tries {
+		if b.pool.count(f) >= n {
 			break
 		}
 		if err := b.fillPool(ctx); err != nil {
@@ -125,7 +176,7 @@ func (b *Business) randomCleanWords(ctx context.Context, n int) ([]models.Random
 		}
 	}
 
-	words := b.pool.draw(n)
+	words := b.pool.draw(n, f)
 	if len(words) < n {
 		if lastErr != nil {
 			return nil, lastErr
@@ -133,11 +184,17 @@ func (b *Business) randomCleanWords(ctx context.Context, n int) ([]models.Random
 		return nil, fmt.Errorf("not enough clean random words: have %d, need %d", len(words), n)
 	}
 
-	if b.pool.startRefill(poolRefillBelow) {
+	if b.pool.startRefill(poolRefillBelow, f) {
 		go func() {
 			defer b.pool.endRefill()
-			if err := b.fillPool(context.Background()); err != nil {
-				b.log.Printf("word pool refill failed: %v\n", err)
+			for range tries {
+				if err := b.fillPool(context.Background()); err != nil {
+					b.log.Printf("word pool refill failed: %v\n", err)
+					return
+				}
+				if b.pool.count(f) >= poolRefillBelow {
+					return
+				}
 			}
 		}()
 	}
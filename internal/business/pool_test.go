@@ -3,6 +3,7 @@ package business
 import (
 	"chetoru/internal/models"
 	"context"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -48,7 +49,7 @@ func TestWordPool_DrawConsumes(t *testing.T) {
 	p.insert(models.RandomWord{Chechen: "в", Russian: "г"})
 	p.insert(models.RandomWord{Chechen: "д", Russian: "е"})
 
-	got := p.draw(2)
+	got := p.draw(2, models.QuizFilter{})
 	if len(got) != 2 || p.size() != 1 {
 		t.Fatalf("draw(2) = %d items, %d left; want 2 and 1", len(got), p.size())
 	}
@@ -57,23 +58,60 @@ func TestWordPool_DrawConsumes(t *testing.T) {
 	}
 
 	// Over-draw returns what's left, never errors.
-	if rest := p.draw(5); len(rest) != 1 || p.size() != 0 {
+	if rest := p.draw(5, models.QuizFilter{}); len(rest) != 1 || p.size() != 0 {
 		t.Fatalf("over-draw = %d items, %d left; want 1 and 0", len(rest), p.size())
 	}
 }
 
+func TestWordPool_FilteredDrawLeavesTheRest(t *testing.T) {
+	var p wordPool
+	p.insert(models.RandomWord{Chechen: "дан", Russian: "прийти", Subtype: 1})
+	p.insert(models.RandomWord{Chechen: "цӏа", Russian: "дом", Subtype: 2, Rate: models.RateAcademic})
+	p.insert(models.RandomWord{Chechen: "дитт", Russian: "дерево", Subtype: 2})
+	p.insert(models.RandomWord{Chechen: "ваха", Russian: "жить", Subtype: 1, Rate: models.RateAcademic})
+
+	nouns := models.QuizFilter{Subtype: 2}
+	if got := p.count(nouns); got != 2 {
+		t.Fatalf("count(nouns) = %d, want 2", got)
+	}
+	got := p.draw(5, nouns)
+	if len(got) != 2 || got[0].Subtype != 2 || got[1].Subtype != 2 {
+		t.Fatalf("draw(nouns) = %+v, want the two nouns", got)
+	}
+	if p.size() != 2 {
+		t.Fatalf("pool size = %d after drawing nouns, want the 2 verbs left", p.size())
+	}
+	academicVerbs := p.draw(5, models.QuizFilter{Subtype: 1, Rate: models.RateAcademic})
+	if len(academicVerbs) != 1 || academicVerbs[0].Chechen != "ваха" {
+		t.Fatalf("draw(academic verbs) = %+v, want ваха only", academicVerbs)
+	}
+}
+
+func TestWordPool_FullPoolDropsOldest(t *testing.T) {
+	var p wordPool
+	for i := range poolMaxSize + 1 {
+		p.insert(models.RandomWord{Chechen: fmt.Sprint("ч", i), Russian: fmt.Sprint("р", i)})
+	}
+	if p.size() != poolMaxSize {
+		t.Fatalf("pool size = %d, want it capped at %d", p.size(), poolMaxSize)
+	}
+	if p.words[0].Chechen != "ч1" {
+		t.Fatalf("oldest pair = %q, want ч0 dropped", p.words[0].Chechen)
+	}
+}
+
 func TestWordPool_RefillGate(t *testing.T) {
 	var p wordPool
 	p.insert(models.RandomWord{Chechen: "а", Russian: "б"})
 
-	if !p.startRefill(5) {
+	if !p.startRefill(5, models.QuizFilter{}) {
 		t.Fatal("below threshold and idle: refill should start")
 	}
-	if p.startRefill(5) {
+	if p.startRefill(5, models.QuizFilter{}) {
 		t.Fatal("refill already running: second start must be refused")
 	}
 	p.endRefill()
-	if !p.startRefill(5) {
+	if !p.startRefill(5, models.QuizFilter{}) {
 		t.Fatal("after endRefill a new refill should start")
 	}
 	p.endRefill()
@@ -81,7 +119,11 @@ func TestWordPool_RefillGate(t *testing.T) {
 	for i := range 5 {
 		p.insert(models.RandomWord{Chechen: string(rune('а' + i + 1)), Russian: string(rune('я' - i))})
 	}
-	if p.startRefill(5) {
+	if p.startRefill(5, models.QuizFilter{}) {
 		t.Fatal("at/above threshold: refill should not start")
 	}
+	if !p.startRefill(5, models.QuizFilter{Subtype: 2}) {
+		t.Fatal("a full pool with none of the filter's category: refill should start")
+	}
+	p.endRefill()
 }
@@ -3,12 +3,49 @@ package business
 import (
 	"chetoru/internal/models"
 
+	"cmp"
 	"context"
+	"fmt"
+	"math"
 	"math/rand/v2"
+	"slices"
 	"strings"
 )
 
-const quizOptionCount = 4
+const (
+	quizOptionCount = 4
+	// quizCandidateCount is how many same-category words the distractor
+	// selector chooses among, when the pool has them without a fetch.
+	quizCandidateCount = 12
+	// DefaultQuizDifficulty is the distractor difficulty NewBusiness starts
+	// with; see SetQuizDifficulty.
+	DefaultQuizDifficulty = 0.5
+)
+
+// quizRand is the randomness a question is built with: math/rand's shared
+// source for /quiz, a seeded one where the same words must come out the same
+// way (see DailyChallenge).
+type quizRand interface {
+	IntN(n int) int
+	Shuffle(n int, swap func(i, j int))
+}
+
+// sharedRand is quizRand over math/rand's shared source.
+type sharedRand struct{}
+
+func (sharedRand) IntN(n int) int                     { return rand.IntN(n) }
+func (sharedRand) Shuffle(n int, swap func(i, j int)) { rand.Shuffle(n, swap) }
+
+// SetQuizDifficulty sets how hard quiz distractors are, from 0 — any word of
+// the question's category — to 1 — always the words most like the answer.
+// Out-of-range values are clamped; NaN, which no clamp catches, leaves the
+// difficulty as it was.
+func (b *Business) SetQuizDifficulty(d float64) {
+	if math.IsNaN(d) {
+		return
+	}
+	b.quizDifficulty = min(max(d, 0), 1)
+}
 
 // GenerateQuiz builds a multiple-choice question with one correct option and
 // several plausible distractors. Pairs come from the prefetched word pool —
@@ -16,13 +53,185 @@ const quizOptionCount = 4
 // Half the questions reverse direction (Russian prompt, Chechen options):
 // production recall is harder and more valuable than recognition. Active
 // recall practice is the /quiz feature's engine.
-func (b *Business) GenerateQuiz(ctx context.Context) (*models.QuizQuestion, error) {
-	pairs, err := b.randomCleanWords(ctx, quizOptionCount)
+//
+// The question word is drawn from filter's category, and the distractors from
+// the question word's: with a verb among three nouns, «-а»-final infinitives
+// give the answer away to someone who knows no Chechen at all. A word whose
+// part of speech the source does not record gets distractors from the filter
+// alone, as does one whose category the pool cannot fill.
+func (b *Business) GenerateQuiz(ctx context.Context, filter models.QuizFilter) (*models.QuizQuestion, error) {
+	if filter.Grammar {
+		return b.grammarQuiz(ctx, filter)
+	}
+	words, err := b.randomCleanWords(ctx, 1, filter)
 	if err != nil {
 		return nil, err
 	}
+	question := words[0]
 
+	category := filter
+	if category.Subtype == 0 {
+		category.Subtype = question.Subtype
+	}
 	reversed := rand.IntN(2) == 0
+	distractors, err := b.drawDistractors(ctx, question, category, reversed, sharedRand{})
+	if err != nil && category != filter {
+		distractors, err = b.drawDistractors(ctx, question, filter, reversed, sharedRand{})
+	}
+	if err != nil {
+		b.pool.insert(question) // unused; let the next question have it
+		return nil, err
+	}
+
+	q := buildQuiz(question, distractors, reversed, sharedRand{})
+	q.Filter = filter
+	return q, nil
+}
+
+// LearnQuiz builds a /learn question about one given word, with distractors
+// drawn from the word pool like /quiz's.
+func (b *Business) LearnQuiz(ctx context.Context, word models.RandomWord, reversed bool) (*models.QuizQuestion, error) {
+	distractors, err := b.drawDistractors(ctx, word, models.QuizFilter{}, reversed, sharedRand{})
+	if err != nil {
+		return nil, err
+	}
+	return buildQuiz(word, distractors, reversed, sharedRand{}), nil
+}
+
+// drawDistractors draws the wrong options for a question about word from the
+// pool's words matching f, then lets pickDistractors choose among them. A
+// draw that collides with the word on either side is set aside, since two
+// right answers make a question that grades a correct reader wrong; so is one
+// whose Chechen side is among avoid, other spellings the question counts as
+// right. Every word drawn and not used goes back to the pool. rng makes the
+// choice among the candidates.
+func (b *Business) drawDistractors(ctx context.Context, word models.RandomWord, f models.QuizFilter, reversed bool, rng quizRand, avoid ...string) ([]models.RandomWord, error) {
+	need := quizOptionCount - 1
+	var candidates, collided []models.RandomWord
+	giveBack := func(words []models.RandomWord) {
+		for _, w := range words {
+			b.pool.insert(w)
+		}
+	}
+	defer func() { giveBack(collided) }()
+
+	take := func(pairs []models.RandomWord) {
+		for _, p := range pairs {
+			if sameMeaning(p, word) || slices.ContainsFunc(avoid, func(a string) bool {
+				return strings.EqualFold(a, p.Chechen)
+			}) || slices.ContainsFunc(candidates, func(have models.RandomWord) bool {
+				// Draws from different rounds are not distinct from each other.
+				return sameMeaning(p, have)
+			}) {
+				collided = append(collided, p)
+				continue
+			}
+			candidates = append(candidates, p)
+		}
+	}
+	for range poolFillTries {
+		if len(candidates) >= need {
+			break
+		}
+		pairs, err := b.randomCleanWords(ctx, need-len(candidates), f)
+		if err != nil {
+			giveBack(candidates)
+			return nil, err
+		}
+		take(pairs)
+	}
+	if len(candidates) < need {
+		giveBack(candidates)
+		return nil, fmt.Errorf("not enough distractors for %q", word.Chechen)
+	}
+	// Widen the choice with whatever else the pool holds of the category,
+	// without fetching for it: a quiz must not wait on choosing better.
+	take(b.pool.draw(quizCandidateCount-len(candidates), f))
+
+	picked, rest := pickDistractors(word, candidates, need, reversed, b.quizDifficulty, rng)
+	giveBack(rest)
+	return picked, nil
+}
+
+// pickDistractors chooses need wrong options for a question about answer
+// among candidates and returns them with the candidates left over. It ranks
+// the candidates by optionSimilarity and picks at random from the best ones:
+// at difficulty 1 from exactly the need most similar, at 0 from all of them,
+// in between from a window that narrows as difficulty grows. Randomness stays
+// at every level, so a word does not always come with the same decoys.
+func pickDistractors(answer models.RandomWord, candidates []models.RandomWord, need int, reversed bool, difficulty float64, rng quizRand) (picked, rest []models.RandomWord) {
+	ranked := slices.Clone(candidates)
+	rng.Shuffle(len(ranked), func(i, j int) { ranked[i], ranked[j] = ranked[j], ranked[i] })
+	score := make(map[models.RandomWord]float64, len(ranked))
+	for _, c := range ranked {
+		score[c] = optionSimilarity(answer, c, reversed)
+	}
+	slices.SortStableFunc(ranked, func(a, b models.RandomWord) int {
+		return cmp.Compare(score[b], score[a])
+	})
+
+	need = min(need, len(ranked))
+	window := need + int(math.Round((1-difficulty)*float64(len(ranked)-need)))
+	choice := ranked[:window]
+	rng.Shuffle(len(choice), func(i, j int) { choice[i], choice[j] = choice[j], choice[i] })
+	return choice[:need], slices.Concat(choice[need:], ranked[window:])
+}
+
+// optionSimilarity scores how plausible candidate is as a wrong option beside
+// answer, comparing the sides the question shows as options. The part of
+// speech outweighs everything else together: a verb among nouns is wrong on
+// sight whatever its spelling. Then come length, and shared first and last
+// letters — the beginnings and endings a guesser pattern-matches on, and in
+// Chechen the endings carry class and case.
+func optionSimilarity(answer, candidate models.RandomWord, reversed bool) float64 {
+	side := func(w models.RandomWord) []rune {
+		if reversed {
+			return []rune(strings.ToLower(w.Chechen))
+		}
+		return []rune(strings.ToLower(w.Russian))
+	}
+	a, c := side(answer), side(candidate)
+
+	var score float64
+	switch {
+	case answer.Subtype == 0 || candidate.Subtype == 0:
+		// Unknown: no evidence either way.
+	case answer.Subtype == candidate.Subtype:
+		score += similarPOSWeight
+	default:
+		score -= similarPOSWeight
+	}
+	if longer := max(len(a), len(c)); longer > 0 {
+		score += similarLengthWeight * (1 - math.Abs(float64(len(a)-len(c)))/float64(longer))
+	}
+	for i := 0; i < similarAffixRunes && i < len(a) && i < len(c) && a[i] == c[i]; i++ {
+		score += similarAffixWeight
+	}
+	for i := 1; i <= similarAffixRunes && i <= len(a) && i <= len(c) && a[len(a)-i] == c[len(c)-i]; i++ {
+		score += similarAffixWeight
+	}
+	return score
+}
+
+// optionSimilarity weights. The length and affix terms top out at
+// similarLengthWeight + 2·similarAffixRunes·similarAffixWeight = 5, below
+// similarPOSWeight, so no spelling makes up for the wrong part of speech.
+const (
+	similarPOSWeight    = 6
+	similarLengthWeight = 2
+	similarAffixRunes   = 3
+	similarAffixWeight  = 0.5
+)
+
+// sameMeaning reports whether two pairs share either side, the test the pool
+// applies to keep its own pairs mutually distinct.
+func sameMeaning(a, b models.RandomWord) bool {
+	return strings.EqualFold(a.Chechen, b.Chechen) || strings.EqualFold(a.Russian, b.Russian)
+}
+
+// buildQuiz asks about question, with the distractors' matching sides as
+// the wrong options, shuffled by rng.
+func buildQuiz(question models.RandomWord, distractors []models.RandomWord, reversed bool, rng quizRand) *models.QuizQuestion {
 	side := func(p models.RandomWord) string {
 		if reversed {
 			return p.Chechen
@@ -30,21 +239,21 @@ func (b *Business) GenerateQuiz(ctx context.Context) (*models.QuizQuestion, erro
 		return p.Russian
 	}
 
-	question := pairs[0]
 	prompt := question.Russian
 	if !reversed {
 		prompt = question.Chechen
 	}
 
 	options := make([]string, 0, quizOptionCount)
-	for _, p := range pairs {
-		options = append(options, side(p))
+	options = append(options, side(question))
+	for _, p := range distractors {
 		if len(options) == quizOptionCount {
 			break
 		}
+		options = append(options, side(p))
 	}
 
-	rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+	rng.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
 
 	correctIdx := 0
 	for i, opt := range options {
@@ -59,7 +268,7 @@ func (b *Business) GenerateQuiz(ctx context.Context) (*models.QuizQuestion, erro
 		Options:    options,
 		CorrectIdx: correctIdx,
 		Reversed:   reversed,
-	}, nil
+	}
 }
 
 // isCleanMeaning reports whether a Russian gloss is a concise standalone answer
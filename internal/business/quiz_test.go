@@ -2,8 +2,12 @@ package business
 
 import (
 	"chetoru/internal/models"
+	"chetoru/internal/repository"
 	"context"
+	"fmt"
+	"math"
 	"net/http"
+	"slices"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -32,7 +36,7 @@ func TestGenerateQuiz_PromptMatchesCorrectOption(t *testing.T) {
 		for _, w := range words {
 			b.pool.insert(w)
 		}
-		q, err := b.GenerateQuiz(context.Background())
+		q, err := b.GenerateQuiz(context.Background(), models.QuizFilter{})
 		if err != nil {
 			t.Fatalf("GenerateQuiz: %v", err)
 		}
@@ -57,3 +61,228 @@ func TestGenerateQuiz_PromptMatchesCorrectOption(t *testing.T) {
 		t.Fatalf("both directions should appear in 30 runs (forward=%v reversed=%v)", sawForward, sawReversed)
 	}
 }
+
+func TestGenerateQuiz_DistractorsShareTheCategory(t *testing.T) {
+	stubDoshamAPI(t, http.StatusInternalServerError, ``)
+
+	verbs := []models.RandomWord{
+		{Chechen: "дан", Russian: "прийти", Subtype: 1},
+		{Chechen: "ваха", Russian: "жить", Subtype: 1},
+		{Chechen: "деша", Russian: "читать", Subtype: 1},
+		{Chechen: "яздан", Russian: "писать", Subtype: 1},
+	}
+	nouns := []models.RandomWord{
+		{Chechen: "цӏа", Russian: "дом", Subtype: 2},
+		{Chechen: "дитт", Russian: "дерево", Subtype: 2},
+		{Chechen: "хи", Russian: "вода", Subtype: 2},
+		{Chechen: "ӏаж", Russian: "яблоко", Subtype: 2},
+	}
+	category := map[string]int{}
+	for _, w := range append(slices.Clone(verbs), nouns...) {
+		category[w.Chechen], category[w.Russian] = w.Subtype, w.Subtype
+	}
+
+	b := &Business{log: logrus.New()}
+	for _, filter := range []models.QuizFilter{{}, {Subtype: 1}, {Subtype: 2}} {
+		for range 20 {
+			b.pool = wordPool{}
+			for _, w := range append(slices.Clone(verbs), nouns...) {
+				b.pool.insert(w)
+			}
+			q, err := b.GenerateQuiz(context.Background(), filter)
+			if err != nil {
+				t.Fatalf("GenerateQuiz(%+v): %v", filter, err)
+			}
+			want := category[q.Prompt]
+			if filter.Subtype != 0 && want != filter.Subtype {
+				t.Fatalf("GenerateQuiz(%+v) asked about %q, outside the filter", filter, q.Prompt)
+			}
+			for _, o := range q.Options {
+				if category[o] != want {
+					t.Fatalf("GenerateQuiz(%+v): options %v mix categories with prompt %q", filter, q.Options, q.Prompt)
+				}
+			}
+			if q.Filter != filter {
+				t.Fatalf("question filter = %+v, want %+v", q.Filter, filter)
+			}
+		}
+	}
+}
+
+func TestLearnQuiz_SkipsDistractorsThatCollideWithTheWord(t *testing.T) {
+	stubDoshamAPI(t, http.StatusInternalServerError, ``)
+
+	b := &Business{log: logrus.New()}
+	for _, w := range []models.RandomWord{
+		{Chechen: "хен", Russian: "дерево"}, // another word for the learned meaning
+		{Chechen: "цӏа", Russian: "дом"},
+		{Chechen: "ӏаж", Russian: "яблоко"},
+		{Chechen: "кхор", Russian: "груша"},
+		{Chechen: "хи", Russian: "вода"},
+	} {
+		b.pool.insert(w)
+	}
+
+	word := models.RandomWord{Chechen: "дитт", Russian: "дерево"}
+	q, err := b.LearnQuiz(context.Background(), word, false)
+	if err != nil {
+		t.Fatalf("LearnQuiz: %v", err)
+	}
+	if q.Prompt != "дитт" || q.Options[q.CorrectIdx] != "дерево" {
+		t.Fatalf("question = %+v, want дитт → дерево", q)
+	}
+	seen := map[string]bool{}
+	for _, o := range q.Options {
+		if seen[o] {
+			t.Fatalf("options = %v, want no duplicate answers", q.Options)
+		}
+		seen[o] = true
+	}
+}
+
+func TestOptionSimilarity_PartOfSpeechOutweighsSpelling(t *testing.T) {
+	answer := models.RandomWord{Chechen: "кехат", Russian: "бумага", Subtype: 2}
+	lookalikeVerb := models.RandomWord{Chechen: "кхехка", Russian: "бурлить", Subtype: 1}
+	plainNoun := models.RandomWord{Chechen: "хи", Russian: "вода", Subtype: 2}
+	for _, reversed := range []bool{false, true} {
+		if v, n := optionSimilarity(answer, lookalikeVerb, reversed), optionSimilarity(answer, plainNoun, reversed); v >= n {
+			t.Errorf("reversed=%v: verb scores %v, noun %v; want the noun ahead", reversed, v, n)
+		}
+	}
+
+	// Within a part of speech, spelling decides.
+	close := models.RandomWord{Chechen: "кехаташ", Russian: "бумажник", Subtype: 2}
+	if c, p := optionSimilarity(answer, close, true), optionSimilarity(answer, plainNoun, true); c <= p {
+		t.Errorf("кехаташ scores %v, хи %v; want the lookalike ahead", c, p)
+	}
+}
+
+// posCollisionRate picks distractors for a noun among candidates that are
+// mostly verbs, over and over, and returns the share of picks that came out
+// the wrong part of speech.
+func posCollisionRate(difficulty float64) float64 {
+	answer := models.RandomWord{Chechen: "цӏа", Russian: "дом", Subtype: 2}
+	candidates := []models.RandomWord{
+		{Chechen: "дитт", Russian: "дерево", Subtype: 2},
+		{Chechen: "хи", Russian: "вода", Subtype: 2},
+		{Chechen: "ӏаж", Russian: "яблоко", Subtype: 2},
+		{Chechen: "кехат", Russian: "бумага", Subtype: 2},
+		{Chechen: "дан", Russian: "прийти", Subtype: 1},
+		{Chechen: "ваха", Russian: "жить", Subtype: 1},
+		{Chechen: "деша", Russian: "читать", Subtype: 1},
+		{Chechen: "яздан", Russian: "писать", Subtype: 1},
+		{Chechen: "ала", Russian: "сказать", Subtype: 1},
+		{Chechen: "хаза", Russian: "слышать", Subtype: 1},
+		{Chechen: "эца", Russian: "взять", Subtype: 1},
+		{Chechen: "мала", Russian: "пить", Subtype: 1},
+	}
+	const trials = 2000
+	wrong, total := 0, 0
+	for i := range trials {
+		picked, rest := pickDistractors(answer, candidates, quizOptionCount-1, i%2 == 0, difficulty, sharedRand{})
+		if len(picked)+len(rest) != len(candidates) {
+			panic("pickDistractors lost candidates")
+		}
+		for _, p := range picked {
+			total++
+			if p.Subtype != answer.Subtype {
+				wrong++
+			}
+		}
+	}
+	return float64(wrong) / float64(total)
+}
+
+func TestPickDistractors_POSCollisionsVersusRandomDraw(t *testing.T) {
+	random := posCollisionRate(0) // what a plain random draw does
+	balanced := posCollisionRate(DefaultQuizDifficulty)
+	hardest := posCollisionRate(1)
+	t.Logf("wrong part of speech among distractors: random %.2f, default %.2f, hardest %.2f", random, balanced, hardest)
+
+	// 8 of the 12 candidates are verbs.
+	if random < 0.55 || random > 0.78 {
+		t.Errorf("random draw collision rate = %.2f, want about 2/3", random)
+	}
+	if balanced >= random-0.1 {
+		t.Errorf("default difficulty rate %.2f is not clearly below random %.2f", balanced, random)
+	}
+	if hardest != 0 {
+		t.Errorf("hardest rate = %.2f, want no verb while nouns remain", hardest)
+	}
+}
+
+func TestSetQuizDifficulty_Clamps(t *testing.T) {
+	var b Business
+	b.SetQuizDifficulty(3)
+	if b.quizDifficulty != 1 {
+		t.Errorf("difficulty 3 stored as %v, want 1", b.quizDifficulty)
+	}
+	b.SetQuizDifficulty(-1)
+	if b.quizDifficulty != 0 {
+		t.Errorf("difficulty -1 stored as %v, want 0", b.quizDifficulty)
+	}
+	b.SetQuizDifficulty(math.NaN())
+	if b.quizDifficulty != 0 {
+		t.Errorf("difficulty NaN stored as %v, want the previous 0", b.quizDifficulty)
+	}
+}
+
+func TestDailyChallenge_HalfEachWayAndSeededByDay(t *testing.T) {
+	stubDoshamAPI(t, http.StatusInternalServerError, ``)
+
+	// Ten questions with three distractors each take forty words; the
+	// lexicon has them only locally, so nothing here reaches the pool.
+	repo := newMirrorTestRepo(t)
+	ctx := context.Background()
+	i := 0
+	for _, head := range []string{"ба", "да", "ка", "ма", "на", "ра"} {
+		for _, tail := range []string{"лл", "рт", "хь", "мс", "кх", "тт", "шк", "зн"} {
+			che, rus := head+tail, fmt.Sprintf("слово%d", i)
+			i++
+			p := repository.TranslationPair{OriginalRaw: che, OriginalClean: che, OriginalLang: "CHE", TranslationRaw: rus, TranslationClean: rus, TranslationLang: "RUS", Source: "api"}
+			if _, _, err := repo.InsertTranslationPair(ctx, p); err != nil {
+				t.Fatalf("insert %q: %v", che, err)
+			}
+		}
+	}
+	fresh := func() *Business { return &Business{log: logrus.New(), dictRepo: repo} }
+
+	qs, err := fresh().DailyChallenge(ctx, "2026-10-16")
+	if err != nil {
+		t.Fatalf("DailyChallenge: %v", err)
+	}
+	if len(qs) != DailyChallengeSize {
+		t.Fatalf("questions = %d, want %d", len(qs), DailyChallengeSize)
+	}
+	reversed := 0
+	for _, q := range qs {
+		if q.Reversed {
+			reversed++
+		}
+		if len(q.Options) != quizOptionCount {
+			t.Fatalf("question %+v, want %d options", q, quizOptionCount)
+		}
+	}
+	if reversed != DailyChallengeSize/2 {
+		t.Fatalf("reversed = %d of %d, want half", reversed, DailyChallengeSize)
+	}
+
+	// The seed fixes everything: a second build for the same day, by a
+	// Business that has drawn nothing yet, asks the same words the same way.
+	again, err := fresh().DailyChallenge(ctx, "2026-10-16")
+	if err != nil {
+		t.Fatalf("DailyChallenge again: %v", err)
+	}
+	for i := range qs {
+		if qs[i].Prompt != again[i].Prompt || qs[i].Reversed != again[i].Reversed || !slices.Equal(qs[i].Options, again[i].Options) {
+			t.Fatalf("question %d differs for the same day: %+v vs %+v", i, qs[i], again[i])
+		}
+	}
+	other, err := fresh().DailyChallenge(ctx, "2026-10-17")
+	if err != nil {
+		t.Fatalf("DailyChallenge next day: %v", err)
+	}
+	if slices.EqualFunc(qs, other, func(a, b models.QuizQuestion) bool { return a.Prompt == b.Prompt }) {
+		t.Fatal("the next day asks the same words")
+	}
+}
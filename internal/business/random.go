@@ -15,7 +15,7 @@ import (
 // common case costs no API call. On error the caller falls back to the local
 // moderated table.
 func (b *Business) RandomWordFromAPI(ctx context.Context) (*models.RandomWord, error) {
-	words, err := b.randomCleanWords(ctx, 1)
+	words, err := b.randomCleanWords(ctx, 1, models.QuizFilter{})
 	if err != nil {
 		return nil, err
 	}
@@ -32,18 +32,28 @@ func orientEntry(entry models.Entry) *models.RandomWord {
 	for _, t := range entry.Translations {
 		if normalizeLang(t.LanguageCode) == "RUS" {
 			// content is Chechen, translation is the Russian meaning
-			return makeRandomWord(entry.Content, t.Content)
+			return categorize(makeRandomWord(entry.Content, t.Content), entry)
 		}
 	}
 	for _, t := range entry.Translations {
 		if normalizeLang(t.LanguageCode) == "CHE" {
 			// content is Russian, translation is the Chechen word
-			return makeRandomWord(t.Content, entry.Content)
+			return categorize(makeRandomWord(t.Content, entry.Content), entry)
 		}
 	}
 	return nil
 }
 
+// categorize copies the entry's part of speech and source dictionary onto its
+// pair, for /quiz filters. The subtype of a Russian-headed entry describes the
+// Russian word, but a verb translates to a verb: it holds for the pair.
+func categorize(w *models.RandomWord, entry models.Entry) *models.RandomWord {
+	if w != nil {
+		w.Subtype, w.Rate = entry.Subtype, entry.Rate
+	}
+	return w
+}
+
 // isLearnableWord reports whether a Chechen string is a clean standalone word
 // suitable for a discovery card — not a multi-clause dictionary gloss with
 // examples, grammatical markers, or numbered senses.
@@ -74,7 +84,11 @@ func makeRandomWord(chechen, russian string) *models.RandomWord {
 	if chechen == "" || russian == "" {
 		return nil
 	}
-	return &models.RandomWord{Chechen: stripStressMarks(chechen), Russian: stripStressMarks(russian)}
+	word := &models.RandomWord{Chechen: stripStressMarks(chechen), Russian: stripStressMarks(russian)}
+	if word.Chechen != chechen {
+		word.Stressed = chechen
+	}
+	return word
 }
 
 // stripStressMarks drops combining acute accents ("совеща́ние") — dictionary
@@ -107,17 +121,22 @@ func stripLeadingGenderMarker(chechen string) string {
 	return chechen
 }
 
-// fetchRandomEntries asks the dosham API for a batch of random dictionary
-// entries. Shared by the /random and /quiz features.
+// fetchRandomEntries draws a batch of random dictionary entries from the
+// sources. Shared by the /random and /quiz features.
 func (b *Business) fetchRandomEntries(ctx context.Context, count int) ([]models.Entry, error) {
-	query := fmt.Sprintf(`{ randomEntries(count: %d) { content type translations { content languageCode } } }`, count)
+	return b.randomFromSources(ctx, count)
+}
+
+// fetchDoshamRandomEntries asks the dosham API for a batch of random entries.
+func (b *Business) fetchDoshamRandomEntries(ctx context.Context, count int) ([]models.Entry, error) {
+	query := fmt.Sprintf(`{ randomEntries(count: %d) { content type subtype rate translations { content languageCode } } }`, count)
 
 	var response struct {
 		Data struct {
 			RandomEntries []models.Entry `json:"randomEntries"`
 		} `json:"data"`
 	}
-	if err := doDoshamQuery(ctx, query, nil, &response); err != nil {
+	if err := b.doshamClient().Query(ctx, "randomEntries", query, nil, &response); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,294 @@
+package business
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DictionarySource is one provider of dictionary data. dosham is the one the
+// bot was built on; the others live beside it rather than writing into it —
+// dosham's write API sits behind authorization we do not have — and their
+// answers are merged with its own on the way out.
+//
+// A source that has nothing of a kind to offer answers nil, nil: the local
+// store holds pairs but no morphology, a glossary has no paradigms.
+type DictionarySource interface {
+	// Name identifies the source in configuration and in the provenance
+	// admins see on cards.
+	Name() string
+	// Search answers a query the way dosham's `find` does: a substring match
+	// over headwords and translations, in the source's own order.
+	Search(ctx context.Context, word string) ([]models.TranslationPairs, error)
+	// Random draws up to count entries for /random and /quiz.
+	Random(ctx context.Context, count int) ([]models.Entry, error)
+	// Grammar returns word's grammar card, or nil when the source has none.
+	Grammar(ctx context.Context, word string) (*models.WordGrammar, error)
+}
+
+// Source names, as DICTIONARY_SOURCES spells them.
+const (
+	SourceDosham   = "dosham"
+	SourceLocal    = "local"
+	SourceGlossary = "glossary"
+)
+
+// errNoSourceAnswered is returned when every source was asked and none had an
+// answer or an error to report — only possible with no sources configured.
+var errNoSourceAnswered = errors.New("no dictionary source answered")
+
+// SetSources replaces the sources lookups consult, highest priority first.
+// Not safe to call once the bot is serving; main sets it at startup. With
+// none set, dosham alone answers, as it always has.
+func (b *Business) SetSources(sources ...DictionarySource) {
+	b.sources = sources
+}
+
+// SourcesFromConfig builds the sources named in spec — a comma-separated list
+// in priority order, e.g. "dosham,glossary" — against this Business.
+// glossaryPath is the file the glossary source reads.
+func (b *Business) SourcesFromConfig(spec, glossaryPath string) ([]DictionarySource, error) {
+	var sources []DictionarySource
+	seen := map[string]bool{}
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("dictionary source %q listed twice", name)
+		}
+		seen[name] = true
+		switch name {
+		case SourceDosham:
+			sources = append(sources, doshamSource{b})
+		case SourceLocal:
+			if b.dictRepo == nil {
+				return nil, errors.New("local dictionary source needs a repository")
+			}
+			sources = append(sources, localSource{b.dictRepo})
+		case SourceGlossary:
+			if glossaryPath == "" {
+				return nil, errors.New("glossary source needs GLOSSARY_PATH")
+			}
+			g, err := LoadGlossary(glossaryPath)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, g)
+		default:
+			return nil, fmt.Errorf("unknown dictionary source %q", name)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("no dictionary sources configured")
+	}
+	return sources, nil
+}
+
+// dictionarySources returns the configured sources, or dosham alone.
+func (b *Business) dictionarySources() []DictionarySource {
+	if len(b.sources) > 0 {
+		return b.sources
+	}
+	return []DictionarySource{doshamSource{b}}
+}
+
+// searchSources asks every source about word at once and returns their
+// answers concatenated in priority order, each pair tagged with where it came
+// from; rankAndDedup later folds duplicates across sources. A failing source
+// is left out as long as another found something — the same rule the
+// ё-variant cascade follows — and partial reports that one was. With nothing
+// found, any failure is the answer: the source that was down may be the one
+// that has the word.
+func (b *Business) searchSources(ctx context.Context, word string) (pairs []models.TranslationPairs, partial bool, err error) {
+	sources := b.dictionarySources()
+	results := make([][]models.TranslationPairs, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Go(func() { results[i], errs[i] = src.Search(ctx, word) })
+	}
+	wg.Wait()
+
+	// Non-nil even when empty: a real "no such word" is negative-cached, and
+	// the cache tells it from an outage by exactly this.
+	merged := make([]models.TranslationPairs, 0)
+	var firstErr error
+	for i, src := range sources {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", src.Name(), errs[i])
+			}
+			continue
+		}
+		for _, p := range results[i] {
+			p.Provenance = src.Name()
+			merged = append(merged, p)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, false, errNoSourceAnswered
+	}
+	if firstErr == nil {
+		return merged, false, nil
+	}
+	if len(merged) == 0 {
+		return nil, false, firstErr
+	}
+	b.log.Printf("dictionary source failed for %q, answering from the rest: %v\n", word, firstErr)
+	return merged, true, nil
+}
+
+// randomFromSources draws from the highest-priority source that has entries
+// to give. Offline, the local store is the only one asked, as it is for
+// lookups: /random, the quiz pool and everything drawing from them would
+// otherwise keep calling dosham.
+func (b *Business) randomFromSources(ctx context.Context, count int) ([]models.Entry, error) {
+	sources := b.dictionarySources()
+	if b.OfflineMode() {
+		if b.dictRepo == nil {
+			return nil, errNoSourceAnswered
+		}
+		sources = []DictionarySource{localSource{b.dictRepo}}
+	}
+	var firstErr error
+	for _, src := range sources {
+		entries, err := src.Random(ctx, count)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", src.Name(), err)
+			}
+			continue
+		}
+		if len(entries) > 0 {
+			return entries, nil
+		}
+	}
+	if firstErr == nil {
+		firstErr = errNoSourceAnswered
+	}
+	return nil, firstErr
+}
+
+// grammarFromSources returns the first grammar card a source has, in priority
+// order. An error is reported only when no later source had a card, so one
+// outage does not hide another source's answer — nor is "no grammar" cached
+// because the source that had it was down.
+func (b *Business) grammarFromSources(ctx context.Context, word string) (*models.WordGrammar, error) {
+	var firstErr error
+	for _, src := range b.dictionarySources() {
+		g, err := src.Grammar(ctx, word)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", src.Name(), err)
+			}
+			continue
+		}
+		if g != nil {
+			return g, nil
+		}
+	}
+	return nil, firstErr
+}
+
+// mergeProvenance joins two provenance lists, keeping order and dropping
+// repeats: "dosham" and "local,dosham" make "dosham,local".
+func mergeProvenance(a, b string) string {
+	if a == "" || a == b {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	names := strings.Split(a, ",")
+	for _, n := range strings.Split(b, ",") {
+		if !containsString(names, n) {
+			names = append(names, n)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// doshamSource is dosham.app, reached through the shared client.
+type doshamSource struct{ b *Business }
+
+func (s doshamSource) Name() string { return SourceDosham }
+
+func (s doshamSource) Search(ctx context.Context, word string) ([]models.TranslationPairs, error) {
+	return s.b.fetchTranslationsFromAPI(word)
+}
+
+func (s doshamSource) Random(ctx context.Context, count int) ([]models.Entry, error) {
+	return s.b.fetchDoshamRandomEntries(ctx, count)
+}
+
+func (s doshamSource) Grammar(ctx context.Context, word string) (*models.WordGrammar, error) {
+	return s.b.computeGrammar(ctx, word)
+}
+
+// localSource is the SQLite store: every pair an earlier lookup or
+// cmd/mirror wrote. As a source it answers the way the outage fallback does.
+type localSource struct{ repo DictionaryRepository }
+
+func (s localSource) Name() string { return SourceLocal }
+
+func (s localSource) Search(ctx context.Context, word string) ([]models.TranslationPairs, error) {
+	cleanWord := tools.NormalizeSearch(word)
+	if cleanWord == "" {
+		return nil, nil
+	}
+	pairs, err := s.repo.FindTranslationPairsContaining(ctx, cleanWord, mirrorSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pairs {
+		pairs[i].Original = tools.EscapeUnclosedTags(pairs[i].Original)
+		pairs[i].Translate = tools.EscapeUnclosedTags(pairs[i].Translate)
+	}
+	return pairs, nil
+}
+
+func (s localSource) Random(ctx context.Context, count int) ([]models.Entry, error) {
+	pairs, err := s.repo.RandomTranslationPairs(ctx, count)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]models.Entry, 0, len(pairs))
+	for _, p := range pairs {
+		// The query already left phrases out; rows stored before entry_type
+		// existed have none, and they are words too.
+		entryType := p.EntryType
+		if entryType == "" {
+			entryType = "WORD"
+		}
+		entries = append(entries, models.Entry{
+			Content: p.Original,
+			Type:    entryType,
+			Subtype: p.Subtype,
+			Rate:    p.Rate,
+			Translations: []models.Translation{
+				{Content: p.Translate, LanguageCode: p.TranslateLang},
+			},
+		})
+	}
+	return entries, nil
+}
+
+// Grammar is nil: the store keeps pairs, not dosham's morphology.
+func (s localSource) Grammar(context.Context, string) (*models.WordGrammar, error) {
+	return nil, nil
+}
@@ -0,0 +1,171 @@
+package business
+
+import (
+	"chetoru/internal/cache"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func writeGlossary(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "glossary.tsv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write glossary: %v", err)
+	}
+	return path
+}
+
+func TestLoadGlossary(t *testing.T) {
+	g, err := LoadGlossary(writeGlossary(t, "# course vocabulary\n\nӀаж\tяблоко\nГ1ала\tгород\tпрост.\n"))
+	if err != nil {
+		t.Fatalf("LoadGlossary: %v", err)
+	}
+	if g.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", g.Len())
+	}
+
+	// A Chechen-side hit leads with the Chechen word, a Russian-side hit with
+	// the Russian one, and the palochka stand-in in the file is folded.
+	got, _ := g.Search(context.Background(), "гӏал")
+	if len(got) != 1 || got[0].Original != "Г1ала" || got[0].TranslateLang != "RUS" || got[0].Notes != "прост." {
+		t.Fatalf("Search(гӏал) = %+v", got)
+	}
+	got, _ = g.Search(context.Background(), "Яблоко")
+	if len(got) != 1 || got[0].Original != "яблоко" || got[0].Translate != "Ӏаж" || got[0].OriginalLang != "RUS" {
+		t.Fatalf("Search(Яблоко) = %+v", got)
+	}
+
+	if _, err := LoadGlossary(writeGlossary(t, "только одно поле\n")); err == nil {
+		t.Fatal("a line without a tab loaded")
+	}
+}
+
+func TestSourcesFromConfig(t *testing.T) {
+	b := &Business{log: logrus.New(), dictRepo: newMirrorTestRepo(t)}
+	path := writeGlossary(t, "Ӏаж\tяблоко\n")
+
+	sources, err := b.SourcesFromConfig(" glossary , dosham,local", path)
+	if err != nil {
+		t.Fatalf("SourcesFromConfig: %v", err)
+	}
+	var names []string
+	for _, s := range sources {
+		names = append(names, s.Name())
+	}
+	if fmt.Sprint(names) != "[glossary dosham local]" {
+		t.Fatalf("sources = %v, want the configured priority", names)
+	}
+
+	for _, bad := range []string{"dosham,wiktionary", "dosham,dosham", "", "glossary"} {
+		glossary := path
+		if bad == "glossary" {
+			glossary = ""
+		}
+		if _, err := b.SourcesFromConfig(bad, glossary); err == nil {
+			t.Errorf("SourcesFromConfig(%q) accepted", bad)
+		}
+	}
+}
+
+// Two sources, one answer: a pair both know is shown once and credited to
+// both; a pair only the glossary knows joins dosham's.
+func TestTranslate_MergesSourcesWithProvenance(t *testing.T) {
+	var down atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"find":[{"entryId":"e1","content":"Ӏаж","type":"WORD","rate":10000,"translations":[
+			{"translationId":"t1","content":"яблоко","languageCode":"ru"}]}]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	b := &Business{log: logrus.New(), cache: cache.NewCache("127.0.0.1:1", "")}
+	sources, err := b.SourcesFromConfig("dosham,glossary", writeGlossary(t, "Ӏаж\tяблоко\nӀаж\tяблоня (диал.)\n"))
+	if err != nil {
+		t.Fatalf("SourcesFromConfig: %v", err)
+	}
+	b.SetSources(sources...)
+
+	got, err := b.Translate("ӏаж")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d pairs, want the shared one once plus the glossary's own: %+v", len(got), got)
+	}
+	if got[0].Translate != "яблоко" || got[0].Provenance != "dosham,glossary" {
+		t.Fatalf("first pair = %+v, want dosham's pair credited to both sources", got[0])
+	}
+	if got[1].Translate != "яблоня (диал.)" || got[1].Provenance != "glossary" {
+		t.Fatalf("second pair = %+v, want the glossary-only pair", got[1])
+	}
+
+	// dosham down: the glossary still answers, and that is an answer, not an
+	// outage.
+	down.Store(true)
+	got, err = b.Translate("яблон")
+	if err != nil || len(got) != 1 || got[0].Provenance != "glossary" {
+		t.Fatalf("Translate with dosham down = %+v, %v; want the glossary's pair", got, err)
+	}
+}
+
+// dosham down and the local table without the word is an outage, not a
+// miss: the word may well be dosham's. A source that did answer while
+// another failed makes a partial result, which is not cached.
+func TestTranslate_FailedSourceWithEmptyRestIsAnError(t *testing.T) {
+	stubDoshamAPI(t, http.StatusBadGateway, `down`)
+	b := &Business{log: logrus.New(), dictRepo: newMirrorTestRepo(t), cache: cache.NewCache("127.0.0.1:1", "")}
+	sources, err := b.SourcesFromConfig("dosham,local", "")
+	if err != nil {
+		t.Fatalf("SourcesFromConfig: %v", err)
+	}
+	b.SetSources(sources...)
+
+	if got, err := b.Translate("хьекъал"); err == nil {
+		t.Fatalf("Translate with dosham down and nothing local = %+v, nil; want an error", got)
+	}
+
+	g, err := LoadGlossary(writeGlossary(t, "Хьекъал\tум\n"))
+	if err != nil {
+		t.Fatalf("LoadGlossary: %v", err)
+	}
+	b.SetSources(doshamSource{b}, g)
+	got, partial, err := b.fetchTranslationsWithFallback("хьекъал")
+	if err != nil || len(got) != 1 || !partial {
+		t.Fatalf("fetch with dosham down = %+v, partial %v, %v; want the glossary's pair, partial", got, partial, err)
+	}
+}
+
+func TestRandomFromSources_FallsThroughPriority(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	b := &Business{log: logrus.New()}
+	g, err := LoadGlossary(writeGlossary(t, "Ӏаж\tяблоко\nКхор\tгруша\n"))
+	if err != nil {
+		t.Fatalf("LoadGlossary: %v", err)
+	}
+	b.SetSources(doshamSource{b}, g)
+
+	entries, err := b.fetchRandomEntries(context.Background(), 5)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("fetchRandomEntries = %+v, %v; want the glossary's two entries", entries, err)
+	}
+	if w := orientEntry(entries[0]); w == nil || w.Chechen == "" || w.Russian == "" {
+		t.Fatalf("glossary entry %+v does not orient into a quiz pair", entries[0])
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"chetoru/internal/cache"
 	"chetoru/internal/models"
 	"chetoru/internal/repository"
+	"chetoru/pkg/morph"
 	"chetoru/pkg/tools"
 	"sort"
 	"unicode/utf8"
@@ -34,6 +35,26 @@ type Business struct {
 	log                 *logrus.Logger
 	onPairReady         OnPairReady
 	pool                wordPool
+	quizDifficulty      float64 // see SetQuizDifficulty
+
+	// dosham is shared by every feature that queries the API, so its
+	// concurrency cap and breaker hold for the process. Reached through
+	// doshamClient, which fills it in for a Business built as a literal.
+	dosham     *DoshamClient
+	doshamOnce sync.Once
+
+	// sources are the dictionaries lookups consult, highest priority first;
+	// empty means dosham alone. See SetSources.
+	sources []DictionarySource
+
+	// offline answers every lookup from the local mirror without calling
+	// dosham; see SetOfflineMode.
+	offline atomic.Bool
+
+	// lexicon is the fuzzy index behind DidYouMean.
+	lexicon lexiconIndex
+	// games is the vocabulary behind /daily and /wordle.
+	games gameIndex
 
 	cacheHits   atomic.Int64
 	cacheMisses atomic.Int64
@@ -48,6 +69,22 @@ func (b *Business) WaitBackground() {
 	b.bg.Wait()
 }
 
+// doshamClient returns the dosham client, creating one on first use for a
+// Business that was not built by NewBusiness.
+func (b *Business) doshamClient() *DoshamClient {
+	b.doshamOnce.Do(func() {
+		if b.dosham == nil {
+			b.dosham = NewDoshamClient()
+		}
+	})
+	return b.dosham
+}
+
+// DoshamStats returns the dosham client's per-query counters and breaker state.
+func (b *Business) DoshamStats() models.DoshamStats {
+	return b.doshamClient().Stats()
+}
+
 // TranslationCacheStats returns hit/miss counts for the translation cache
 // since process start.
 func (b *Business) TranslationCacheStats() (hits, misses int64) {
@@ -57,18 +94,27 @@ func (b *Business) TranslationCacheStats() (hits, misses int64) {
 type DictionaryRepository interface {
 	FindTranslationPairs(ctx context.Context, cleanWord string, limit int) ([]models.TranslationPairs, error)
 	FindTranslationPairsByPrefix(ctx context.Context, prefix string, limit int) ([]models.TranslationPairs, error)
+	FindTranslationPairsContaining(ctx context.Context, cleanWord string, limit int) ([]models.TranslationPairs, error)
+	SearchTranslationPairs(ctx context.Context, query string, limit int) ([]models.TranslationPairs, error)
+	StoreEntryForms(ctx context.Context, headword, headwordClean string, formsClean []string) (int, error)
+	FindFormHeadwords(ctx context.Context, formClean string) ([]string, error)
+	ListLexicon(ctx context.Context) ([]string, error)
+	ListChechenLexicon(ctx context.Context) (headwords, forms []string, err error)
+	RandomTranslationPairs(ctx context.Context, limit int) ([]models.TranslationPairs, error)
 	InsertTranslationPair(ctx context.Context, pair repository.TranslationPair) (int64, bool, error)
 	UpdateTranslationPairFormatting(ctx context.Context, id int64, formattedAI, formattedChosen string) error
 	SetTranslationPairFormattingChoice(ctx context.Context, id int64, choice string) error
 }
 
-func NewBusiness(cache *cache.Cache, dictRepo DictionaryRepository, aiClient *ai.Client, log *logrus.Logger) *Business {
+func NewBusiness(cache *cache.Cache, dictRepo DictionaryRepository, dosham *DoshamClient, aiClient *ai.Client, log *logrus.Logger) *Business {
 	return &Business{
 		cache:               cache,
 		dictRepo:            dictRepo,
+		dosham:              dosham,
 		aiClient:            aiClient,
 		aiFormattingEnabled: aiClient != nil,
 		log:                 log,
+		quizDifficulty:      DefaultQuizDifficulty,
 	}
 }
 
@@ -91,6 +137,10 @@ func (b *Business) AIFormattingEnabled() bool {
 // it is the difference between telling a user their word is missing and telling
 // them the service is down, and between recording a genuine vocabulary gap and
 // poisoning missing_words with every query made during an outage.
+//
+// When dosham cannot answer — or offline mode is on — the local mirror does.
+// Those answers are never cached: the mirror may be incomplete, and an empty
+// or partial result frozen for a day would outlive the outage that caused it.
 func (b *Business) Translate(word string) ([]models.TranslationPairs, error) {
 	ctx := context.Background()
 	cacheKey := normalizeCacheKey(word)
@@ -104,15 +154,116 @@ func (b *Business) Translate(word string) ([]models.TranslationPairs, error) {
 		return translations, nil
 	}
 
-	translations, err := b.fetchTranslationsWithFallback(word)
+	// Without dosham the local table has no entry of the word's own — the
+	// exact lookup above found none — so an inflected Chechen form goes to
+	// its headword before the mirror's looser matches.
+	if b.OfflineMode() {
+		if translations := b.translateEntryForm(ctx, word); len(translations) > 0 {
+			b.cacheTranslationsAsync(ctx, cacheKey, translations)
+			return translations, nil
+		}
+		if mirrored := b.loadMirrorTranslations(ctx, word); len(mirrored) > 0 {
+			return mirrored, nil
+		}
+		return b.translateRespelled(word), nil
+	}
+
+	translations, partial, err := b.fetchTranslationsWithFallback(word)
 	if err != nil {
+		if forms := b.translateEntryForm(ctx, word); len(forms) > 0 {
+			return forms, nil
+		}
+		if mirrored := b.loadMirrorTranslations(ctx, word); len(mirrored) > 0 {
+			b.log.Printf("dosham unavailable, answered %q from the mirror: %v\n", word, err)
+			return mirrored, nil
+		}
 		return nil, err
 	}
+	// An inflected Chechen form: dosham's search knows only headwords, but the
+	// form index learned «дийцира» from the paradigm of «дийца». It is asked
+	// only when the search has no entry for the word itself, so a headword
+	// that is also another word's form opens as itself.
+	if len(filterHeadwordMatches(translations, word)) == 0 {
+		if forms := b.translateEntryForm(ctx, word); len(forms) > 0 {
+			if !partial {
+				b.cacheTranslationsAsync(ctx, cacheKey, forms)
+			}
+			return forms, nil
+		}
+	}
+	if len(translations) == 0 {
+		// Already ranked, for the word they were found for.
+		if respelled := b.translateRespelled(word); len(respelled) > 0 {
+			b.cacheTranslationsAsync(ctx, cacheKey, respelled)
+			return respelled, nil
+		}
+	}
 	translations = rankAndDedup(translations, word)
-	b.cacheTranslationsAsync(ctx, cacheKey, translations)
+	// With a source down the answer is what the rest had, and caching it
+	// would keep the missing source's pairs away for a day.
+	if !partial {
+		b.cacheTranslationsAsync(ctx, cacheKey, translations)
+	}
 	return translations, nil
 }
 
+// maxRespelledLookups caps the live lookups one Latin miss spends on its
+// transliteration candidates. The local table and the cache check all of
+// them for free; dosham gets only the likeliest.
+const maxRespelledLookups = 2
+
+// translateRespelled retries a Latin-script query that found nothing as the
+// Cyrillic it may have meant — typed on the wrong keyboard layout, or in the
+// Latin Chechen alphabet — and returns the first candidate's pairs, marked
+// Respelled so the card renders for the word the dictionary knows. Only a
+// miss pays for this: a Latin query dosham does answer never gets here.
+func (b *Business) translateRespelled(word string) []models.TranslationPairs {
+	candidates := tools.TransliterationCandidates(word)
+	ctx := context.Background()
+	for _, candidate := range candidates {
+		if local := b.loadLocalTranslations(ctx, candidate); len(local) > 0 {
+			return markRespelled(rankAndDedup(local, candidate), candidate)
+		}
+		if b.cache == nil {
+			continue
+		}
+		// Straight to the cache, as suggestFromLemmas does: a candidate
+		// that misses is no miss of a lookup anyone asked for.
+		cached, err := b.cache.GetTranslation(ctx, normalizeCacheKey(candidate))
+		if err != nil {
+			if !errors.Is(err, cache.ErrMiss) {
+				b.log.Printf("respelled cache lookup failed for %q: %v\n", candidate, err)
+			}
+			continue
+		}
+		if len(cached) > 0 {
+			return markRespelled(cached, candidate)
+		}
+	}
+
+	for _, candidate := range candidates[:min(len(candidates), maxRespelledLookups)] {
+		pairs, err := b.Translate(candidate)
+		if err != nil {
+			b.log.Printf("respelled lookup %q for %q failed: %v\n", candidate, word, err)
+			continue
+		}
+		if len(pairs) > 0 {
+			return markRespelled(pairs, candidate)
+		}
+	}
+	return nil
+}
+
+// markRespelled returns a copy of pairs marked as found for candidate.
+func markRespelled(pairs []models.TranslationPairs, candidate string) []models.TranslationPairs {
+	out := make([]models.TranslationPairs, len(pairs))
+	for i, p := range pairs {
+		p.Respelled = candidate
+		out[i] = p
+	}
+	return out
+}
+
 func normalizeText(text string) string {
 	return tools.NormalizeSearch(text)
 }
@@ -163,51 +314,64 @@ func toNullString(v string) sql.NullString {
 // and reports whether the word has translations now. Used by the daily
 // missing-words sweep; a found result is cached so the next search is instant.
 func (b *Business) RecheckTranslation(word string) bool {
+	// Offline, the only answer is the mirror, which is what put the word on
+	// the list in the first place.
+	if b.OfflineMode() {
+		return false
+	}
 	// An outage is not evidence the word is still missing, so it stays on the
 	// list and gets another chance on the next sweep.
-	translations, err := b.fetchTranslationsWithFallback(word)
+	translations, partial, err := b.fetchTranslationsWithFallback(word)
 	if err != nil || len(translations) == 0 {
 		return false
 	}
+	if partial {
+		return true
+	}
 	// This path bypasses Translate and writes under the same key, so it has to
 	// rank too — otherwise the sweep quietly caches an unranked list.
 	b.cacheTranslationsAsync(context.Background(), normalizeCacheKey(word), rankAndDedup(translations, word))
 	return true
 }
 
-// fetchTranslationsWithFallback queries the API for word and, when the results
+// fetchTranslationsWithFallback queries the sources for word and, when the results
 // lack an exact headword match for a ё/е-ambiguous query, retries with the
 // candidate respellings and puts those results first. The dosham search is a
 // substring match that does not fold ё/е — "елка" matches "Белка" but not
 // "Ёлка" — while Russians routinely type е for ё. Variants are tried one ё at
 // a time ("береза" → "бёреза", "берёза"), stopping at the first exact match.
-func (b *Business) fetchTranslationsWithFallback(word string) ([]models.TranslationPairs, error) {
+// partial reports that a source failed on a query whose results are kept.
+func (b *Business) fetchTranslationsWithFallback(word string) (translations []models.TranslationPairs, partial bool, err error) {
 	word = strings.TrimSpace(word)
-	translations, err := b.fetchTranslationsFromAPI(word)
+	ctx := context.Background()
+	translations, partial, err = b.searchSources(ctx, word)
 	if err == nil && hasExactOriginal(translations, word) {
-		return translations, nil
+		return translations, partial, nil
 	}
 
 	variants := tools.YoVariants(word)
 	if len(variants) == 0 {
-		return translations, err
+		return translations, partial, err
 	}
 
 	// The user is already waiting on a miss, so variant lookups run
 	// concurrently instead of chaining API round trips. Merging still follows
 	// variant order, so the result is the same as the sequential version.
 	results := make([][]models.TranslationPairs, len(variants))
+	partials := make([]bool, len(variants))
 	errs := make([]error, len(variants))
 	var wg sync.WaitGroup
 	for i, alt := range variants {
-		wg.Go(func() { results[i], errs[i] = b.fetchTranslationsFromAPI(alt) })
+		wg.Go(func() { results[i], partials[i], errs[i] = b.searchSources(ctx, alt) })
 	}
 	wg.Wait()
 
+	partial = partial || err != nil
 	for i, altPairs := range results {
 		if err == nil {
 			err = errs[i]
 		}
+		partial = partial || partials[i] || errs[i] != nil
 		if len(altPairs) == 0 {
 			continue
 		}
@@ -217,13 +381,14 @@ func (b *Business) fetchTranslationsWithFallback(word string) ([]models.Translat
 		}
 	}
 	// Something answered, so this is a real result even if a spelling variant
-	// failed on the way — at worst we missed an extra respelling. Only a
-	// cascade that found nothing AND had a query fail is an outage, and that
-	// one must not be negative-cached as "no such word" for a day.
+	// failed on the way — at worst we missed an extra respelling, which
+	// partial tells the cache. Only a cascade that found nothing AND had a
+	// query fail is an outage, and that one must not be negative-cached as
+	// "no such word" for a day.
 	if len(translations) > 0 {
-		return translations, nil
+		return translations, partial, nil
 	}
-	return translations, err
+	return translations, partial, err
 }
 
 // hasExactOriginal reports whether any pair's headword is exactly the searched
@@ -244,14 +409,15 @@ func hasExactOriginal(pairs []models.TranslationPairs, word string) bool {
 // mergePairs returns first followed by second, dropping duplicate pairs.
 func mergePairs(first, second []models.TranslationPairs) []models.TranslationPairs {
 	merged := make([]models.TranslationPairs, 0, len(first)+len(second))
-	seen := make(map[string]bool, len(first)+len(second))
+	seen := make(map[string]int, len(first)+len(second))
 	add := func(pairs []models.TranslationPairs) {
 		for _, p := range pairs {
 			k := tools.NormalizeSearch(p.Original) + "\x00" + tools.NormalizeSearch(p.Translate)
-			if seen[k] {
+			if j, ok := seen[k]; ok {
+				merged[j].Provenance = mergeProvenance(merged[j].Provenance, p.Provenance)
 				continue
 			}
-			seen[k] = true
+			seen[k] = len(merged)
 			merged = append(merged, p)
 		}
 	}
@@ -281,6 +447,13 @@ func (b *Business) SuggestTranslations(word string) []models.TranslationPairs {
 		return b.suggestFromPhraseWords(words)
 	}
 
+	// Undoing the ending is exact where trimming guesses: «шёл» has no prefix
+	// in common with «идти», and «стола» trims to «сто». The prefix cascade
+	// stays behind it for what the rules do not know.
+	if pairs := b.suggestFromLemmas(word); len(pairs) > 0 {
+		return pairs
+	}
+
 	prefixes := prefixCandidates(word)
 	if len(prefixes) == 0 {
 		return nil
@@ -327,6 +500,117 @@ func (b *Business) SuggestTranslations(word string) []models.TranslationPairs {
 	return nil
 }
 
+// maxExampleHits bounds the "found in examples" section: the card shows six
+// lines, and the rest of the budget absorbs hits dedup folds away.
+const maxExampleHits = 20
+
+// FindInExamples searches the local full-text index for a word that no entry
+// is about but that appears inside glosses and examples. It backs the "найдено
+// в примерах" section of a miss; the pairs go to tools.FormatCard as they are,
+// best match first.
+func (b *Business) FindInExamples(word string) []models.TranslationPairs {
+	if b.dictRepo == nil {
+		return nil
+	}
+	query := tools.NormalizeSearch(word)
+	if query == "" {
+		return nil
+	}
+	pairs, err := b.dictRepo.SearchTranslationPairs(context.Background(), query, maxExampleHits)
+	if err != nil {
+		b.log.Printf("full-text search failed for %q: %v\n", word, err)
+		return nil
+	}
+	for i := range pairs {
+		pairs[i].Original = tools.EscapeUnclosedTags(pairs[i].Original)
+		pairs[i].Translate = tools.EscapeUnclosedTags(pairs[i].Translate)
+	}
+	return pairs
+}
+
+// maxLemmaLookups caps the live lookups one miss spends on lemma candidates.
+// The rules propose generously and the local table and the cache check all
+// of them for free; dosham gets only the likeliest, which for a table entry
+// such as «шёл» → «идти» is the right one.
+const maxLemmaLookups = 1
+
+// suggestFromLemmas looks up the candidate dictionary forms of an inflected
+// Russian word and returns the pairs of the first one that is a headword —
+// local table first, then what earlier lookups left in the cache, then a
+// live lookup of the likeliest candidate. Only exact headword matches count:
+// a lemma candidate that merely occurs inside other entries is a wrong
+// guess.
+func (b *Business) suggestFromLemmas(word string) []models.TranslationPairs {
+	lemmas := morph.Lemmas(word)
+	if len(lemmas) == 0 {
+		return nil
+	}
+	key := tools.NormalizeSearch(word)
+	words := make([]string, 0, len(lemmas))
+	for _, l := range lemmas {
+		if l.Word != key {
+			words = append(words, l.Word)
+		}
+	}
+
+	ctx := context.Background()
+	if b.dictRepo != nil {
+		for _, w := range words {
+			local, err := b.dictRepo.FindTranslationPairs(ctx, w, maxSuggestions)
+			if err != nil {
+				b.log.Printf("lemma lookup failed for %q: %v\n", w, err)
+				break
+			}
+			if len(local) > 0 {
+				return local
+			}
+		}
+	}
+
+	if b.cache == nil {
+		return nil
+	}
+	for _, w := range words {
+		// Straight to the cache, not loadCachedTranslations: a guess that
+		// misses is no miss of a lookup anyone asked for.
+		cached, err := b.cache.GetTranslation(ctx, normalizeCacheKey(w))
+		if err != nil {
+			if !errors.Is(err, cache.ErrMiss) {
+				b.log.Printf("lemma cache lookup failed for %q: %v\n", w, err)
+				break
+			}
+			continue
+		}
+		if matches := filterHeadwordMatches(cached, w); len(matches) > 0 {
+			return matches[:min(len(matches), maxSuggestions)]
+		}
+	}
+
+	for _, w := range words[:min(len(words), maxLemmaLookups)] {
+		pairs, err := b.Translate(w)
+		if err != nil {
+			b.log.Printf("lemma lookup %q for %q failed: %v\n", w, word, err)
+			continue
+		}
+		if matches := filterHeadwordMatches(pairs, w); len(matches) > 0 {
+			return matches[:min(len(matches), maxSuggestions)]
+		}
+	}
+	return nil
+}
+
+// filterHeadwordMatches keeps the pairs where one side is exactly word.
+func filterHeadwordMatches(pairs []models.TranslationPairs, word string) []models.TranslationPairs {
+	key := normalizeForRank(word)
+	var out []models.TranslationPairs
+	for _, p := range pairs {
+		if normalizeForRank(p.Original) == key || normalizeForRank(p.Translate) == key {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
 // phraseWords splits a multi-word query into distinct lookup-worthy words,
 // skipping short particles. Returns nil for single-word queries.
 func phraseWords(query string) []string {
@@ -406,30 +690,9 @@ func filterPrefixMatches(pairs []models.TranslationPairs, prefix string) []model
 }
 
 func (b *Business) fetchTranslationsFromAPI(word string) ([]models.TranslationPairs, error) {
-	query := `
-		query Find($inputText: String!) {
-			find(inputText: $inputText) {
-				entryId
-				content
-				type
-				subtype
-				entryIndex
-				notes
-				rate
-				details
-				translations {
-					translationId
-					content
-					languageCode
-					notes
-				}
-			}
-		}
-	`
-
-	var response models.TranslationResponse
-	if err := doDoshamQuery(context.Background(), query, map[string]any{"inputText": word}, &response); err != nil {
-		return nil, fmt.Errorf("dosham find %q: %w", word, err)
+	entries, err := b.doshamClient().Find(context.Background(), word)
+	if err != nil {
+		return nil, err
 	}
 
 	translations := make([]models.TranslationPairs, 0)
@@ -443,7 +706,7 @@ func (b *Business) fetchTranslationsFromAPI(word string) ([]models.TranslationPa
 	// The new API returns a flat list of entries. Each entry carries its
 	// translations; we keep only Russian/Chechen ones, normalizing the language
 	// code to the internal CHE/RUS representation.
-	for _, entry := range response.Data.Find {
+	for _, entry := range entries {
 		for _, translation := range entry.Translations {
 			normLang := normalizeLang(translation.LanguageCode)
 			if normLang == "" {
@@ -499,9 +762,13 @@ func rankAndDedup(pairs []models.TranslationPairs, query string) []models.Transl
 	for _, p := range pairs {
 		k := normalizeForRank(p.Original) + "\x00" + normalizeForRank(p.Translate)
 		if i, ok := at[k]; ok {
+			// The pair survives once, but every source that had it is still
+			// on record — agreement between sources is what admins look for.
+			provenance := mergeProvenance(out[i].Provenance, p.Provenance)
 			if betterDuplicate(out[i], p) {
 				out[i] = p
 			}
+			out[i].Provenance = provenance
 			continue
 		}
 		at[k] = len(out)
@@ -627,6 +894,9 @@ func (b *Business) loadLocalTranslations(ctx context.Context, word string) []mod
 		b.log.Printf("failed to read dictionary pairs: %v\n", err)
 		return nil
 	}
+	for i := range translations {
+		translations[i].Provenance = SourceLocal
+	}
 	return translations
 }
 
@@ -635,28 +905,8 @@ func (b *Business) storeTranslationPair(entry models.Entry, translation models.T
 		return
 	}
 
-	originalLang := inferOriginalLang(translation.LanguageCode)
-	if originalLang == "" {
-		return
-	}
-
-	pair := repository.TranslationPair{
-		OriginalRaw:         strings.TrimSpace(entry.Content),
-		OriginalClean:       normalizeText(entry.Content),
-		OriginalLang:        originalLang,
-		TranslationRaw:      strings.TrimSpace(translation.Content),
-		TranslationClean:    normalizeText(translation.Content),
-		TranslationLang:     translation.LanguageCode,
-		Source:              "api",
-		SourceEntryID:       toNullString(entry.EntryID),
-		SourceTranslationID: toNullString(translation.TranslationID),
-		Rate:                entry.Rate,
-		EntryType:           entry.Type,
-		Subtype:             entry.Subtype,
-		EntryIndex:          entry.EntryIndex,
-		EntryNotes:          entry.Notes,
-	}
-	if pair.OriginalClean == "" || pair.TranslationClean == "" {
+	pair, ok := NewTranslationPair(entry, translation, "api")
+	if !ok {
 		return
 	}
 
@@ -679,6 +929,40 @@ func (b *Business) storeTranslationPair(entry models.Entry, translation models.T
 	}
 }
 
+// NewTranslationPair builds the stored row for one translation of a dosham
+// entry. The bot's own lookups and cmd/mirror both go through it, so a pair
+// mirrored ahead of time is byte-for-byte the row a live lookup would have
+// written and renders the same card. ok is false for languages other than
+// Russian and Chechen and for sides that normalize to nothing.
+func NewTranslationPair(entry models.Entry, translation models.Translation, source string) (repository.TranslationPair, bool) {
+	translationLang := normalizeLang(translation.LanguageCode)
+	originalLang := inferOriginalLang(translationLang)
+	if originalLang == "" {
+		return repository.TranslationPair{}, false
+	}
+
+	pair := repository.TranslationPair{
+		OriginalRaw:         strings.TrimSpace(entry.Content),
+		OriginalClean:       normalizeText(entry.Content),
+		OriginalLang:        originalLang,
+		TranslationRaw:      strings.TrimSpace(translation.Content),
+		TranslationClean:    normalizeText(translation.Content),
+		TranslationLang:     translationLang,
+		Source:              source,
+		SourceEntryID:       toNullString(entry.EntryID),
+		SourceTranslationID: toNullString(translation.TranslationID),
+		Rate:                entry.Rate,
+		EntryType:           entry.Type,
+		Subtype:             entry.Subtype,
+		EntryIndex:          entry.EntryIndex,
+		EntryNotes:          entry.Notes,
+	}
+	if pair.OriginalClean == "" || pair.TranslationClean == "" {
+		return repository.TranslationPair{}, false
+	}
+	return pair, true
+}
+
 // formatPairWithAI asynchronously formats a dictionary pair using AI, saves it, then triggers moderation.
 func (b *Business) formatPairWithAI(pairID int64, cleanWord, originalRaw, translationRaw string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -0,0 +1,115 @@
+package business
+
+import (
+	"chetoru/internal/cache"
+	"chetoru/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestTranslate_RetriesLatinQueryAsCyrillic(t *testing.T) {
+	var mu sync.Mutex
+	var asked []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				InputText string `json:"inputText"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		asked = append(asked, req.Variables.InputText)
+		mu.Unlock()
+		if req.Variables.InputText != "слово" {
+			fmt.Fprint(w, `{"data":{"find":[]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"find":[{"entryId":"e1","content":"Слово","type":"WORD","rate":100,"translations":[
+			{"translationId":"t1","content":"Дош","languageCode":"ce"}]}]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	b := &Business{log: logrus.New(), dictRepo: newMirrorTestRepo(t), cache: cache.NewCache("127.0.0.1:1", "")}
+	got, err := b.Translate("ckjdj")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	b.WaitBackground()
+	if len(got) != 1 || got[0].Translate != "Дош" || got[0].Respelled != "слово" {
+		t.Fatalf("Translate(ckjdj) = %+v, want Слово's pair marked Respelled", got)
+	}
+
+	// A Cyrillic miss has nothing to respell and costs no extra call.
+	mu.Lock()
+	asked = nil
+	mu.Unlock()
+	if got, err := b.Translate("зузук"); err != nil || len(got) != 0 {
+		t.Fatalf("Translate(зузук) = %+v, %v; want a plain miss", got, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, q := range asked {
+		if q != "зузук" {
+			t.Fatalf("a Cyrillic miss asked dosham about %q", q)
+		}
+	}
+}
+
+// A candidate the local table knows answers before any candidate goes to
+// dosham: «dosh» swapped to «вщыр» would otherwise be a live miss first.
+func TestTranslate_RespelledFromLocalTable(t *testing.T) {
+	var mu sync.Mutex
+	var asked []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				InputText string `json:"inputText"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		asked = append(asked, req.Variables.InputText)
+		mu.Unlock()
+		fmt.Fprint(w, `{"data":{"find":[]}}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOSHAM_API_URL", srv.URL)
+
+	repo := newMirrorTestRepo(t)
+	if _, _, err := repo.InsertTranslationPair(context.Background(), repository.TranslationPair{
+		OriginalRaw: "Дош", OriginalClean: "дош", OriginalLang: "CHE",
+		TranslationRaw: "Слово", TranslationClean: "слово", TranslationLang: "RUS", Source: "api",
+	}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	b := &Business{log: logrus.New(), dictRepo: repo, cache: cache.NewCache("127.0.0.1:1", "")}
+	got, err := b.Translate("dosh")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	b.WaitBackground()
+	if len(got) != 1 || got[0].Respelled != "дош" {
+		t.Fatalf("Translate(dosh) = %+v, want the local pair marked Respelled", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, q := range asked {
+		if q != "dosh" {
+			t.Fatalf("asked dosham about %q, want the local table to answer for the candidate", q)
+		}
+	}
+}
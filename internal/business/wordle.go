@@ -0,0 +1,158 @@
+package business
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gameIndex is the vocabulary the daily games draw from, built from the
+// Chechen lexicon like lexiconIndex and kept as long. Each list is sorted, so
+// a day's seed picks the same words whatever order the lexicon loads in.
+type gameIndex struct {
+	mu       sync.Mutex
+	words    gameWords
+	built    time.Time
+	building bool
+	// first is closed when the build the first game request started is
+	// done, as lexiconIndex.first is.
+	first chan struct{}
+}
+
+// gameWords is one build of gameIndex. headwords are the clean learnable
+// Chechen headwords, which the daily challenge draws from; answers are those
+// of models.WordleLength letters, /wordle's; guesses are the answers and
+// every inflected form of the same length.
+type gameWords struct {
+	headwords []string
+	answers   []string
+	guesses   map[string]bool
+}
+
+// WordleWord picks the hidden word for day (a time.DateOnly date), seeded by
+// the day as the daily challenge is. The lexicon grows between days, and the
+// pick with it, so the caller stores the day's word rather than asking again.
+func (b *Business) WordleWord(ctx context.Context, day string) (string, error) {
+	answers := b.gameVocabulary(ctx).answers
+	if len(answers) == 0 {
+		return "", fmt.Errorf("no %d-letter words in the lexicon", models.WordleLength)
+	}
+	rng := rand.New(rand.NewPCG(daySeed("wordle", day)))
+	return answers[rng.IntN(len(answers))], nil
+}
+
+// IsWordleWord reports whether word is a Chechen word /wordle accepts as a
+// guess: one of models.WordleLength letters the local lexicon knows, as a
+// headword or a form.
+func (b *Business) IsWordleWord(ctx context.Context, word string) bool {
+	return b.gameVocabulary(ctx).guesses[tools.FuzzyKey(word)]
+}
+
+// gameVocabulary returns the current index, building it on first use and
+// in the background once it is older than lexiconTTL.
+func (b *Business) gameVocabulary(ctx context.Context) gameWords {
+	if b.dictRepo == nil {
+		return gameWords{}
+	}
+	idx := &b.games
+	idx.mu.Lock()
+	words, built, building := idx.words, idx.built, idx.building
+	stale := words.guesses != nil && time.Since(built) > lexiconTTL
+	if stale && !building {
+		idx.building = true
+	}
+	idx.mu.Unlock()
+
+	if words.guesses == nil {
+		return b.firstGamesBuild(ctx)
+	}
+	if stale && !building {
+		b.bg.Go(func() { b.rebuildGames(context.Background()) })
+	}
+	return words
+}
+
+// firstGamesBuild builds the index for the first /daily or /wordle request,
+// or waits for the build another one started, as firstLexiconBuild does.
+func (b *Business) firstGamesBuild(ctx context.Context) gameWords {
+	idx := &b.games
+	idx.mu.Lock()
+	if idx.words.guesses != nil {
+		words := idx.words
+		idx.mu.Unlock()
+		return words
+	}
+	if first := idx.first; first != nil {
+		idx.mu.Unlock()
+		select {
+		case <-first:
+		case <-ctx.Done():
+			return gameWords{}
+		}
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+		return idx.words
+	}
+	first := make(chan struct{})
+	idx.first = first
+	idx.mu.Unlock()
+
+	words := b.rebuildGames(ctx)
+
+	idx.mu.Lock()
+	idx.first = nil
+	idx.mu.Unlock()
+	close(first)
+	return words
+}
+
+func (b *Business) rebuildGames(ctx context.Context) gameWords {
+	headwords, forms, err := b.dictRepo.ListChechenLexicon(ctx)
+	idx := &b.games
+	if err != nil {
+		b.log.Printf("game vocabulary load failed: %v\n", err)
+		idx.mu.Lock()
+		defer idx.mu.Unlock()
+		idx.building = false
+		return idx.words
+	}
+
+	words := gameWords{guesses: make(map[string]bool)}
+	learnable := make(map[string]bool)
+	for _, w := range headwords {
+		w = stripLeadingGenderMarker(strings.TrimSpace(tools.Clean(w)))
+		if !isLearnableWord(w) {
+			continue
+		}
+		if w = stripStressMarks(w); !learnable[w] {
+			learnable[w] = true
+			words.headwords = append(words.headwords, w)
+		}
+		if len(tools.ChechenLetters(w)) != models.WordleLength {
+			continue
+		}
+		key := tools.FuzzyKey(w)
+		if !words.guesses[key] {
+			words.guesses[key] = true
+			words.answers = append(words.answers, key)
+		}
+	}
+	for _, f := range forms {
+		if len(tools.ChechenLetters(f)) == models.WordleLength {
+			words.guesses[tools.FuzzyKey(f)] = true
+		}
+	}
+	slices.Sort(words.headwords)
+	slices.Sort(words.answers)
+
+	idx.mu.Lock()
+	idx.words, idx.built, idx.building = words, time.Now(), false
+	idx.mu.Unlock()
+	return words
+}
@@ -0,0 +1,91 @@
+package business
+
+import (
+	"chetoru/internal/repository"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestWordle_VocabularyCountsDigraphsAsLetters(t *testing.T) {
+	repo := newMirrorTestRepo(t)
+	ctx := context.Background()
+	for _, p := range []repository.TranslationPair{
+		{OriginalRaw: "Хьекъал", OriginalClean: "хьекъал", OriginalLang: "CHE", TranslationRaw: "ум", TranslationClean: "ум", TranslationLang: "RUS"},
+		{OriginalRaw: "Ребенок", OriginalClean: "ребенок", OriginalLang: "RUS", TranslationRaw: "кӏорни", TranslationClean: "кӏорни", TranslationLang: "CHE"},
+		{OriginalRaw: "Гӏала", OriginalClean: "гӏала", OriginalLang: "CHE", TranslationRaw: "город", TranslationClean: "город", TranslationLang: "RUS"},
+		{OriginalRaw: "Бераш, ден", OriginalClean: "бераш, ден", OriginalLang: "CHE", TranslationRaw: "дети", TranslationClean: "дети", TranslationLang: "RUS"},
+	} {
+		p.Source = "api"
+		if _, _, err := repo.InsertTranslationPair(ctx, p); err != nil {
+			t.Fatalf("insert %q: %v", p.OriginalRaw, err)
+		}
+	}
+	if _, err := repo.StoreEntryForms(ctx, "Дийца", "дийца", []string{"дийца", "дийцира"}); err != nil {
+		t.Fatalf("StoreEntryForms: %v", err)
+	}
+
+	b := &Business{log: logrus.New(), dictRepo: repo}
+
+	// Хьекъал and кӏорни are five letters; гӏала is four, and the bare form
+	// дийца is a guess but not an answer, as is nothing from a gloss.
+	answers := b.gameVocabulary(ctx).answers
+	if len(answers) != 2 || answers[0] != "кӏорни" || answers[1] != "хьекъал" {
+		t.Fatalf("answers = %q, want [кӏорни хьекъал]", answers)
+	}
+	for word, want := range map[string]bool{
+		"Хьекъал": true,
+		"к1орни":  true,
+		"дийца":   true,
+		"дийцира": false,
+		"гӏала":   false,
+		"бераш":   false,
+	} {
+		if got := b.IsWordleWord(ctx, word); got != want {
+			t.Errorf("IsWordleWord(%q) = %v, want %v", word, got, want)
+		}
+	}
+
+	first, err := b.WordleWord(ctx, "2026-10-16")
+	if err != nil {
+		t.Fatalf("WordleWord: %v", err)
+	}
+	if again, _ := b.WordleWord(ctx, "2026-10-16"); again != first {
+		t.Errorf("WordleWord twice = %q, %q; want the same word for the day", first, again)
+	}
+}
+
+// countingGamesRepo counts Chechen lexicon reads, each slow enough for
+// concurrent first requests to overlap.
+type countingGamesRepo struct {
+	recordingDictRepo
+	reads atomic.Int32
+}
+
+func (r *countingGamesRepo) ListChechenLexicon(context.Context) ([]string, []string, error) {
+	r.reads.Add(1)
+	time.Sleep(50 * time.Millisecond)
+	return []string{"хьекъал"}, nil, nil
+}
+
+func TestGameVocabulary_ConcurrentFirstRequestsBuildOnce(t *testing.T) {
+	repo := &countingGamesRepo{}
+	b := &Business{log: logrus.New(), dictRepo: repo}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if len(b.gameVocabulary(context.Background()).answers) != 1 {
+				t.Error("gameVocabulary answers empty, want the built index")
+			}
+		})
+	}
+	wg.Wait()
+	if n := repo.reads.Load(); n != 1 {
+		t.Fatalf("lexicon read %d times, want once", n)
+	}
+}
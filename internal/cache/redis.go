@@ -171,19 +171,6 @@ func (c *Cache) RememberWordOfDay(ctx context.Context, word string) error {
 	return err
 }
 
-// SetQuizPoll remembers the correct option for a sent quiz poll so the answer
-// can be graded when a poll_answer update arrives. Short-lived — polls are
-// answered within minutes.
-func (c *Cache) SetQuizPoll(ctx context.Context, pollID string, correctOption int) error {
-	return c.client.Set(ctx, "quizpoll_"+pollID, correctOption, 24*time.Hour).Err()
-}
-
-// GetQuizPoll returns the stored correct option for a quiz poll, or an error if
-// it is unknown/expired.
-func (c *Cache) GetQuizPoll(ctx context.Context, pollID string) (int, error) {
-	return c.client.Get(ctx, "quizpoll_"+pollID).Int()
-}
-
 // grammarCacheEntry wraps a grammar lookup so a "no grammar" answer can be
 // cached too: most lookups have no analyzed grammar, and negative caching spares
 // the bot from re-running 1–2 live dosham queries for them on every repeat.
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"time"
+)
+
 // TranslationResponse models the dosham.app GraphQL API (https://api.dosham.app/gql).
 // The `find` query returns a flat list of entries directly.
 type TranslationResponse struct {
@@ -47,6 +52,16 @@ type TranslationPairs struct {
 	// cmd/parse_articles pass. Empty means the card falls back to the regex
 	// parser; the shape of the card is the same either way.
 	Structured string `json:"structured,omitempty"`
+	// FormOf is set when the query was an inflected Chechen form and these are
+	// the pairs of its headword: the card renders for FormOf, not for what was
+	// typed, and says so.
+	FormOf string `json:"form_of,omitempty"`
+	// Respelled is the Cyrillic query these pairs were found for when what was
+	// typed was Latin — a wrong keyboard layout or the Latin Chechen alphabet.
+	Respelled string `json:"respelled,omitempty"`
+	// Provenance names the dictionary sources that returned this pair,
+	// comma-separated in priority order. Shown to admins only.
+	Provenance string `json:"provenance,omitempty"`
 }
 
 type ActivityType int8
@@ -56,6 +71,26 @@ const (
 	ActivityTypeInline ActivityType = 2
 )
 
+// LookupDirection is which way a lookup translated.
+type LookupDirection int8
+
+const (
+	// LookupDirectionUnknown is a miss: nothing came back to tell by.
+	LookupDirectionUnknown LookupDirection = 0
+	LookupChechenRussian   LookupDirection = 1
+	LookupRussianChechen   LookupDirection = 2
+)
+
+// Lookup is one text lookup in a user's /history: the query as typed, the
+// word it normalizes to, and whether the dictionary had it.
+type Lookup struct {
+	Query     string
+	Word      string
+	Direction LookupDirection
+	Hit       bool
+	At        time.Time
+}
+
 type DailyActivity struct {
 	ActiveUsers int
 	Calls       int
@@ -74,6 +109,53 @@ type MissingWord struct {
 type RandomWord struct {
 	Chechen string
 	Russian string
+	// Stressed is Chechen as the dictionary spells it, stress marks kept for
+	// the pronunciation line; "" when the entry marks no stress.
+	Stressed string
+	// Subtype and Rate are the entry's part of speech and source dictionary,
+	// as on TranslationPairs; 0 when the source does not say.
+	Subtype int
+	Rate    int
+}
+
+// RateAcademic is the Rate of the academic Chechen–Russian dictionary.
+const RateAcademic = 10000
+
+// QuizFilter narrows /quiz to one category of words: a part of speech
+// (Subtype) and/or a source dictionary (Rate), with the values
+// TranslationPairs documents. A zero field matches any word. Grammar asks
+// about the words' forms and set phrases instead of their translations.
+// Saved draws from the asker's saved words instead of the pool, and the
+// handler, which knows who is asking, draws them.
+type QuizFilter struct {
+	Subtype int
+	Rate    int
+	Grammar bool
+	Saved   bool
+}
+
+// Matches reports whether w belongs to the filter's category.
+func (f QuizFilter) Matches(w RandomWord) bool {
+	return (f.Subtype == 0 || w.Subtype == f.Subtype) && (f.Rate == 0 || w.Rate == f.Rate)
+}
+
+// GlossToken is one word of an interlinear gloss: the word as written, the
+// headword it was read as, and that headword's first sense. Known is false
+// for a word the dictionary has no entry for; Failed marks one that could not
+// be looked up at all, which is not the same as not existing.
+type GlossToken struct {
+	Word   string
+	Lemma  string
+	Gloss  string
+	Known  bool
+	Failed bool
+}
+
+// Gloss is a text read word by word. Truncated is set when the text ran past
+// the word limit and its tail was left unread.
+type Gloss struct {
+	Tokens    []GlossToken
+	Truncated bool
 }
 
 // WordGrammar is lightweight grammatical info about a Chechen headword, sourced
@@ -104,6 +186,100 @@ type QuizQuestion struct {
 	Options    []string
 	CorrectIdx int
 	Reversed   bool
+	// ReviewID is set on a /learn card: the word_reviews row the answer grades.
+	ReviewID int64
+	// Filter is the category the question was drawn from, which "next
+	// question" keeps asking about.
+	Filter QuizFilter
+	// Kind is what the question asks; the zero value is a translation.
+	Kind string
+	// Hint is shown beside a QuizKindPhrase prompt: the phrase's meaning.
+	Hint string
+}
+
+// QuizQuestion kinds. A grammar question is built from a word's WordGrammar:
+// its inflected forms and its set phrases.
+const (
+	QuizKindTranslation = ""
+	QuizKindForm        = "form"   // which option is a form of the prompt word
+	QuizKindIdiom       = "idiom"  // what the prompt set phrase means
+	QuizKindPhrase      = "phrase" // which word the prompt set phrase is missing
+)
+
+// WordReview is one word in a user's /learn deck and its SM-2 state (see
+// pkg/srs). HeadwordClean is the key the deck is unique on.
+type WordReview struct {
+	ID            int64
+	UserID        int64
+	Headword      string
+	HeadwordClean string
+	Russian       string
+	Ease          float64
+	IntervalDays  int
+	Repetitions   int
+	Lapses        int
+	DueAt         time.Time
+}
+
+// SavedWord is a word a user saved from a card for /mywords. HeadwordClean
+// is the key a word is saved once under, as in WordReview.
+type SavedWord struct {
+	ID            int64
+	UserID        int64
+	Headword      string
+	HeadwordClean string
+	Russian       string
+	SavedAt       time.Time
+}
+
+// Quiz answer modes: a /quiz question, a /learn review of the user's deck,
+// a daily challenge question, a duel's, a /quiz question drawn from the
+// user's own saved words — kept apart so it scores on no board — or a word
+// typed for /write, which scores as /quiz does but tests spelling.
+const (
+	QuizModeQuiz  = "quiz"
+	QuizModeLearn = "learn"
+	QuizModeDaily = "daily"
+	QuizModeDuel  = "duel"
+	QuizModeSaved = "saved"
+	QuizModeWrite = "write"
+)
+
+// QuizAnswer is one answered question, kept so the bot can tell which words
+// people struggle with. Latency is zero when the question's send time is
+// unknown.
+type QuizAnswer struct {
+	UserID        int64
+	Prompt        string
+	Reversed      bool
+	Chosen        string
+	CorrectOption string
+	Correct       bool
+	Latency       time.Duration
+	ChatType      string
+	Mode          string
+	// Kind is the question's QuizQuestion.Kind.
+	Kind string
+}
+
+// WordDifficulty is how a quiz prompt has fared: how often it was asked and
+// how often it was answered wrong.
+type WordDifficulty struct {
+	Prompt   string
+	Reversed bool
+	Attempts int
+	Wrong    int
+}
+
+// StoredQuiz is a sent quiz question as the bot keeps it, so an answer —
+// which carries only the question's ID and the chosen option — can be graded
+// and logged.
+type StoredQuiz struct {
+	QuizQuestion
+	ID       string
+	ChatID   int64 // 0 for questions stored before chats were
+	ChatType string
+	SentAt   time.Time
 }
 
 // QuizScorer is one row of the /top quiz leaderboard. Streak is the current
@@ -117,6 +293,159 @@ type QuizScorer struct {
 	Streak    int
 }
 
+// DailyAttempt is a user's one go at a day's challenge.
+type DailyAttempt struct {
+	QuizScorer // Correct so far; Total is the questions answered
+	Day        string
+	// Results has one entry per question answered, in order: true when the
+	// answer was right.
+	Results    []bool
+	StartedAt  time.Time
+	FinishedAt time.Time // zero until the last question is answered
+}
+
+// Finished reports whether every question of the attempt is answered.
+func (a DailyAttempt) Finished() bool {
+	return !a.FinishedAt.IsZero()
+}
+
+// Duel is a one-on-one quiz duel: both players answer the same Questions
+// and the one with more right answers wins.
+type Duel struct {
+	ID string
+	// InlineMessageID is the challenge posted through inline mode, where the
+	// score goes once the duel is over. Set on acceptance.
+	InlineMessageID string
+	Questions       []QuizQuestion
+	Challenger      DuelPlayer
+	Opponent        DuelPlayer // zero until someone accepts
+	CreatedAt       time.Time
+	AcceptedAt      time.Time // zero until accepted
+	FinishedAt      time.Time // zero until both have played and it is settled
+}
+
+// Player returns the duel's side played by userID, or nil when they are not
+// in it.
+func (d *Duel) Player(userID int64) *DuelPlayer {
+	switch {
+	case userID == d.Challenger.UserID:
+		return &d.Challenger
+	case d.Opponent.UserID != 0 && userID == d.Opponent.UserID:
+		return &d.Opponent
+	default:
+		return nil
+	}
+}
+
+// DuelPlayer is one side of a duel and how far through it they are.
+type DuelPlayer struct {
+	QuizScorer // Correct so far; Total is the questions answered
+	// Results has one entry per question answered, in order: true when the
+	// answer was right.
+	Results    []bool
+	FinishedAt time.Time // zero until the last question is answered
+}
+
+// DuelRating is a player's Elo duel rating and record.
+type DuelRating struct {
+	QuizScorer // the name; the tallies are Wins, Losses and Draws
+	Rating     int
+	Wins       int
+	Losses     int
+	Draws      int
+}
+
+// DuelSettlement is how a settled duel moved its players' ratings: Delta is
+// what the challenger gained and the opponent lost, negative when the
+// opponent won.
+type DuelSettlement struct {
+	Challenger DuelRating
+	Opponent   DuelRating
+	Delta      int
+}
+
+// WordleLength is how many letters a /wordle word has, a digraph counting
+// as one letter as the alphabet counts it.
+const WordleLength = 5
+
+// WordleGame is a user's game of a day's /wordle.
+type WordleGame struct {
+	Day       string
+	UserID    int64
+	Username  string
+	FirstName string
+	// Guesses are the words guessed, in order, folded as the day's word is.
+	Guesses    []string
+	Solved     bool
+	StartedAt  time.Time
+	FinishedAt time.Time // zero while the game is on
+}
+
+// Finished reports whether the game is over, solved or out of guesses.
+func (g WordleGame) Finished() bool {
+	return !g.FinishedAt.IsZero()
+}
+
+// Quiz season periods: the windows the time-limited leaderboards rank.
+const (
+	SeasonWeek  = "week"
+	SeasonMonth = "month"
+)
+
+// QuizSeason is one window of a time-limited leaderboard: [Start, End) in the
+// bot's local time, a week from Monday or a calendar month. Key names it in
+// the archive: "2026-W42", "2026-10".
+type QuizSeason struct {
+	Period string
+	Key    string
+	Start  time.Time
+	End    time.Time
+}
+
+// SeasonAt returns the season of period that t falls in.
+func SeasonAt(period string, t time.Time) QuizSeason {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if period == SeasonMonth {
+		start := day.AddDate(0, 0, 1-day.Day())
+		return QuizSeason{Period: period, Key: start.Format("2006-01"), Start: start, End: start.AddDate(0, 1, 0)}
+	}
+	start := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	year, week := start.ISOWeek()
+	return QuizSeason{Period: SeasonWeek, Key: fmt.Sprintf("%d-W%02d", year, week), Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+// Previous returns the season just before s.
+func (s QuizSeason) Previous() QuizSeason {
+	return SeasonAt(s.Period, s.Start.AddDate(0, 0, -1))
+}
+
+// SeasonWinner is a season's podium place, as archived when the season
+// closed.
+type SeasonWinner struct {
+	QuizScorer
+	Period string
+	Season string // QuizSeason.Key
+	Rank   int
+}
+
+// DoshamStats is the dosham client's view of its own traffic since process
+// start, for the admin /stats report.
+type DoshamStats struct {
+	BreakerOpen bool
+	Queries     []DoshamQueryStats
+}
+
+// DoshamQueryStats counts one kind of dosham query. Requests actually went out;
+// Shared joined an identical request already in flight; Rejected never left
+// because the breaker was open. Failures is a subset of Requests.
+type DoshamQueryStats struct {
+	Query    string
+	Requests int64
+	Shared   int64
+	Failures int64
+	Rejected int64
+}
+
 // GraphQL specific types (dosham.app /gql schema)
 type Entry struct {
 	EntryID string `json:"entryId"`
@@ -33,50 +33,78 @@ func (n *Net) HandleStart(m *tgbotapi.Message) error {
 }
 
 // HandleMe shows the user their own progress: lookups, quiz score, streak,
-// Word of the Day subscription. Visible progress is its own motivator for
-// daily practice.
+// the words they miss most, /learn words due, Word of the Day subscription.
+// Visible progress is its own motivator for daily practice.
 func (n *Net) HandleMe(ctx context.Context, m *tgbotapi.Message) error {
-	lookups, err := n.repo.CountUserActivity(ctx, m.From.ID)
-	if err != nil {
+	var d meData
+	var err error
+	if d.lookups, err = n.repo.CountUserActivity(ctx, m.From.ID); err != nil {
 		return fmt.Errorf("repo.CountUserActivity: %w", err)
 	}
-	correct, total, streak, err := n.repo.GetQuizScore(ctx, m.From.ID)
-	if err != nil {
+	if d.correct, d.total, d.streak, err = n.repo.GetQuizScore(ctx, m.From.ID); err != nil {
 		return fmt.Errorf("repo.GetQuizScore: %w", err)
 	}
-	wotdSubscribed, err := n.repo.IsWordOfDaySubscribed(ctx, m.From.ID)
-	if err != nil {
+	if d.learnDue, d.learnTotal, err = n.repo.CountDueWordReviews(ctx, m.From.ID, time.Now()); err != nil {
+		return fmt.Errorf("repo.CountDueWordReviews: %w", err)
+	}
+	if d.wotdSubscribed, err = n.repo.IsWordOfDaySubscribed(ctx, m.From.ID); err != nil {
 		return fmt.Errorf("repo.IsWordOfDaySubscribed: %w", err)
 	}
-	rank := 0
-	if total > 0 {
-		if rank, err = n.repo.GetQuizRank(ctx, m.From.ID); err != nil {
+	if d.total > 0 {
+		if d.rank, err = n.repo.GetQuizRank(ctx, m.From.ID); err != nil {
 			return fmt.Errorf("repo.GetQuizRank: %w", err)
 		}
+		if d.weakest, err = n.repo.WeakestQuizWords(ctx, m.From.ID, MeWeakestWordsLimit); err != nil {
+			return fmt.Errorf("repo.WeakestQuizWords: %w", err)
+		}
 	}
 
-	msg := tgbotapi.NewMessage(m.Chat.ID, buildMeMessage(lookups, correct, total, streak, rank, wotdSubscribed))
+	msg := tgbotapi.NewMessage(m.Chat.ID, buildMeMessage(d))
 	msg.ParseMode = "html"
 	_, err = n.send(msg)
 	return err
 }
 
-func buildMeMessage(lookups, correct, total, streak, rank int, wotdSubscribed bool) string {
+// meData bundles every figure shown on /me.
+type meData struct {
+	lookups                      int
+	correct, total, streak, rank int
+	learnDue, learnTotal         int
+	wotdSubscribed               bool
+	weakest                      []models.WordDifficulty
+}
+
+func buildMeMessage(d meData) string {
 	var b strings.Builder
 	b.WriteString("👤 <b>Ваш прогресс</b>\n\n")
-	fmt.Fprintf(&b, "🔎 Поисков в словаре: <b>%s</b>\n", formatThousands(lookups))
-	if total > 0 {
-		fmt.Fprintf(&b, "🧠 Викторина: <b>%d/%d</b> (%d%%)\n", correct, total, correct*100/total)
-		if streak >= 2 {
-			fmt.Fprintf(&b, "🔥 Серия: <b>%d дн.</b>\n", streak)
+	fmt.Fprintf(&b, "🔎 Поисков в словаре: <b>%s</b>\n", formatThousands(d.lookups))
+	if d.total > 0 {
+		fmt.Fprintf(&b, "🧠 Викторина: <b>%d/%d</b> (%d%%)\n", d.correct, d.total, d.correct*100/d.total)
+		if d.streak >= 2 {
+			fmt.Fprintf(&b, "🔥 Серия: <b>%d дн.</b>\n", d.streak)
 		}
-		if rank > 0 {
-			fmt.Fprintf(&b, "🏆 Место в /top: <b>№%d</b>\n", rank)
+		if d.rank > 0 {
+			fmt.Fprintf(&b, "🏆 Место в /top: <b>№%d</b>\n", d.rank)
+		}
+		if len(d.weakest) > 0 {
+			words := make([]string, 0, len(d.weakest))
+			for _, w := range d.weakest {
+				words = append(words, tgbotapi.EscapeText(tgbotapi.ModeHTML, w.Prompt))
+			}
+			fmt.Fprintf(&b, "🤔 Чаще всего ошибки: %s\n", strings.Join(words, ", "))
 		}
 	} else {
 		b.WriteString("🧠 Викторина: попробуйте /quiz!\n")
 	}
-	if wotdSubscribed {
+	switch {
+	case d.learnDue > 0:
+		fmt.Fprintf(&b, "📚 К повторению: <b>%d</b> из %d — /learn\n", d.learnDue, d.learnTotal)
+	case d.learnTotal > 0:
+		fmt.Fprintf(&b, "📚 К повторению: всё повторено (слов: %d)\n", d.learnTotal)
+	default:
+		b.WriteString("📚 Учить слова: попробуйте /learn\n")
+	}
+	if d.wotdSubscribed {
 		b.WriteString("📖 Слово дня: <b>включено</b>\n")
 	} else {
 		b.WriteString("📖 Слово дня: выключено — включить через /wotd\n")
@@ -156,6 +184,7 @@ func (n *Net) HandleStats(ctx context.Context, m *tgbotapi.Message) error {
 		quizCorrect:     quizCorrect,
 		cacheHits:       cacheHits,
 		cacheMisses:     cacheMisses,
+		dosham:          n.business.DoshamStats(),
 		daily:           dailyActiveUsersLastMonth,
 	})
 
@@ -199,6 +228,40 @@ func (n *Net) HandleMissingWords(ctx context.Context, m *tgbotapi.Message) error
 	return err
 }
 
+// HandleHardestWords lists the quiz prompts players miss most, by error rate
+// (admin-only): the words the quiz, /learn and the dictionary cards should
+// work harder on.
+func (n *Net) HandleHardestWords(ctx context.Context, m *tgbotapi.Message) error {
+	if !n.isAdmin(m.From.ID) {
+		return nil
+	}
+
+	words, err := n.repo.HardestQuizWords(ctx, HardestWordsMinAttempts, HardestWordsLimit)
+	if err != nil {
+		return fmt.Errorf("repo.HardestQuizWords: %w", err)
+	}
+	if len(words) == 0 {
+		_, err = n.send(tgbotapi.NewMessage(m.Chat.ID, HardestWordsEmpty))
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(HardestWordsHeader)
+	for i, w := range words {
+		arrow := "→ ru"
+		if w.Reversed {
+			arrow = "→ ce"
+		}
+		fmt.Fprintf(&sb, HardestWordRowFormat, i+1, tgbotapi.EscapeText(tgbotapi.ModeHTML, w.Prompt), arrow,
+			w.Wrong, w.Attempts, w.Wrong*100/w.Attempts)
+	}
+
+	msg := tgbotapi.NewMessage(m.Chat.ID, sb.String())
+	msg.ParseMode = "html"
+	_, err = n.send(msg)
+	return err
+}
+
 var russianMonths = [...]string{
 	"", "Январь", "Февраль", "Март", "Апрель", "Май", "Июнь",
 	"Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь",
@@ -220,6 +283,7 @@ type statsData struct {
 	quizCorrect     int
 	cacheHits       int64
 	cacheMisses     int64
+	dosham          models.DoshamStats
 	daily           []models.DailyActivity
 }
 
@@ -275,6 +339,22 @@ func buildStatsMessage(d statsData) string {
 			formatThousands(int(d.cacheHits)), formatThousands(int(lookups)), d.cacheHits*100/lookups)
 	}
 
+	if len(d.dosham.Queries) > 0 || d.dosham.BreakerOpen {
+		b.WriteString("🌐 <b>dosham</b> <i>(с перезапуска)</i>\n")
+		if d.dosham.BreakerOpen {
+			b.WriteString("⛔ Предохранитель <b>разомкнут</b> — запросы не уходят\n")
+		}
+		for _, q := range d.dosham.Queries {
+			fmt.Fprintf(&b, "%s: <b>%s</b> запросов · %s склеено · %s ошибок",
+				q.Query, formatThousands(int(q.Requests)), formatThousands(int(q.Shared)), formatThousands(int(q.Failures)))
+			if q.Rejected > 0 {
+				fmt.Fprintf(&b, " · %s отбито", formatThousands(int(q.Rejected)))
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteByte('\n')
+	}
+
 	b.WriteString("📅 <b>По дням</b> <i>(день · 🟢 активных · 🔁 вызовов)</i>\n")
 	if activeDays == 0 {
 		b.WriteString("<i>Пока нет активности в этом месяце</i>")
@@ -1,6 +1,7 @@
 package net
 
 import (
+	"chetoru/internal/models"
 	"strings"
 	"testing"
 )
@@ -31,26 +32,50 @@ func TestBuildStatsMessage_EngagementLines(t *testing.T) {
 }
 
 func TestBuildMeMessage(t *testing.T) {
-	msg := buildMeMessage(1234, 8, 10, 3, 7, true)
-	for _, want := range []string{"1 234", "8/10", "(80%)", "Серия: <b>3 дн.</b>", "Место в /top: <b>№7</b>", "Слово дня: <b>включено</b>"} {
+	msg := buildMeMessage(meData{lookups: 1234, correct: 8, total: 10, streak: 3, rank: 7, learnDue: 4, learnTotal: 25, wotdSubscribed: true,
+		weakest: []models.WordDifficulty{{Prompt: "дитт"}, {Prompt: "цӏа"}}})
+	for _, want := range []string{"1 234", "8/10", "(80%)", "Серия: <b>3 дн.</b>", "Место в /top: <b>№7</b>", "Слово дня: <b>включено</b>", "К повторению: <b>4</b> из 25", "ошибки: дитт, цӏа"} {
 		if !strings.Contains(msg, want) {
 			t.Errorf("missing %q:\n%s", want, msg)
 		}
 	}
 
 	// Unranked players (below the 3-answer bar) see no rank line.
-	if msg := buildMeMessage(5, 1, 2, 0, 0, true); strings.Contains(msg, "Место") {
+	if msg := buildMeMessage(meData{lookups: 5, correct: 1, total: 2, learnTotal: 3, wotdSubscribed: true}); strings.Contains(msg, "Место") {
 		t.Errorf("rank line must be hidden when unranked:\n%s", msg)
 	}
 
-	msg = buildMeMessage(0, 0, 0, 0, 0, false)
+	msg = buildMeMessage(meData{})
 	if !strings.Contains(msg, "попробуйте /quiz") {
 		t.Errorf("expected quiz nudge when never played:\n%s", msg)
 	}
 	if !strings.Contains(msg, "включить через /wotd") {
 		t.Errorf("expected wotd nudge when unsubscribed:\n%s", msg)
 	}
+	if !strings.Contains(msg, "попробуйте /learn") {
+		t.Errorf("expected learn nudge with an empty deck:\n%s", msg)
+	}
+	if strings.Contains(msg, "ошибки") {
+		t.Errorf("weak-words line must be hidden when there are none:\n%s", msg)
+	}
 	if strings.Contains(msg, "Серия") {
 		t.Errorf("streak line must be hidden below 2 days:\n%s", msg)
 	}
 }
+
+func TestBuildStatsMessage_DoshamSection(t *testing.T) {
+	msg := buildStatsMessage(statsData{month: 6, year: 2026, dosham: models.DoshamStats{
+		BreakerOpen: true,
+		Queries:     []models.DoshamQueryStats{{Query: "find", Requests: 1200, Shared: 30, Failures: 2, Rejected: 7}},
+	}})
+	for _, want := range []string{"разомкнут", "find: <b>1 200</b> запросов · 30 склеено · 2 ошибок · 7 отбито"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("missing %q:\n%s", want, msg)
+		}
+	}
+
+	// No traffic yet: no section.
+	if msg := buildStatsMessage(statsData{month: 6, year: 2026}); strings.Contains(msg, "dosham") {
+		t.Fatalf("dosham section must be omitted with no traffic:\n%s", msg)
+	}
+}
@@ -0,0 +1,349 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandleDaily serves /daily: today's challenge, or its result once played,
+// and «/daily top» for today's leaderboard. The attempt is personal — one per
+// user per day, with a timer running — so in a group only the leaderboard is
+// served.
+func (n *Net) HandleDaily(ctx context.Context, m *tgbotapi.Message) error {
+	switch strings.ToLower(strings.TrimSpace(m.CommandArguments())) {
+	case "top", "топ":
+		return n.HandleDailyTop(ctx, m.Chat.ID, m.From.ID)
+	}
+	if isGroup(m.Chat) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, DailyPrivateOnlyText))
+		return err
+	}
+	if err := n.repo.StoreUser(ctx, int(m.From.ID), m.From.UserName); err != nil {
+		return fmt.Errorf("repo.StoreUser: %w", err)
+	}
+
+	day := time.Now().Format(time.DateOnly)
+	questions, err := n.dailyQuestions(ctx, day)
+	if err != nil {
+		n.log.WithError(err).Warn("daily: no challenge")
+		_, sErr := n.send(tgbotapi.NewMessage(m.Chat.ID, DailyErrorText))
+		return sErr
+	}
+	attempt, err := n.repo.StartDailyAttempt(ctx, day, m.From.ID, m.From.UserName, m.From.FirstName)
+	if err != nil {
+		return fmt.Errorf("repo.StartDailyAttempt: %w", err)
+	}
+
+	var msg tgbotapi.MessageConfig
+	if attempt.Finished() {
+		msg = tgbotapi.NewMessage(m.Chat.ID, n.dailyResultText(ctx, attempt))
+		msg.ReplyMarkup = dailyResultButtons()
+	} else {
+		// Resuming after /daily again picks up at the first unanswered
+		// question; the clock kept running meanwhile.
+		i := len(attempt.Results)
+		msg = tgbotapi.NewMessage(m.Chat.ID, dailyQuestionText(i, len(questions), &questions[i]))
+		msg.ReplyMarkup = dailyQuestionButtons(day, i, &questions[i])
+	}
+	msg.ParseMode = "html"
+	_, err = n.send(msg)
+	return err
+}
+
+// dailyQuestions returns day's challenge, generating and storing it if this
+// is the day's first player. Everyone reads the stored copy, so two first
+// players racing get the same questions too.
+func (n *Net) dailyQuestions(ctx context.Context, day string) ([]models.QuizQuestion, error) {
+	questions, err := n.repo.GetDailyChallenge(ctx, day)
+	if err != nil || questions != nil {
+		return questions, err
+	}
+	generated, err := n.business.DailyChallenge(ctx, day)
+	if err != nil {
+		return nil, fmt.Errorf("business.DailyChallenge: %w", err)
+	}
+	if err := n.repo.SaveDailyChallenge(ctx, day, generated); err != nil {
+		return nil, fmt.Errorf("repo.SaveDailyChallenge: %w", err)
+	}
+	questions, err = n.repo.GetDailyChallenge(ctx, day)
+	if err == nil && questions == nil {
+		err = fmt.Errorf("challenge for %s not stored", day)
+	}
+	return questions, err
+}
+
+// dailyQuestionText renders question i of a challenge of total questions.
+func dailyQuestionText(i, total int, q *models.QuizQuestion) string {
+	return fmt.Sprintf(DailyQuestionFormat, i+1, total, quizAsk(q), tgbotapi.EscapeText(tgbotapi.ModeHTML, q.Prompt))
+}
+
+// quizAsk is the line asking a translation question of q's direction.
+func quizAsk(q *models.QuizQuestion) string {
+	if q.Reversed {
+		return QuizAskChechen
+	}
+	return QuizAskTranslation
+}
+
+// dailyQuestionButtons are question i's options. Unlike /quiz buttons they
+// can carry the question's place in the open: the challenge is stored under
+// its day, and the correct option is read from there, never from the button.
+func dailyQuestionButtons(day string, i int, q *models.QuizQuestion) tgbotapi.InlineKeyboardMarkup {
+	return quizOptionButtons(q, func(j int) string { return dailyAnswerData(day, i, j) })
+}
+
+// quizOptionButtons lays out q's options one per row, lettered, each with
+// the callback data returns for its index.
+func quizOptionButtons(q *models.QuizQuestion, data func(int) string) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(q.Options))
+	for j, opt := range q.Options {
+		letter := ""
+		if j < len(quizLetters) {
+			letter = quizLetters[j] + ". "
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(letter+opt, data(j)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// dailyAnswerData is the callback for option chosen of question i of day's
+// challenge: "daily_<day>_<i>_<chosen>".
+func dailyAnswerData(day string, i, chosen int) string {
+	return fmt.Sprintf("daily_%s_%d_%d", day, i, chosen)
+}
+
+// parseDailyAnswerData reads dailyAnswerData back.
+func parseDailyAnswerData(data string) (day string, i, chosen int, ok bool) {
+	parts := strings.Split(data, "_") // [daily day i chosen]
+	if len(parts) != 4 || parts[0] != "daily" {
+		return "", 0, 0, false
+	}
+	if _, err := time.Parse(time.DateOnly, parts[1]); err != nil {
+		return "", 0, 0, false
+	}
+	var err error
+	if i, err = strconv.Atoi(parts[2]); err != nil || i < 0 {
+		return "", 0, 0, false
+	}
+	if chosen, err = strconv.Atoi(parts[3]); err != nil || chosen < 0 {
+		return "", 0, 0, false
+	}
+	return parts[1], i, chosen, true
+}
+
+// dailyResultButtons offer sharing the result — through inline mode, so it
+// lands in whatever chat the player picks — and the day's leaderboard.
+func dailyResultButtons() tgbotapi.InlineKeyboardMarkup {
+	share := DailyShareQuery
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.InlineKeyboardButton{Text: DailyShareButtonText, SwitchInlineQuery: &share},
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(DailyTopButtonText, "daily_top"),
+		),
+	)
+}
+
+// HandleDailyCallback grades a challenge answer and moves the message on to
+// the next question, or to the result after the last. Callback data formats:
+// "daily_<day>_<i>_<chosen>" and "daily_top" (today's leaderboard). Only the
+// attempt's next unanswered question can be answered, so a double tap or an
+// old message's buttons change nothing.
+func (n *Net) HandleDailyCallback(ctx context.Context, cq *tgbotapi.CallbackQuery) error {
+	if cq.Data == "daily_top" {
+		if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, "")); err != nil {
+			n.log.WithError(err).Warn("failed to ack daily top callback")
+		}
+		return n.HandleDailyTop(ctx, cq.Message.Chat.ID, cq.From.ID)
+	}
+
+	day, i, chosen, ok := parseDailyAnswerData(cq.Data)
+	if !ok {
+		return fmt.Errorf("invalid daily callback %q", cq.Data)
+	}
+	questions, err := n.repo.GetDailyChallenge(ctx, day)
+	if err != nil {
+		return fmt.Errorf("repo.GetDailyChallenge: %w", err)
+	}
+	if i >= len(questions) || chosen >= len(questions[i].Options) {
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, QuizExpiredToast))
+		return err
+	}
+	q := &questions[i]
+	correct := chosen == q.CorrectIdx
+	recorded, err := n.repo.RecordDailyAnswer(ctx, day, cq.From.ID, i, correct, len(questions))
+	if err != nil {
+		return fmt.Errorf("repo.RecordDailyAnswer: %w", err)
+	}
+	if !recorded {
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, QuizAlreadyAnsweredToast))
+		return err
+	}
+
+	// The challenge feeds the answer log, for word difficulty, but not /top:
+	// everyone gets the same ten words, so they say nothing about who knows
+	// more words overall.
+	n.logQuestionAnswer(ctx, cq.From.ID, q, chosen, cq.Message.Chat.Type, models.QuizModeDaily)
+
+	toast := QuizWrongToast + ". " + fmt.Sprintf(QuizCorrectAnswerFormat, q.Options[q.CorrectIdx])
+	if correct {
+		toast = QuizCorrectToast
+	}
+	if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, toast)); err != nil {
+		n.log.WithError(err).Warn("failed to ack daily answer callback")
+	}
+
+	var edit tgbotapi.EditMessageTextConfig
+	if next := i + 1; next < len(questions) {
+		edit = tgbotapi.NewEditMessageTextAndMarkup(cq.Message.Chat.ID, cq.Message.MessageID,
+			dailyQuestionText(next, len(questions), &questions[next]), dailyQuestionButtons(day, next, &questions[next]))
+	} else {
+		attempt, err := n.repo.GetDailyAttempt(ctx, day, cq.From.ID)
+		if err != nil || attempt == nil {
+			return fmt.Errorf("repo.GetDailyAttempt: %w", err)
+		}
+		edit = tgbotapi.NewEditMessageTextAndMarkup(cq.Message.Chat.ID, cq.Message.MessageID,
+			n.dailyResultText(ctx, attempt), dailyResultButtons())
+	}
+	edit.ParseMode = "html"
+	if _, err := n.send(edit); err != nil {
+		return fmt.Errorf("bot.Send edit: %w", err)
+	}
+	return nil
+}
+
+// dailyResultText is a finished attempt's result with the player's place.
+func (n *Net) dailyResultText(ctx context.Context, a *models.DailyAttempt) string {
+	text := fmt.Sprintf(DailyResultFormat, dailyDayLabel(a.Day), answerGrid(a.Results), a.Correct, a.Total, formatDailyTime(a.FinishedAt.Sub(a.StartedAt)))
+	if rank, players, err := n.repo.GetDailyRank(ctx, a.Day, a.UserID); err != nil {
+		n.log.WithError(err).WithField("user_id", a.UserID).Warn("GetDailyRank failed")
+	} else if rank > 0 {
+		text += fmt.Sprintf(DailyRankFormat, rank, players)
+	}
+	return text + DailyComeBackText
+}
+
+// answerGrid is a run of answers as a row of ✅ and ❌ — the part of a
+// result worth sharing: it tells how it went without giving the answers away.
+func answerGrid(results []bool) string {
+	var b strings.Builder
+	for _, ok := range results {
+		if ok {
+			b.WriteString("✅")
+		} else {
+			b.WriteString("❌")
+		}
+	}
+	return b.String()
+}
+
+// dailyDayLabel shows a challenge's day as people write dates, 16.10.2026.
+func dailyDayLabel(day string) string {
+	t, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		return day
+	}
+	return t.Format("02.01.2006")
+}
+
+// formatDailyTime renders how long an attempt took: «48 с», «3 мин 05 с»,
+// and for the player who came back hours later «5 ч 12 мин».
+func formatDailyTime(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%d с", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%d мин %02d с", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%d ч %d мин", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// HandleDailyTop renders today's challenge leaderboard: the most correct
+// answers, ties going to the faster. A requester below the visible top gets
+// their own place appended.
+func (n *Net) HandleDailyTop(ctx context.Context, chatID, userID int64) error {
+	day := time.Now().Format(time.DateOnly)
+	attempts, err := n.repo.TopDailyScorers(ctx, day, DailyTopLimit)
+	if err != nil {
+		return fmt.Errorf("repo.TopDailyScorers: %w", err)
+	}
+	if len(attempts) == 0 {
+		_, err = n.send(tgbotapi.NewMessage(chatID, DailyTopEmptyText))
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, DailyTopHeaderFormat, dailyDayLabel(day))
+	shown := false
+	for i, a := range attempts {
+		shown = shown || a.UserID == userID
+		fmt.Fprintf(&b, "%s <b>%s</b> — %d/%d · %s\n", quizMedal(i), tgbotapi.EscapeText(tgbotapi.ModeHTML, scorerName(a.QuizScorer)), a.Correct, a.Total, formatDailyTime(a.FinishedAt.Sub(a.StartedAt)))
+	}
+	if !shown {
+		if rank, _, err := n.repo.GetDailyRank(ctx, day, userID); err != nil {
+			n.log.WithError(err).WithField("user_id", userID).Warn("GetDailyRank failed")
+		} else if rank > 0 {
+			fmt.Fprintf(&b, "\n👤 Вы: <b>№%d</b>\n", rank)
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	msg.ParseMode = "html"
+	_, err = n.send(msg)
+	return err
+}
+
+// HandleInlineDaily answers the share button's inline query with the user's
+// result for today, ready to post in any chat with a button that brings the
+// chat's members to their own attempt. The answer is personal and uncached:
+// it is this user's grid, and it changes when they finish.
+func (n *Net) HandleInlineDaily(ctx context.Context, iq *tgbotapi.InlineQuery) error {
+	day := time.Now().Format(time.DateOnly)
+	attempt, err := n.repo.GetDailyAttempt(ctx, day, iq.From.ID)
+	if err != nil {
+		return fmt.Errorf("repo.GetDailyAttempt: %w", err)
+	}
+	conf := tgbotapi.InlineConfig{
+		InlineQueryID: iq.ID,
+		IsPersonal:    true,
+		CacheTime:     0,
+		Results:       []any{},
+	}
+	if attempt == nil || !attempt.Finished() {
+		// Nothing to share yet: the button above the empty list opens the
+		// bot on /daily.
+		conf.SwitchPMText = DailyInlinePlayText
+		conf.SwitchPMParameter = dailyStartParameter
+		return n.answerInline(conf)
+	}
+
+	text := dailyShareText(attempt)
+	article := tgbotapi.NewInlineQueryResultArticle(iq.ID+"_daily", fmt.Sprintf(DailyInlineTitleFormat, attempt.Correct, attempt.Total), text)
+	article.Description = answerGrid(attempt.Results)
+	markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonURL(DailyAcceptButtonText, "https://t.me/"+n.bot.Self.UserName+"?start="+dailyStartParameter),
+	))
+	article.ReplyMarkup = &markup
+	conf.Results = []any{article}
+	return n.answerInline(conf)
+}
+
+// dailyStartParameter is the /start payload that opens the daily challenge.
+const dailyStartParameter = "daily"
+
+// dailyShareText is the message a shared result posts: plain text, since it
+// is also what the picker previews.
+func dailyShareText(a *models.DailyAttempt) string {
+	return fmt.Sprintf(DailyShareFormat, dailyDayLabel(a.Day), a.Correct, a.Total, answerGrid(a.Results), formatDailyTime(a.FinishedAt.Sub(a.StartedAt)))
+}
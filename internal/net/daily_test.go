@@ -0,0 +1,40 @@
+package net
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyAnswerData_RoundTrip(t *testing.T) {
+	data := dailyAnswerData("2026-10-16", 9, 3)
+	if len(data) > 64 {
+		t.Fatalf("callback data %q is over Telegram's 64 bytes", data)
+	}
+	day, i, chosen, ok := parseDailyAnswerData(data)
+	if !ok || day != "2026-10-16" || i != 9 || chosen != 3 {
+		t.Fatalf("parseDailyAnswerData(%q) = %q, %d, %d, %v", data, day, i, chosen, ok)
+	}
+	for _, bad := range []string{"daily_top", "daily_16.10.2026_1_2", "daily_2026-10-16_-1_0", "daily_2026-10-16_1", "quiz_2026-10-16_1_2"} {
+		if _, _, _, ok := parseDailyAnswerData(bad); ok {
+			t.Errorf("parseDailyAnswerData(%q) accepted", bad)
+		}
+	}
+}
+
+func TestDailyResultFormatting(t *testing.T) {
+	if got := answerGrid([]bool{true, false, true}); got != "✅❌✅" {
+		t.Errorf("answerGrid = %q", got)
+	}
+	for d, want := range map[time.Duration]string{
+		48 * time.Second:                  "48 с",
+		3*time.Minute + 5*time.Second:     "3 мин 05 с",
+		5*time.Hour + 12*time.Minute + 40: "5 ч 12 мин",
+	} {
+		if got := formatDailyTime(d); got != want {
+			t.Errorf("formatDailyTime(%v) = %q, want %q", d, got, want)
+		}
+	}
+	if got := dailyDayLabel("2026-10-16"); got != "16.10.2026" {
+		t.Errorf("dailyDayLabel = %q", got)
+	}
+}
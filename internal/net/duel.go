@@ -0,0 +1,461 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// duelQueryWords are the inline queries that offer a duel challenge instead
+// of a lookup. None is a Chechen word or one Russian speakers would look up
+// expecting a translation card.
+var duelQueryWords = map[string]bool{
+	"дуэль":  true,
+	"дуэли":  true,
+	"duel":   true,
+	"⚔️":     true,
+	"#дуэль": true,
+}
+
+// isDuelQuery reports whether an inline query asks for a duel challenge.
+func isDuelQuery(query string) bool {
+	return duelQueryWords[strings.ToLower(strings.TrimSpace(query))]
+}
+
+// duelStartPrefix starts the /start payload that opens a duel's questions:
+// "duel_<id>".
+const duelStartPrefix = "duel_"
+
+// duelLink is the deep link that opens the bot on a duel's questions.
+func (n *Net) duelLink(id string) string {
+	return "https://t.me/" + n.bot.Self.UserName + "?start=" + duelStartPrefix + id
+}
+
+// HandleInlineDuel answers a duel query with a challenge to post in the
+// current chat. The duel is opened now, before the user even picks the
+// result, because the posted message can only carry its ID in the accept
+// button; the ones never posted or never accepted are pruned. The answer is
+// personal and uncached: every challenge is a new duel.
+func (n *Net) HandleInlineDuel(ctx context.Context, iq *tgbotapi.InlineQuery) error {
+	id, err := n.repo.CreateDuel(ctx, iq.From.ID, iq.From.UserName, iq.From.FirstName)
+	if err != nil {
+		return fmt.Errorf("repo.CreateDuel: %w", err)
+	}
+	name := scorerName(models.QuizScorer{Username: iq.From.UserName, FirstName: iq.From.FirstName})
+	article := tgbotapi.NewInlineQueryResultArticle(iq.ID+"_duel", DuelInlineTitle, "")
+	article.Description = fmt.Sprintf(DuelInlineDescriptionFormat, DuelQuestions)
+	article.InputMessageContent = tgbotapi.InputTextMessageContent{
+		Text:      fmt.Sprintf(DuelChallengeFormat, tgbotapi.EscapeText(tgbotapi.ModeHTML, name), DuelQuestions),
+		ParseMode: "html",
+	}
+	markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(DuelAcceptButtonText, "duel_a_"+id),
+	))
+	article.ReplyMarkup = &markup
+	return n.answerInline(tgbotapi.InlineConfig{
+		InlineQueryID: iq.ID,
+		IsPersonal:    true,
+		CacheTime:     0,
+		Results:       []any{article},
+	})
+}
+
+// HandleDuelCallback routes the duel buttons. Callback data formats:
+// "duel_a_<duel>" (accept a posted challenge) and
+// "duel_q_<duel>_<i>_<chosen>" (answer a duel question).
+func (n *Net) HandleDuelCallback(ctx context.Context, cq *tgbotapi.CallbackQuery) error {
+	if id, ok := strings.CutPrefix(cq.Data, "duel_a_"); ok {
+		return n.acceptDuel(ctx, cq, id)
+	}
+	id, i, chosen, ok := parseDuelAnswerData(cq.Data)
+	if !ok {
+		return fmt.Errorf("invalid duel callback %q", cq.Data)
+	}
+	return n.answerDuel(ctx, cq, id, i, chosen)
+}
+
+// duelAnswerData is the callback for option chosen of question i of a duel.
+func duelAnswerData(id string, i, chosen int) string {
+	return fmt.Sprintf("duel_q_%s_%d_%d", id, i, chosen)
+}
+
+// parseDuelAnswerData reads duelAnswerData back.
+func parseDuelAnswerData(data string) (id string, i, chosen int, ok bool) {
+	parts := strings.Split(data, "_") // [duel q id i chosen]
+	if len(parts) != 5 || parts[0] != "duel" || parts[1] != "q" || parts[2] == "" {
+		return "", 0, 0, false
+	}
+	var err error
+	if i, err = strconv.Atoi(parts[3]); err != nil || i < 0 {
+		return "", 0, 0, false
+	}
+	if chosen, err = strconv.Atoi(parts[4]); err != nil || chosen < 0 {
+		return "", 0, 0, false
+	}
+	return parts[2], i, chosen, true
+}
+
+// acceptDuel makes the tapper the challenge's opponent. The questions are
+// drawn here, once, so both sides answer the same ones; then the posted
+// challenge turns into a «duel under way» card with a link to the questions,
+// the tapper is sent there by the callback answer, and the challenger gets
+// the first question in their private chat.
+func (n *Net) acceptDuel(ctx context.Context, cq *tgbotapi.CallbackQuery, id string) error {
+	d, err := n.repo.GetDuel(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repo.GetDuel: %w", err)
+	}
+	switch {
+	case d == nil || (d.AcceptedAt.IsZero() && time.Since(d.CreatedAt) > DuelTTL):
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, DuelExpiredToast))
+		return err
+	case cq.From.ID == d.Challenger.UserID && d.AcceptedAt.IsZero():
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, DuelOwnChallengeToast))
+		return err
+	case d.Player(cq.From.ID) != nil:
+		// Either side tapping an accepted challenge again: back to the
+		// questions.
+		return n.openDuelLink(cq, id)
+	case !d.AcceptedAt.IsZero():
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, DuelTakenToast))
+		return err
+	}
+
+	questions := make([]models.QuizQuestion, 0, DuelQuestions)
+	for range DuelQuestions {
+		q, err := n.business.GenerateQuiz(ctx, models.QuizFilter{})
+		if err != nil {
+			n.log.WithError(err).Warn("duel: GenerateQuiz failed")
+			_, sErr := n.bot.Request(tgbotapi.NewCallback(cq.ID, DuelErrorToast))
+			return sErr
+		}
+		questions = append(questions, *q)
+	}
+	accepted, err := n.repo.AcceptDuel(ctx, id, cq.From.ID, cq.From.UserName, cq.From.FirstName, cq.InlineMessageID, questions)
+	if err != nil {
+		return fmt.Errorf("repo.AcceptDuel: %w", err)
+	}
+	if !accepted {
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, DuelTakenToast))
+		return err
+	}
+	if err := n.openDuelLink(cq, id); err != nil {
+		n.log.WithError(err).Warn("failed to ack duel accept callback")
+	}
+
+	if d, err = n.repo.GetDuel(ctx, id); err != nil || d == nil {
+		return fmt.Errorf("repo.GetDuel: %w", err)
+	}
+	if cq.InlineMessageID != "" {
+		text := fmt.Sprintf(DuelAcceptedFormat, duelName(d.Challenger), duelName(d.Opponent), DuelQuestions)
+		if err := n.editDuelInline(cq.InlineMessageID, text, n.duelPlayButton(id)); err != nil {
+			n.log.WithError(err).WithField("duel", id).Warn("duel: edit challenge")
+		}
+	}
+	// The challenger may never have opened the bot's private chat — they
+	// only used inline mode — and then cannot be written to; the card's
+	// link is their way in.
+	if err := n.sendDuelQuestion(ctx, d.Challenger.UserID, d, &d.Challenger); err != nil {
+		n.log.WithError(err).WithField("user_id", d.Challenger.UserID).Info("duel: challenger not reachable")
+	}
+	return nil
+}
+
+// openDuelLink answers a callback by opening the bot on the duel's
+// questions: Telegram follows a t.me link to the bot given as a callback
+// answer's URL.
+func (n *Net) openDuelLink(cq *tgbotapi.CallbackQuery, id string) error {
+	answer := tgbotapi.NewCallback(cq.ID, "")
+	answer.URL = n.duelLink(id)
+	_, err := n.bot.Request(answer)
+	return err
+}
+
+// duelPlayButton links a duel card to the duel's questions.
+func (n *Net) duelPlayButton(id string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonURL(DuelPlayButtonText, n.duelLink(id)),
+	))
+}
+
+// editDuelInline rewrites the challenge posted through inline mode.
+func (n *Net) editDuelInline(inlineMessageID, text string, markup tgbotapi.InlineKeyboardMarkup) error {
+	edit := tgbotapi.EditMessageTextConfig{
+		BaseEdit:  tgbotapi.BaseEdit{InlineMessageID: inlineMessageID, ReplyMarkup: &markup},
+		Text:      text,
+		ParseMode: "html",
+	}
+	return n.request(edit)
+}
+
+// HandleDuelStart serves the duel deep link: the player's next question, or
+// where the duel stands once they have answered them all.
+func (n *Net) HandleDuelStart(ctx context.Context, m *tgbotapi.Message, id string) error {
+	d, err := n.repo.GetDuel(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repo.GetDuel: %w", err)
+	}
+	var p *models.DuelPlayer
+	if d != nil {
+		p = d.Player(m.From.ID)
+	}
+	switch {
+	case d == nil:
+		_, err = n.send(tgbotapi.NewMessage(m.Chat.ID, DuelExpiredText))
+		return err
+	case p == nil:
+		_, err = n.send(tgbotapi.NewMessage(m.Chat.ID, DuelNotYoursText))
+		return err
+	case d.AcceptedAt.IsZero():
+		_, err = n.send(tgbotapi.NewMessage(m.Chat.ID, DuelNotAcceptedText))
+		return err
+	}
+	if err := n.repo.StoreUser(ctx, int(m.From.ID), m.From.UserName); err != nil {
+		return fmt.Errorf("repo.StoreUser: %w", err)
+	}
+	if !p.FinishedAt.IsZero() {
+		msg := tgbotapi.NewMessage(m.Chat.ID, duelStatusText(d, p))
+		msg.ParseMode = "html"
+		_, err = n.send(msg)
+		return err
+	}
+	return n.sendDuelQuestion(ctx, m.Chat.ID, d, p)
+}
+
+// sendDuelQuestion sends p their first unanswered question of the duel.
+func (n *Net) sendDuelQuestion(ctx context.Context, chatID int64, d *models.Duel, p *models.DuelPlayer) error {
+	i := len(p.Results)
+	msg := tgbotapi.NewMessage(chatID, duelQuestionText(d, p, i))
+	msg.ParseMode = "html"
+	msg.ReplyMarkup = duelQuestionButtons(d, i)
+	_, err := n.send(msg)
+	return err
+}
+
+// duelQuestionText renders question i of the duel as p sees it.
+func duelQuestionText(d *models.Duel, p *models.DuelPlayer, i int) string {
+	q := &d.Questions[i]
+	return fmt.Sprintf(DuelQuestionFormat, duelName(*duelRival(d, p)), i+1, len(d.Questions), quizAsk(q), tgbotapi.EscapeText(tgbotapi.ModeHTML, q.Prompt))
+}
+
+func duelQuestionButtons(d *models.Duel, i int) tgbotapi.InlineKeyboardMarkup {
+	return quizOptionButtons(&d.Questions[i], func(j int) string { return duelAnswerData(d.ID, i, j) })
+}
+
+// duelRival is the other side of the duel from p.
+func duelRival(d *models.Duel, p *models.DuelPlayer) *models.DuelPlayer {
+	if p == &d.Challenger {
+		return &d.Opponent
+	}
+	return &d.Challenger
+}
+
+// duelName is a player's display name, HTML-escaped.
+func duelName(p models.DuelPlayer) string {
+	return tgbotapi.EscapeText(tgbotapi.ModeHTML, scorerName(p.QuizScorer))
+}
+
+// answerDuel grades a duel answer and moves the message on to the next
+// question. After a player's last answer it either waits for the rival or,
+// when the rival is done too, settles the duel.
+func (n *Net) answerDuel(ctx context.Context, cq *tgbotapi.CallbackQuery, id string, i, chosen int) error {
+	d, err := n.repo.GetDuel(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repo.GetDuel: %w", err)
+	}
+	if d == nil || d.Player(cq.From.ID) == nil || i >= len(d.Questions) || chosen >= len(d.Questions[i].Options) {
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, DuelExpiredToast))
+		return err
+	}
+	q := &d.Questions[i]
+	correct := chosen == q.CorrectIdx
+	recorded, err := n.repo.RecordDuelAnswer(ctx, id, cq.From.ID, i, correct, len(d.Questions))
+	if err != nil {
+		return fmt.Errorf("repo.RecordDuelAnswer: %w", err)
+	}
+	if !recorded {
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, QuizAlreadyAnsweredToast))
+		return err
+	}
+	// Like the daily challenge's, duel answers feed the answer log but not
+	// /top: the duel rating is their score.
+	n.logQuestionAnswer(ctx, cq.From.ID, q, chosen, cq.Message.Chat.Type, models.QuizModeDuel)
+
+	toast := QuizWrongToast + ". " + fmt.Sprintf(QuizCorrectAnswerFormat, q.Options[q.CorrectIdx])
+	if correct {
+		toast = QuizCorrectToast
+	}
+	if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, toast)); err != nil {
+		n.log.WithError(err).Warn("failed to ack duel answer callback")
+	}
+
+	if d, err = n.repo.GetDuel(ctx, id); err != nil || d == nil {
+		return fmt.Errorf("repo.GetDuel: %w", err)
+	}
+	p := d.Player(cq.From.ID)
+	if p.FinishedAt.IsZero() {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(cq.Message.Chat.ID, cq.Message.MessageID, duelQuestionText(d, p, len(p.Results)), duelQuestionButtons(d, len(p.Results)))
+		edit.ParseMode = "html"
+		if _, err := n.send(edit); err != nil {
+			return fmt.Errorf("bot.Send edit: %w", err)
+		}
+		return nil
+	}
+
+	s, err := n.repo.SettleDuel(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repo.SettleDuel: %w", err)
+	}
+	text := duelStatusText(d, p)
+	if s != nil {
+		text = duelResultText(d, s)
+	}
+	edit := tgbotapi.NewEditMessageText(cq.Message.Chat.ID, cq.Message.MessageID, text)
+	edit.ParseMode = "html"
+	if _, err := n.send(edit); err != nil {
+		return fmt.Errorf("bot.Send edit: %w", err)
+	}
+	if s != nil {
+		n.announceDuel(ctx, d, duelRival(d, p), text)
+	}
+	return nil
+}
+
+// announceDuel tells the rest of the world how a settled duel ended: the
+// rival, who finished first and was told to wait, and the chat the challenge
+// was posted in.
+func (n *Net) announceDuel(ctx context.Context, d *models.Duel, rival *models.DuelPlayer, text string) {
+	msg := tgbotapi.NewMessage(rival.UserID, text)
+	msg.ParseMode = "html"
+	if _, err := n.send(msg); err != nil {
+		if n.isBlockedError(err) {
+			if mErr := n.repo.MarkUserBlocked(ctx, rival.UserID, "duel_result"); mErr != nil {
+				n.log.WithError(mErr).WithField("user_id", rival.UserID).Warn("duel: mark blocked")
+			}
+		} else {
+			n.log.WithError(err).WithField("user_id", rival.UserID).Warn("duel: send result")
+		}
+	}
+	if d.InlineMessageID == "" {
+		return
+	}
+	rematch := DuelQuery
+	markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.InlineKeyboardButton{Text: DuelRematchButtonText, SwitchInlineQueryCurrentChat: &rematch},
+	))
+	if err := n.editDuelInline(d.InlineMessageID, text, markup); err != nil {
+		n.log.WithError(err).WithField("duel", d.ID).Warn("duel: edit challenge with result")
+	}
+}
+
+// duelStatusText is where the duel stands for p, who has answered all the
+// questions: waiting for the rival, or the result once settled.
+func duelStatusText(d *models.Duel, p *models.DuelPlayer) string {
+	if !d.FinishedAt.IsZero() {
+		return duelScoreText(d)
+	}
+	return fmt.Sprintf(DuelWaitingFormat, duelName(*duelRival(d, p)), answerGrid(p.Results), p.Correct, len(d.Questions))
+}
+
+// duelScoreText is a finished duel's score: both rows of ✅/❌ and the
+// winner.
+func duelScoreText(d *models.Duel) string {
+	var b strings.Builder
+	b.WriteString(DuelResultHeader)
+	for _, p := range []models.DuelPlayer{d.Challenger, d.Opponent} {
+		fmt.Fprintf(&b, DuelPlayerLineFormat, duelName(p), p.Correct, len(d.Questions), answerGrid(p.Results))
+	}
+	switch {
+	case d.Challenger.Correct > d.Opponent.Correct:
+		fmt.Fprintf(&b, DuelWinnerFormat, duelName(d.Challenger))
+	case d.Challenger.Correct < d.Opponent.Correct:
+		fmt.Fprintf(&b, DuelWinnerFormat, duelName(d.Opponent))
+	default:
+		b.WriteString(DuelDrawText)
+	}
+	return b.String()
+}
+
+// duelResultText is duelScoreText with how the duel moved the ratings,
+// shown once, when it is settled.
+func duelResultText(d *models.Duel, s *models.DuelSettlement) string {
+	return duelScoreText(d) + fmt.Sprintf(DuelRatingFormat,
+		duelName(d.Challenger), s.Challenger.Rating, s.Delta,
+		duelName(d.Opponent), s.Opponent.Rating, -s.Delta)
+}
+
+// HandleDuel serves /duel: how to challenge someone and the user's duel
+// rating, or with «top» the duel leaderboard.
+func (n *Net) HandleDuel(ctx context.Context, m *tgbotapi.Message) error {
+	switch strings.ToLower(strings.TrimSpace(m.CommandArguments())) {
+	case "top", "топ":
+		return n.HandleDuelTop(ctx, m.Chat.ID, m.From.ID)
+	}
+	text := DuelHelpText
+	rating, rank, err := n.repo.GetDuelRating(ctx, m.From.ID)
+	if err != nil {
+		n.log.WithError(err).WithField("user_id", m.From.ID).Warn("GetDuelRating failed")
+	} else if rating != nil {
+		text += fmt.Sprintf(DuelMyRatingFormat, rating.Rating, rank, rating.Wins, rating.Draws, rating.Losses)
+	}
+	query := DuelQuery
+	msg := tgbotapi.NewMessage(m.Chat.ID, text)
+	msg.ParseMode = "html"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.InlineKeyboardButton{Text: DuelInviteButtonText, SwitchInlineQuery: &query},
+	))
+	_, err = n.send(msg)
+	return err
+}
+
+// HandleDuelTop renders the duel leaderboard. A requester below the visible
+// top gets their own place appended.
+func (n *Net) HandleDuelTop(ctx context.Context, chatID, userID int64) error {
+	ratings, err := n.repo.TopDuelRatings(ctx, DuelTopLimit)
+	if err != nil {
+		return fmt.Errorf("repo.TopDuelRatings: %w", err)
+	}
+	if len(ratings) == 0 {
+		_, err = n.send(tgbotapi.NewMessage(chatID, DuelTopEmptyText))
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString(DuelTopHeader)
+	shown := false
+	for i, r := range ratings {
+		shown = shown || r.UserID == userID
+		fmt.Fprintf(&b, "%s <b>%s</b> — %d · %d–%d–%d\n", quizMedal(i), tgbotapi.EscapeText(tgbotapi.ModeHTML, scorerName(r.QuizScorer)), r.Rating, r.Wins, r.Draws, r.Losses)
+	}
+	if !shown {
+		if r, rank, err := n.repo.GetDuelRating(ctx, userID); err != nil {
+			n.log.WithError(err).WithField("user_id", userID).Warn("GetDuelRating failed")
+		} else if r != nil {
+			fmt.Fprintf(&b, "\n👤 Вы: <b>№%d</b> — %d\n", rank, r.Rating)
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	msg.ParseMode = "html"
+	_, err = n.send(msg)
+	return err
+}
+
+// pruneDuels drops duels never settled within DuelTTL: challenges nobody
+// accepted, or duels abandoned DuelTTL after they were accepted. Their
+// buttons then answer as expired.
+func (n *Net) pruneDuels(ctx context.Context) {
+	pruned, err := n.repo.PruneDuels(ctx, time.Now().Add(-DuelTTL))
+	if err != nil {
+		n.log.WithError(err).Warn("duels: prune")
+		return
+	}
+	if pruned > 0 {
+		n.log.Infof("duels: pruned %d stale", pruned)
+	}
+}
@@ -0,0 +1,59 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestDuelAnswerData_RoundTrip(t *testing.T) {
+	data := duelAnswerData("0123456789abcdef", 4, 2)
+	if len(data) > 64 {
+		t.Fatalf("callback data %q is over Telegram's 64 bytes", data)
+	}
+	id, i, chosen, ok := parseDuelAnswerData(data)
+	if !ok || id != "0123456789abcdef" || i != 4 || chosen != 2 {
+		t.Fatalf("parseDuelAnswerData(%q) = %q, %d, %d, %v", data, id, i, chosen, ok)
+	}
+	for _, bad := range []string{"duel_a_0123456789abcdef", "duel_q__1_2", "duel_q_ab_x_2", "duel_q_ab_1"} {
+		if _, _, _, ok := parseDuelAnswerData(bad); ok {
+			t.Errorf("parseDuelAnswerData(%q) accepted", bad)
+		}
+	}
+}
+
+func TestIsDuelQuery(t *testing.T) {
+	for _, q := range []string{"дуэль", " Дуэль ", "duel", "⚔️"} {
+		if !isDuelQuery(q) {
+			t.Errorf("isDuelQuery(%q) = false", q)
+		}
+	}
+	for _, q := range []string{"дуэлянт", "вызов", ""} {
+		if isDuelQuery(q) {
+			t.Errorf("isDuelQuery(%q) = true; it is a lookup", q)
+		}
+	}
+}
+
+func TestDuelTexts(t *testing.T) {
+	d := &models.Duel{
+		Questions:  make([]models.QuizQuestion, 3),
+		Challenger: models.DuelPlayer{QuizScorer: models.QuizScorer{UserID: 1, Username: "ali", Correct: 2}, Results: []bool{true, false, true}},
+		Opponent:   models.DuelPlayer{QuizScorer: models.QuizScorer{UserID: 2, FirstName: "<Зара>", Correct: 3}, Results: []bool{true, true, true}},
+	}
+	if p := d.Player(2); duelRival(d, p) != &d.Challenger {
+		t.Fatalf("duelRival(opponent) is not the challenger")
+	}
+	score := duelScoreText(d)
+	if !strings.Contains(score, "✅❌✅") || !strings.Contains(score, "Победа: <b>&lt;Зара&gt;</b>") {
+		t.Errorf("duelScoreText = %q", score)
+	}
+	d.Opponent.Correct = 2
+	if score := duelScoreText(d); !strings.Contains(score, DuelDrawText) {
+		t.Errorf("duelScoreText(tie) = %q, want a draw", score)
+	}
+	waiting := duelStatusText(d, &d.Challenger)
+	if !strings.Contains(waiting, "Дуэль с &lt;Зара&gt;") || !strings.Contains(waiting, "2/3") {
+		t.Errorf("duelStatusText(unsettled) = %q", waiting)
+	}
+}
@@ -0,0 +1,226 @@
+package net
+
+import (
+	"bytes"
+	"chetoru/internal/models"
+	"chetoru/pkg/anki"
+	"chetoru/pkg/tools"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// exportFormat is a file /export can build.
+type exportFormat string
+
+const (
+	exportAPKG exportFormat = "apkg"
+	exportCSV  exportFormat = "csv"
+	exportTSV  exportFormat = "tsv"
+)
+
+// parseExportFormat reads /export's argument: a format's name, with or
+// without its dot, or nothing for the Anki package.
+func parseExportFormat(args string) (exportFormat, bool) {
+	switch f := exportFormat(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(args)), ".")); f {
+	case "", "anki":
+		return exportAPKG, true
+	case exportAPKG, exportCSV, exportTSV:
+		return f, true
+	}
+	return "", false
+}
+
+// HandleExport serves /export: the user's saved words, then the Chechen
+// words in their /history, as a file to take to Anki — an .apkg by default,
+// or CSV or TSV text with «/export csv» and «/export tsv». Each word carries
+// its meaning, and the first ExportLookupLimit a usage example and a grammar
+// hint, as its card in the bot does. One export runs per user at a time, then
+// not again for ExportCooldown: the lookups behind it hold a dispatch slot.
+// The words are personal, so in a group it only points to the private chat.
+func (n *Net) HandleExport(ctx context.Context, m *tgbotapi.Message) error {
+	if isGroup(m.Chat) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, ExportPrivateOnlyText))
+		return err
+	}
+	format, ok := parseExportFormat(m.CommandArguments())
+	if !ok {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, ExportUsageText))
+		return err
+	}
+	words, _, err := n.repo.ListSavedWords(ctx, m.From.ID, 0, ExportMaxWords)
+	if err != nil {
+		return fmt.Errorf("repo.ListSavedWords: %w", err)
+	}
+	lookups, err := n.repo.ListLookups(ctx, m.From.ID, ExportMaxWords)
+	if err != nil {
+		return fmt.Errorf("repo.ListLookups: %w", err)
+	}
+	if len(words) == 0 && !hasChechenLookup(lookups) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, ExportEmptyText))
+		return err
+	}
+	if refusal, ok := n.beginExport(m.From.ID); !ok {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, refusal))
+		return err
+	}
+	defer n.endExport(m.From.ID)
+	// The history's meanings, examples and grammar are lookups; show the
+	// user something is coming while they run.
+	if _, err := n.bot.Request(tgbotapi.NewChatAction(m.Chat.ID, tgbotapi.ChatUploadDocument)); err != nil {
+		n.log.WithError(err).Debug("export: chat action failed")
+	}
+
+	notes := make([]anki.Note, 0, len(words))
+	for _, w := range words {
+		notes = append(notes, anki.Note{Front: tools.Clean(w.Headword), Back: tools.Clean(w.Russian)})
+	}
+	notes = append(notes, n.historyNotes(lookups, notes)...)
+	if len(notes) > ExportMaxWords {
+		notes = notes[:ExportMaxWords]
+	}
+	n.enrichExportNotes(ctx, notes)
+
+	var file bytes.Buffer
+	switch format {
+	case exportAPKG:
+		err = anki.WritePackage(ctx, &file, ExportDeckName, notes)
+	case exportCSV:
+		err = anki.WriteText(&file, notes, ',')
+	case exportTSV:
+		err = anki.WriteText(&file, notes, '\t')
+	}
+	if err != nil {
+		return fmt.Errorf("export %s: %w", format, err)
+	}
+
+	doc := tgbotapi.NewDocument(m.Chat.ID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("chetoru-%s.%s", time.Now().Format(time.DateOnly), format),
+		Bytes: file.Bytes(),
+	})
+	doc.Caption = fmt.Sprintf(ExportCaptionFormat, len(notes))
+	_, err = n.send(doc)
+	return err
+}
+
+// beginExport claims userID's export, or returns what to tell them instead:
+// that one is already being built, or how long the cooldown still has to run.
+func (n *Net) beginExport(userID int64) (string, bool) {
+	n.exportMu.Lock()
+	defer n.exportMu.Unlock()
+	if n.exportRunning[userID] {
+		return ExportBusyText, false
+	}
+	for id, done := range n.exportDone {
+		if time.Since(done) >= ExportCooldown {
+			delete(n.exportDone, id)
+		}
+	}
+	if done, ok := n.exportDone[userID]; ok {
+		wait := ExportCooldown - time.Since(done)
+		return fmt.Sprintf(ExportCooldownFormat, int(math.Ceil(wait.Minutes()))), false
+	}
+	n.exportRunning[userID] = true
+	return "", true
+}
+
+// endExport releases userID's export and starts its cooldown.
+func (n *Net) endExport(userID int64) {
+	n.exportMu.Lock()
+	defer n.exportMu.Unlock()
+	delete(n.exportRunning, userID)
+	n.exportDone[userID] = time.Now()
+}
+
+// hasChechenLookup reports whether any lookup found a Chechen word — one
+// historyNotes can turn into a note.
+func hasChechenLookup(lookups []models.Lookup) bool {
+	for _, l := range lookups {
+		if l.Hit && l.Direction == models.LookupChechenRussian {
+			return true
+		}
+	}
+	return false
+}
+
+// historyNotes turns the Chechen words the user found into notes, each with
+// the headword and sense its card opens with, as «⭐ Сохранить» would save
+// it. Each costs a lookup, so only the latest ExportLookupLimit are read.
+// Words already among have, or twice in the history under different
+// spellings, are left out. Russian lookups are too: their cards open on a
+// Russian word with a list of Chechen ones, which no single note is.
+func (n *Net) historyNotes(lookups []models.Lookup, have []anki.Note) []anki.Note {
+	var queries []string
+	for _, l := range lookups {
+		if len(queries) == min(ExportMaxWords-len(have), ExportLookupLimit) {
+			break
+		}
+		if l.Hit && l.Direction == models.LookupChechenRussian {
+			queries = append(queries, l.Query)
+		}
+	}
+	found := make([]*models.RandomWord, len(queries))
+	inParallel(len(queries), func(i int) {
+		w, err := n.business.CardWord(queries[i])
+		if err != nil {
+			n.log.WithError(err).WithField("word", queries[i]).Debug("export: history word lookup failed")
+		}
+		found[i] = w
+	})
+
+	seen := make(map[string]bool, len(have))
+	for _, note := range have {
+		seen[tools.NormalizeSearch(note.Front)] = true
+	}
+	var notes []anki.Note
+	for _, w := range found {
+		if w == nil || seen[tools.NormalizeSearch(w.Chechen)] {
+			continue
+		}
+		seen[tools.NormalizeSearch(w.Chechen)] = true
+		notes = append(notes, anki.Note{Front: tools.Clean(w.Chechen), Back: tools.Clean(w.Russian)})
+	}
+	return notes
+}
+
+// enrichExportNotes fills in the example and grammar hint of the first
+// ExportLookupLimit notes; the rest, and a word whose lookups fail, keep
+// their meaning and go without.
+func (n *Net) enrichExportNotes(ctx context.Context, notes []anki.Note) {
+	inParallel(min(len(notes), ExportLookupLimit), func(i int) {
+		if ex, ok := n.usageExample(notes[i].Front); ok {
+			notes[i].Example = ex
+		}
+		if g, err := n.business.GrammarFor(ctx, notes[i].Front); err == nil {
+			notes[i].Grammar = grammarSummaryText(g)
+		}
+	})
+}
+
+// inParallel runs fn for 0 to count-1, exportWorkers at a time like
+// business.Gloss, so a long list neither takes a lookup per word in turn
+// nor fills the dosham client at once.
+func inParallel(count int, fn func(i int)) {
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range min(exportWorkers, count) {
+		wg.Go(func() {
+			for i := range work {
+				fn(i)
+			}
+		})
+	}
+	for i := range count {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+}
+
+// exportWorkers is how many words /export looks up at once.
+const exportWorkers = 4
@@ -0,0 +1,53 @@
+package net
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		args string
+		want exportFormat
+		ok   bool
+	}{
+		{"", exportAPKG, true},
+		{"anki", exportAPKG, true},
+		{"apkg", exportAPKG, true},
+		{" .CSV ", exportCSV, true},
+		{"tsv", exportTSV, true},
+		{"xlsx", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parseExportFormat(tt.args)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseExportFormat(%q) = %q, %v; want %q, %v", tt.args, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBeginExport_OneAtATimeThenCooldown(t *testing.T) {
+	n := NewNet(logrus.New(), nil, nil, nil, nil, nil)
+	if _, ok := n.beginExport(1); !ok {
+		t.Fatal("first export refused")
+	}
+	if refusal, ok := n.beginExport(1); ok || refusal != ExportBusyText {
+		t.Fatalf("export while one runs = %q, %v; want busy", refusal, ok)
+	}
+	if _, ok := n.beginExport(2); !ok {
+		t.Fatal("another user's export refused")
+	}
+	n.endExport(1)
+	want := fmt.Sprintf(ExportCooldownFormat, int(ExportCooldown.Minutes()))
+	if refusal, ok := n.beginExport(1); ok || refusal != want {
+		t.Fatalf("export right after one = %q, %v; want %q", refusal, ok, want)
+	}
+
+	n.exportDone[1] = time.Now().Add(-ExportCooldown)
+	if _, ok := n.beginExport(1); !ok {
+		t.Fatal("export after the cooldown refused")
+	}
+}
@@ -0,0 +1,125 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// glossChunkRunes is how much of a gloss one message carries. It stays under
+// clampMessage's cut so a chunk is never truncated on the way out.
+const glossChunkRunes = 3500
+
+// HandleGloss answers /gloss with an interlinear reading of a text: each word,
+// the headword it belongs to and that headword's first sense. The text is the
+// command's argument, or — sent as a reply — the message replied to, so a
+// Chechen message in a group can be read where it was posted.
+func (n *Net) HandleGloss(ctx context.Context, m *tgbotapi.Message) error {
+	text := strings.TrimSpace(m.CommandArguments())
+	if text == "" && m.ReplyToMessage != nil {
+		text = strings.TrimSpace(m.ReplyToMessage.Text)
+		if text == "" {
+			text = strings.TrimSpace(m.ReplyToMessage.Caption)
+		}
+	}
+	if text == "" {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, GlossUsageText))
+		return err
+	}
+
+	go n.send(tgbotapi.NewChatAction(m.Chat.ID, tgbotapi.ChatTyping))
+
+	g := n.business.Gloss(text)
+	if len(g.Tokens) == 0 {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, GlossUsageText))
+		return err
+	}
+
+	// Unknown words are vocabulary gaps like any failed lookup. A word that
+	// could not be looked up is not — that was an outage.
+	seen := map[string]bool{}
+	for _, t := range g.Tokens {
+		clean := tools.NormalizeSearch(t.Word)
+		if t.Known || t.Failed || seen[clean] || !isRecordableMissingWord(clean) {
+			continue
+		}
+		seen[clean] = true
+		raw := t.Word
+		n.bg.Go(func() {
+			if err := n.repo.RecordMissingWord(ctx, clean, raw); err != nil {
+				n.log.WithError(err).WithField("word", clean).Warn("failed to record missing word")
+			}
+		})
+	}
+
+	lines := make([]string, 0, len(g.Tokens)+1)
+	for _, t := range g.Tokens {
+		lines = append(lines, glossLine(t))
+	}
+	if g.Truncated {
+		lines = append(lines, GlossTruncatedText)
+	}
+	for i, chunk := range chunkLines(lines, glossChunkRunes) {
+		if i == 0 {
+			chunk = GlossHeaderText + "\n\n" + chunk
+		}
+		msg := tgbotapi.NewMessage(m.Chat.ID, clampMessage(chunk))
+		msg.ParseMode = "html"
+		if i == 0 {
+			msg.ReplyToMessageID = m.MessageID
+			msg.AllowSendingWithoutReply = true
+		}
+		if _, err := n.send(msg); err != nil {
+			return fmt.Errorf("send gloss: %w", err)
+		}
+	}
+	return nil
+}
+
+// glossLine renders one word: «<b>шёл</b> · идти — <i>дӏаваха</i>». The
+// headword is left out when it is the word as typed.
+func glossLine(t models.GlossToken) string {
+	word := "<b>" + html.EscapeString(t.Word) + "</b>"
+	switch {
+	case t.Failed:
+		return word + " — " + GlossFailedMark
+	case !t.Known:
+		return word + " — " + GlossUnknownMark
+	}
+	line := word
+	if tools.NormalizeSearch(t.Lemma) != tools.NormalizeSearch(t.Word) {
+		line += " · " + html.EscapeString(t.Lemma)
+	}
+	return line + " — <i>" + html.EscapeString(t.Gloss) + "</i>"
+}
+
+// chunkLines packs lines into messages of at most limit runes each, breaking
+// only between lines so no HTML tag is split across two messages.
+func chunkLines(lines []string, limit int) []string {
+	var chunks []string
+	var cur strings.Builder
+	size := 0
+	for _, line := range lines {
+		n := utf8.RuneCountInString(line) + 1
+		if size > 0 && size+n > limit {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+			size = 0
+		}
+		if size > 0 {
+			cur.WriteString("\n")
+		}
+		cur.WriteString(line)
+		size += n
+	}
+	if size > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
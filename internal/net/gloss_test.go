@@ -0,0 +1,57 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGlossLine(t *testing.T) {
+	cases := []struct {
+		token models.GlossToken
+		want  string
+	}{
+		{models.GlossToken{Word: "шёл", Lemma: "Идти", Gloss: "даха", Known: true}, "<b>шёл</b> · Идти — <i>даха</i>"},
+		// The headword is the word as typed, so it is not repeated.
+		{models.GlossToken{Word: "г1ала", Lemma: "Гӏала", Gloss: "город", Known: true}, "<b>г1ала</b> — <i>город</i>"},
+		{models.GlossToken{Word: "зузук"}, "<b>зузук</b> — " + GlossUnknownMark},
+		{models.GlossToken{Word: "<b>", Failed: true}, "<b>&lt;b&gt;</b> — " + GlossFailedMark},
+	}
+	for _, c := range cases {
+		if got := glossLine(c.token); got != c.want {
+			t.Errorf("glossLine(%+v) = %q, want %q", c.token, got, c.want)
+		}
+	}
+}
+
+func TestChunkLines(t *testing.T) {
+	line := "<b>слово</b> — <i>перевод</i>"
+	lines := make([]string, 400)
+	for i := range lines {
+		lines[i] = line
+	}
+	chunks := chunkLines(lines, glossChunkRunes)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks for %d lines, want the text split", len(chunks), len(lines))
+	}
+	total := 0
+	for i, c := range chunks {
+		if n := utf8.RuneCountInString(c); n > glossChunkRunes {
+			t.Errorf("chunk %d is %d runes, over the limit", i, n)
+		}
+		// Every line arrives whole: no tag split across two messages.
+		for _, l := range strings.Split(c, "\n") {
+			if l != line {
+				t.Fatalf("chunk %d holds a broken line %q", i, l)
+			}
+			total++
+		}
+	}
+	if total != len(lines) {
+		t.Errorf("chunks carry %d lines, want %d", total, len(lines))
+	}
+	if chunkLines(nil, glossChunkRunes) != nil {
+		t.Error("no lines must make no chunks")
+	}
+}
@@ -124,4 +124,13 @@ func TestGrammarSummaryLine(t *testing.T) {
 			t.Errorf("headword-only: got %q", got)
 		}
 	})
+	t.Run("plain text is not escaped", func(t *testing.T) {
+		g := &models.WordGrammar{POS: "сущ.", Forms: []string{"a<b"}}
+		if got, want := grammarSummaryText(g), "сущ. · формы: a<b"; got != want {
+			t.Errorf("text: got %q, want %q", got, want)
+		}
+		if got, want := grammarSummaryLine(g), "сущ. · формы: a&lt;b"; got != want {
+			t.Errorf("line: got %q, want %q", got, want)
+		}
+	})
 }
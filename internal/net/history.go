@@ -0,0 +1,167 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// lookupFor is what /history keeps of a text lookup: the query, the word it
+// normalizes to, whether the dictionary had it, and which way it translated —
+// read, as the card does, for the word the answer is about.
+func lookupFor(query string, translations []models.TranslationPairs) models.Lookup {
+	l := models.Lookup{
+		Query: strings.TrimSpace(query),
+		Word:  tools.NormalizeSearch(query),
+		Hit:   len(translations) > 0,
+	}
+	if !l.Hit {
+		return l
+	}
+	if respelled := translations[0].Respelled; respelled != "" {
+		query = respelled
+	}
+	if headword := translations[0].FormOf; headword != "" {
+		query = headword
+	}
+	l.Direction = models.LookupRussianChechen
+	if _, ok := tools.ChechenHead(query, translations); ok {
+		l.Direction = models.LookupChechenRussian
+	}
+	return l
+}
+
+// HandleHistory serves /history: the user's recent lookups, each word once,
+// with a button to open each one found again. «/history clear» erases them,
+// «/history off» stops keeping them (and erases them), «/history on» starts
+// again. The history is personal, so in a group it only points to the
+// private chat.
+func (n *Net) HandleHistory(ctx context.Context, m *tgbotapi.Message) error {
+	if isGroup(m.Chat) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, HistoryPrivateOnlyText))
+		return err
+	}
+	// The opt-out lives on the user's row, which a user who never looked
+	// anything up does not have yet.
+	if err := n.repo.StoreUser(ctx, int(m.From.ID), m.From.UserName); err != nil {
+		return fmt.Errorf("repo.StoreUser: %w", err)
+	}
+
+	var reply string
+	switch strings.ToLower(strings.TrimSpace(m.CommandArguments())) {
+	case "":
+		return n.sendHistory(ctx, m.Chat.ID, m.From.ID)
+	case "off", "выкл":
+		if err := n.repo.SetHistoryOff(ctx, m.From.ID, true); err != nil {
+			return fmt.Errorf("repo.SetHistoryOff: %w", err)
+		}
+		reply = HistoryOffText
+	case "on", "вкл":
+		if err := n.repo.SetHistoryOff(ctx, m.From.ID, false); err != nil {
+			return fmt.Errorf("repo.SetHistoryOff: %w", err)
+		}
+		reply = HistoryOnText
+	case "clear", "очистить":
+		if _, err := n.repo.ClearLookups(ctx, m.From.ID); err != nil {
+			return fmt.Errorf("repo.ClearLookups: %w", err)
+		}
+		reply = HistoryClearedText
+	default:
+		reply = HistoryUsageText
+	}
+	_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, reply))
+	return err
+}
+
+func (n *Net) sendHistory(ctx context.Context, chatID, userID int64) error {
+	off, err := n.repo.IsHistoryOff(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("repo.IsHistoryOff: %w", err)
+	}
+	if off {
+		_, err := n.send(tgbotapi.NewMessage(chatID, HistoryIsOffText))
+		return err
+	}
+	lookups, err := n.repo.ListLookups(ctx, userID, HistoryListSize)
+	if err != nil {
+		return fmt.Errorf("repo.ListLookups: %w", err)
+	}
+	if len(lookups) == 0 {
+		_, err := n.send(tgbotapi.NewMessage(chatID, HistoryEmptyText))
+		return err
+	}
+	msg := tgbotapi.NewMessage(chatID, historyText(lookups))
+	msg.ParseMode = "html"
+	if markup, ok := historyButtons(lookups); ok {
+		msg.ReplyMarkup = markup
+	}
+	_, err = n.send(msg)
+	return err
+}
+
+// historyText lists lookups newest first with the way each translated, or
+// that it was not found.
+func historyText(lookups []models.Lookup) string {
+	var b strings.Builder
+	b.WriteString(HistoryHeaderText)
+	for i, l := range lookups {
+		fmt.Fprintf(&b, "%d. <b>%s</b> · %s\n", i+1, tgbotapi.EscapeText(tgbotapi.ModeHTML, l.Query), lookupLabel(l))
+	}
+	fmt.Fprintf(&b, HistoryFooterFormat, HistoryRetentionDays)
+	return b.String()
+}
+
+func lookupLabel(l models.Lookup) string {
+	switch {
+	case !l.Hit:
+		return HistoryMissLabel
+	case l.Direction == models.LookupChechenRussian:
+		return "че → рус"
+	default:
+		return "рус → че"
+	}
+}
+
+// historyButtons open the card of each lookup that found something, as the
+// query was typed — the same card typing it again gives. Numbered as the
+// list is, two to a row; ok is false when none fits.
+func historyButtons(lookups []models.Lookup) (tgbotapi.InlineKeyboardMarkup, bool) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, l := range lookups {
+		data, ok := cardCallbackData(l.Query)
+		if !l.Hit || !ok {
+			continue
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d. %s", i+1, l.Query), data))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return tgbotapi.InlineKeyboardMarkup{}, false
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...), true
+}
+
+// pruneLookups drops lookups older than HistoryRetentionDays. The activity
+// they detail stays for the analytics; only what was searched goes.
+func (n *Net) pruneLookups(ctx context.Context) {
+	pruned, err := n.repo.PruneLookups(ctx, time.Now().AddDate(0, 0, -HistoryRetentionDays))
+	if err != nil {
+		n.log.WithError(err).Warn("history: prune")
+		return
+	}
+	if pruned > 0 {
+		n.log.Infof("history: pruned %d lookups", pruned)
+	}
+}
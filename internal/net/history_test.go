@@ -0,0 +1,60 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestLookupFor(t *testing.T) {
+	chechen := []models.TranslationPairs{{Original: "гӏала", Translate: "город, крепость", OriginalLang: "CHE", TranslateLang: "RUS"}}
+	russian := []models.TranslationPairs{{Original: "город", Translate: "гӏала", OriginalLang: "RUS", TranslateLang: "CHE"}}
+	tests := []struct {
+		name         string
+		query        string
+		translations []models.TranslationPairs
+		want         models.Lookup
+	}{
+		{"chechen", " Г1ала ", chechen, models.Lookup{Query: "Г1ала", Word: "гӏала", Direction: models.LookupChechenRussian, Hit: true}},
+		{"russian", "город", russian, models.Lookup{Query: "город", Word: "город", Direction: models.LookupRussianChechen, Hit: true}},
+		{"miss", "ахьала", nil, models.Lookup{Query: "ахьала", Word: "ахьала"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lookupFor(tt.query, tt.translations); got != tt.want {
+				t.Errorf("lookupFor = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryTextAndButtons(t *testing.T) {
+	lookups := []models.Lookup{
+		{Query: "дитт", Word: "дитт", Direction: models.LookupChechenRussian, Hit: true},
+		{Query: "ахьала", Word: "ахьала"},
+		{Query: "дом", Word: "дом", Direction: models.LookupRussianChechen, Hit: true},
+		{Query: strings.Repeat("ц", 40), Word: strings.Repeat("ц", 40), Hit: true},
+	}
+	text := historyText(lookups)
+	for _, want := range []string{"1. <b>дитт</b> · че → рус", "2. <b>ахьала</b> · " + HistoryMissLabel, "3. <b>дом</b> · рус → че"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("historyText lacks %q:\n%s", want, text)
+		}
+	}
+
+	markup, ok := historyButtons(lookups)
+	if !ok {
+		t.Fatal("historyButtons: want buttons for the found words")
+	}
+	// The miss and the word too long for a callback get no button.
+	rows := markup.InlineKeyboard
+	if len(rows) != 1 || len(rows[0]) != 2 {
+		t.Fatalf("rows = %+v, want one row of two", rows)
+	}
+	if b := rows[0][1]; b.Text != "3. дом" || *b.CallbackData != "card_дом" {
+		t.Errorf("second button = %q/%s, want the list's number and дом's card", b.Text, *b.CallbackData)
+	}
+	if _, ok := historyButtons(lookups[1:2]); ok {
+		t.Error("misses alone must give no keyboard")
+	}
+}
@@ -0,0 +1,166 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/srs"
+	"chetoru/pkg/tools"
+	"context"
+	"fmt"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// learnDrawAttempts bounds how many pool words /learn skips because the user
+// already has them before giving up on a new card for now.
+const learnDrawAttempts = 5
+
+// HandleLearn serves the next /learn card, and «/learn мои» the next card of
+// the user's saved words (/mywords) only. The deck is personal — a group
+// would grade one member's answers into everyone's schedule — so in a group
+// it only points to the private chat.
+func (n *Net) HandleLearn(ctx context.Context, m *tgbotapi.Message) error {
+	if isGroup(m.Chat) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, LearnPrivateOnlyText))
+		return err
+	}
+	if err := n.repo.StoreUser(ctx, int(m.From.ID), m.From.UserName); err != nil {
+		return fmt.Errorf("repo.StoreUser: %w", err)
+	}
+	filter, ok := parseQuizFilter(m.CommandArguments())
+	return n.sendLearnCard(ctx, m.Chat.ID, m.From.ID, ok && filter.Saved)
+}
+
+// sendLearnCard asks about the user's most overdue word, or failing that
+// introduces a new one while today's allowance of new words lasts. Reviews
+// come first: a new word is cheap to meet and expensive to forget. saved
+// keeps both to the user's saved words, new ones in the order they were
+// saved.
+func (n *Net) sendLearnCard(ctx context.Context, chatID, userID int64, saved bool) error {
+	now := time.Now()
+	nextDue := n.repo.NextDueWordReview
+	if saved {
+		nextDue = n.repo.NextDueSavedWordReview
+	}
+	review, err := nextDue(ctx, userID, now)
+	if err != nil {
+		return fmt.Errorf("repo.NextDueWordReview: %w", err)
+	}
+	if review == nil {
+		if review, err = n.introduceWord(ctx, userID, now, saved); err != nil {
+			n.log.WithError(err).WithField("user_id", userID).Warn("learn: no new word")
+			_, sErr := n.send(tgbotapi.NewMessage(chatID, LearnErrorText))
+			return sErr
+		}
+	}
+	if review == nil {
+		text := LearnDoneText
+		if saved {
+			text = LearnSavedDoneText
+		}
+		_, err := n.send(tgbotapi.NewMessage(chatID, text))
+		return err
+	}
+
+	// Recognition until the word has been recalled twice in a row, then
+	// production, the harder direction.
+	word := models.RandomWord{Chechen: review.Headword, Russian: review.Russian}
+	q, err := n.business.LearnQuiz(ctx, word, review.Repetitions >= 2)
+	if err != nil {
+		n.log.WithError(err).Warn("LearnQuiz failed")
+		_, sErr := n.send(tgbotapi.NewMessage(chatID, LearnErrorText))
+		return sErr
+	}
+	q.ReviewID = review.ID
+	q.Filter.Saved = saved
+	return n.sendQuizButtons(ctx, chatID, "private", q)
+}
+
+// introduceWord adds a pool word the user has not met to their deck, due
+// now, or with saved the earliest saved word not in the deck yet. It returns
+// nil without an error once today's LearnNewPerDay is spent, or when every
+// saved word is in the deck.
+func (n *Net) introduceWord(ctx context.Context, userID int64, now time.Time, saved bool) (*models.WordReview, error) {
+	today := now.Format(time.DateOnly)
+	introduced, err := n.repo.CountNewWordReviews(ctx, userID, today)
+	if err != nil {
+		return nil, fmt.Errorf("repo.CountNewWordReviews: %w", err)
+	}
+	if introduced >= LearnNewPerDay {
+		return nil, nil
+	}
+	if saved {
+		w, err := n.repo.NextUnlearnedSavedWord(ctx, userID)
+		if err != nil || w == nil {
+			return nil, err
+		}
+		review := models.WordReview{
+			UserID:        userID,
+			Headword:      w.Headword,
+			HeadwordClean: w.HeadwordClean,
+			Russian:       w.Russian,
+			Ease:          srs.DefaultEase,
+			DueAt:         now,
+		}
+		if review.ID, _, err = n.repo.AddWordReview(ctx, review, today); err != nil {
+			return nil, fmt.Errorf("repo.AddWordReview: %w", err)
+		}
+		return &review, nil
+	}
+
+	for range learnDrawAttempts {
+		word, err := n.business.RandomWordFromAPI(ctx)
+		if err != nil {
+			return nil, err
+		}
+		review := models.WordReview{
+			UserID:        userID,
+			Headword:      word.Chechen,
+			HeadwordClean: tools.NormalizeSearch(word.Chechen), // pool words carry no stress marks
+			Russian:       word.Russian,
+			Ease:          srs.DefaultEase,
+			DueAt:         now,
+		}
+		id, added, err := n.repo.AddWordReview(ctx, review, today)
+		if err != nil {
+			return nil, fmt.Errorf("repo.AddWordReview: %w", err)
+		}
+		if added {
+			review.ID = id
+			return &review, nil
+		}
+	}
+	return nil, fmt.Errorf("no unseen word in %d draws", learnDrawAttempts)
+}
+
+// gradeReview schedules the card answered in a /learn question and returns
+// the toast suffix saying when it comes back. A card no longer due — the
+// answer was already graded by an earlier tap — is left as it is.
+func (n *Net) gradeReview(ctx context.Context, userID, reviewID int64, correct bool) (string, error) {
+	review, err := n.repo.GetWordReview(ctx, userID, reviewID)
+	if err != nil {
+		return "", fmt.Errorf("repo.GetWordReview: %w", err)
+	}
+	now := time.Now()
+	if review == nil || review.DueAt.After(now) {
+		return "", nil
+	}
+
+	card := srs.Card{
+		Ease:         review.Ease,
+		IntervalDays: review.IntervalDays,
+		Repetitions:  review.Repetitions,
+		Lapses:       review.Lapses,
+		Due:          review.DueAt,
+	}.Review(correct, now)
+	review.Ease, review.IntervalDays, review.Repetitions, review.Lapses, review.DueAt =
+		card.Ease, card.IntervalDays, card.Repetitions, card.Lapses, card.Due
+	updated, err := n.repo.UpdateWordReview(ctx, *review, now)
+	if err != nil || !updated {
+		return "", err
+	}
+	if !correct {
+		return LearnRelearnToast, nil
+	}
+	return fmt.Sprintf(LearnNextReviewFormat, card.IntervalDays), nil
+}
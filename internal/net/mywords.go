@@ -0,0 +1,258 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// saveCallbackData builds the payload of «⭐ Сохранить» under word's card,
+// stress marks dropped. Like cardCallbackData it is ok=false when the word
+// does not fit in 64 bytes, and the caller leaves the button out.
+func saveCallbackData(word string) (string, bool) {
+	data := "save_" + strings.ReplaceAll(strings.TrimSpace(word), "\u0301", "")
+	return data, len(data) <= 64
+}
+
+// saveButtonRow is the «⭐ Сохранить» row for chechen's card, when it fits.
+func saveButtonRow(chechen string) ([]tgbotapi.InlineKeyboardButton, bool) {
+	data, ok := saveCallbackData(chechen)
+	if !ok {
+		return nil, false
+	}
+	return tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(SaveWordButtonText, data)), true
+}
+
+// translationSaveWord is the Chechen word a translation card's «⭐
+// Сохранить» saves: the card's headword when it is Chechen, or for a Russian
+// one its first sense when that is a single word. ok is false when the card
+// offers nothing to save.
+func translationSaveWord(query string, translations []models.TranslationPairs) (string, bool) {
+	if respelled := translations[0].Respelled; respelled != "" {
+		query = respelled
+	}
+	if headword := translations[0].FormOf; headword != "" {
+		query = headword
+	}
+	if head, ok := tools.ChechenHead(query, translations); ok {
+		return head, true
+	}
+	if _, sense, ok := tools.Gloss(query, translations); ok && !strings.ContainsAny(sense, " ,;") {
+		return sense, true
+	}
+	return "", false
+}
+
+// HandleSaveWordCallback saves the word a card's «⭐ Сохранить» names to the
+// tapper's list. The button carries the word only; its meaning is read from
+// the word's own card, so every saved word is a Chechen headword with the
+// sense its card opens with, whichever card it was saved from.
+func (n *Net) HandleSaveWordCallback(ctx context.Context, cq *tgbotapi.CallbackQuery) error {
+	word, found := strings.CutPrefix(cq.Data, "save_")
+	if !found || word == "" {
+		return fmt.Errorf("invalid save callback data: %q", cq.Data)
+	}
+	w, err := n.business.CardWord(word)
+	if err != nil || w == nil {
+		if err != nil {
+			n.log.WithError(err).WithField("word", word).Warn("save word lookup failed")
+		}
+		_, rErr := n.bot.Request(tgbotapi.NewCallback(cq.ID, SaveWordFailedToast))
+		return rErr
+	}
+	added, err := n.repo.SaveWord(ctx, models.SavedWord{
+		UserID:        cq.From.ID,
+		Headword:      w.Chechen,
+		HeadwordClean: tools.NormalizeSearch(w.Chechen),
+		Russian:       w.Russian,
+	})
+	if err != nil {
+		return fmt.Errorf("repo.SaveWord: %w", err)
+	}
+	toast := fmt.Sprintf(SaveWordDoneFormat, w.Chechen)
+	if !added {
+		toast = fmt.Sprintf(SaveWordAlreadyFormat, w.Chechen)
+	}
+	_, err = n.bot.Request(tgbotapi.NewCallback(cq.ID, toast))
+	return err
+}
+
+// HandleMyWords serves /mywords: the user's saved words, a page at a time,
+// with a button to delete each and the ways to practise them. The list is
+// personal, so in a group it only points to the private chat.
+func (n *Net) HandleMyWords(ctx context.Context, m *tgbotapi.Message) error {
+	if isGroup(m.Chat) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, MyWordsPrivateOnlyText))
+		return err
+	}
+	text, markup, err := n.myWordsPage(ctx, m.From.ID, 0)
+	if err != nil {
+		return err
+	}
+	msg := tgbotapi.NewMessage(m.Chat.ID, text)
+	msg.ParseMode = "html"
+	if markup != nil {
+		msg.ReplyMarkup = *markup
+	}
+	_, err = n.send(msg)
+	return err
+}
+
+// myWordsPage renders page (from 0) of the user's saved words. A page past
+// the end — its last word just deleted — shows the last page there is.
+func (n *Net) myWordsPage(ctx context.Context, userID int64, page int) (string, *tgbotapi.InlineKeyboardMarkup, error) {
+	words, total, err := n.repo.ListSavedWords(ctx, userID, page*MyWordsPageSize, MyWordsPageSize)
+	if err != nil {
+		return "", nil, fmt.Errorf("repo.ListSavedWords: %w", err)
+	}
+	if total == 0 {
+		return MyWordsEmptyText, nil, nil
+	}
+	pages := (total + MyWordsPageSize - 1) / MyWordsPageSize
+	if page >= pages {
+		return n.myWordsPage(ctx, userID, pages-1)
+	}
+	markup := myWordsButtons(words, page, pages)
+	return myWordsText(words, page, total), &markup, nil
+}
+
+// myWordsText lists a page of saved words, numbered across pages.
+func myWordsText(words []models.SavedWord, page, total int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, MyWordsHeaderFormat, total)
+	for i, w := range words {
+		fmt.Fprintf(&b, "%d. <b>%s</b> — %s\n", page*MyWordsPageSize+i+1,
+			tgbotapi.EscapeText(tgbotapi.ModeHTML, w.Headword), tgbotapi.EscapeText(tgbotapi.ModeHTML, w.Russian))
+	}
+	return b.String()
+}
+
+// myWordsButtons are a page's delete buttons, two to a row and numbered as
+// the list is, the pager when there is more than one page, and practice.
+// Callback data: "mywords_p_<page>" (turn to page),
+// "mywords_d_<id>_<page>" (delete id, then show page) and "mywords_n" (the
+// page counter, a noop).
+func myWordsButtons(words []models.SavedWord, page, pages int) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, w := range words {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("🗑 %d. %s", page*MyWordsPageSize+i+1, w.Headword),
+			fmt.Sprintf("mywords_d_%d_%d", w.ID, page),
+		))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	if pages > 1 {
+		var pager []tgbotapi.InlineKeyboardButton
+		if page > 0 {
+			pager = append(pager, tgbotapi.NewInlineKeyboardButtonData("◀️", fmt.Sprintf("mywords_p_%d", page-1)))
+		}
+		pager = append(pager, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d/%d", page+1, pages), "mywords_n"))
+		if page < pages-1 {
+			pager = append(pager, tgbotapi.NewInlineKeyboardButtonData("▶️", fmt.Sprintf("mywords_p_%d", page+1)))
+		}
+		rows = append(rows, pager)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(MyWordsQuizButtonText, "quiz_ns"),
+		tgbotapi.NewInlineKeyboardButtonData(MyWordsLearnButtonText, "quiz_ls"),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// parseMyWordsData reads a /mywords callback back: the page to show and,
+// for a delete, the saved word's ID.
+func parseMyWordsData(data string) (id int64, page int, ok bool) {
+	parts := strings.Split(data, "_") // [mywords p page] or [mywords d id page]
+	var err error
+	switch {
+	case len(parts) == 3 && parts[1] == "p":
+		page, err = strconv.Atoi(parts[2])
+	case len(parts) == 4 && parts[1] == "d":
+		if id, err = strconv.ParseInt(parts[2], 10, 64); err == nil {
+			page, err = strconv.Atoi(parts[3])
+		}
+	default:
+		return 0, 0, false
+	}
+	if err != nil || page < 0 || id < 0 {
+		return 0, 0, false
+	}
+	return id, page, true
+}
+
+// HandleMyWordsCallback turns a /mywords page or deletes a word from it,
+// then redraws the list in place.
+func (n *Net) HandleMyWordsCallback(ctx context.Context, cq *tgbotapi.CallbackQuery) error {
+	if cq.Data == "mywords_n" {
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, ""))
+		return err
+	}
+	id, page, ok := parseMyWordsData(cq.Data)
+	if !ok {
+		return fmt.Errorf("invalid mywords callback %q", cq.Data)
+	}
+	toast := ""
+	if id > 0 {
+		deleted, err := n.repo.DeleteSavedWord(ctx, cq.From.ID, id)
+		if err != nil {
+			return fmt.Errorf("repo.DeleteSavedWord: %w", err)
+		}
+		if deleted {
+			toast = MyWordsDeletedToast
+		}
+	}
+	if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, toast)); err != nil {
+		n.log.WithError(err).Warn("failed to ack mywords callback")
+	}
+
+	text, markup, err := n.myWordsPage(ctx, cq.From.ID, page)
+	if err != nil {
+		return err
+	}
+	edit := tgbotapi.NewEditMessageText(cq.Message.Chat.ID, cq.Message.MessageID, text)
+	edit.ParseMode = "html"
+	edit.ReplyMarkup = markup
+	if _, err := n.send(edit); err != nil {
+		return fmt.Errorf("bot.Send edit: %w", err)
+	}
+	return nil
+}
+
+// sendSavedQuiz asks a /quiz question about one of the user's saved words,
+// built as a /learn question is but graded like a /quiz one, minus the
+// score. Only a private chat knows whose words to ask about.
+func (n *Net) sendSavedQuiz(ctx context.Context, chat *tgbotapi.Chat) error {
+	if isGroup(chat) {
+		_, err := n.send(tgbotapi.NewMessage(chat.ID, MyWordsPrivateOnlyText))
+		return err
+	}
+	w, err := n.repo.RandomSavedWord(ctx, chat.ID)
+	if err != nil {
+		return fmt.Errorf("repo.RandomSavedWord: %w", err)
+	}
+	if w == nil {
+		_, err := n.send(tgbotapi.NewMessage(chat.ID, MyWordsEmptyText))
+		return err
+	}
+	q, err := n.business.LearnQuiz(ctx, models.RandomWord{Chechen: w.Headword, Russian: w.Russian}, rand.IntN(2) == 0)
+	if err != nil {
+		n.log.WithError(err).Warn("LearnQuiz failed")
+		_, sErr := n.send(tgbotapi.NewMessage(chat.ID, QuizErrorText))
+		return sErr
+	}
+	q.Filter = models.QuizFilter{Saved: true}
+	return n.sendQuizButtons(ctx, chat.ID, chat.Type, q)
+}
@@ -0,0 +1,58 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestTranslationSaveWord(t *testing.T) {
+	chechen := []models.TranslationPairs{{Original: "Гӏала", Translate: "город", OriginalLang: "CHE", TranslateLang: "RUS"}}
+	if got, ok := translationSaveWord("г1ала", chechen); !ok || got != "Гӏала" {
+		t.Errorf("Chechen card saves %q, %v; want its headword", got, ok)
+	}
+	russian := []models.TranslationPairs{{Original: "город", Translate: "гӏала", OriginalLang: "RUS", TranslateLang: "CHE"}}
+	if got, ok := translationSaveWord("город", russian); !ok || got != "гӏала" {
+		t.Errorf("Russian card saves %q, %v; want its one-word sense", got, ok)
+	}
+}
+
+func TestSaveCallbackData(t *testing.T) {
+	if data, ok := saveCallbackData("Гӏа́ла"); !ok || data != "save_Гӏала" {
+		t.Errorf("saveCallbackData = %q, %v; want the word without its stress mark", data, ok)
+	}
+	if _, ok := saveCallbackData(strings.Repeat("ц", 40)); ok {
+		t.Error("a word past 64 bytes must get no button")
+	}
+}
+
+func TestMyWordsButtons(t *testing.T) {
+	words := []models.SavedWord{{ID: 31, Headword: "Дитт"}, {ID: 30, Headword: "Цӏа"}, {ID: 29, Headword: "Хи"}}
+	rows := myWordsButtons(words, 1, 3).InlineKeyboard
+	// Two delete rows, the pager, and practice.
+	if len(rows) != 4 || len(rows[0]) != 2 || len(rows[1]) != 1 || len(rows[2]) != 3 {
+		t.Fatalf("rows = %+v, want 2+1 delete buttons, a full pager and practice", rows)
+	}
+	if b := rows[0][0]; b.Text != "🗑 11. Дитт" || *b.CallbackData != "mywords_d_31_1" {
+		t.Errorf("first delete button = %q/%s, want the list's number and a delete of 31", b.Text, *b.CallbackData)
+	}
+	if id, page, ok := parseMyWordsData(*rows[0][0].CallbackData); !ok || id != 31 || page != 1 {
+		t.Errorf("parseMyWordsData(delete) = %d, %d, %v", id, page, ok)
+	}
+	if data := *rows[2][1].CallbackData; data != "mywords_n" {
+		t.Errorf("page counter data = %q, want the /mywords noop", data)
+	}
+	if id, page, ok := parseMyWordsData(*rows[2][2].CallbackData); !ok || id != 0 || page != 2 {
+		t.Errorf("parseMyWordsData(next page) = %d, %d, %v", id, page, ok)
+	}
+
+	// A single page has no pager.
+	if rows := myWordsButtons(words[:1], 0, 1).InlineKeyboard; len(rows) != 2 {
+		t.Errorf("single page rows = %+v, want a delete row and practice", rows)
+	}
+	for _, bad := range []string{"mywords_x", "mywords_p_-1", "mywords_d_x_0"} {
+		if _, _, ok := parseMyWordsData(bad); ok {
+			t.Errorf("parseMyWordsData(%q) ok, want rejected", bad)
+		}
+	}
+}
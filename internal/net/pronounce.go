@@ -0,0 +1,46 @@
+package net
+
+import (
+	"chetoru/pkg/tools"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// SetPronunciation turns the pronunciation line on word cards on or off. Off
+// by default: the line is for learners who cannot yet read the palochka
+// digraphs, and to a fluent reader it is one more line to scroll past.
+func (n *Net) SetPronunciation(on bool) {
+	n.pronunciation = on
+}
+
+// pronunciationLine transcribes a Chechen word for the card that shows it, or
+// returns "" when pronunciation is off or there is nothing to transcribe.
+// word should be the dictionary's spelling, stress marks kept: they are what
+// puts the accent in the transcription.
+func (n *Net) pronunciationLine(word string) string {
+	if !n.pronunciation {
+		return ""
+	}
+	latin, ipa := tools.Romanize(word), tools.IPA(word)
+	if latin == "" || ipa == "" {
+		return ""
+	}
+	return fmt.Sprintf(PronunciationFormat,
+		tgbotapi.EscapeText(tgbotapi.ModeHTML, latin),
+		tgbotapi.EscapeText(tgbotapi.ModeHTML, ipa))
+}
+
+// withPronunciation puts line right under the first line of card, where the
+// headword is; a blank line belongs to the card, not to the transcription.
+func withPronunciation(card, line string) string {
+	if line == "" {
+		return card
+	}
+	head, rest, found := strings.Cut(card, "\n")
+	if !found {
+		return card + "\n" + line
+	}
+	return head + "\n" + line + "\n" + rest
+}
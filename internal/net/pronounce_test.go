@@ -0,0 +1,38 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestTranslationCardPronunciation(t *testing.T) {
+	pairs := []models.TranslationPairs{{
+		Original: "Къо́лам", OriginalLang: "CHE",
+		Translate: "карандаш", TranslateLang: "RUS",
+		Rate: 10000, EntryType: "WORD",
+	}}
+	query := "Къо́лам"
+
+	off := (&Net{}).translationCard(query, pairs)
+	if strings.Contains(off, "🗣") {
+		t.Fatalf("pronunciation is off, card has the line:\n%s", off)
+	}
+
+	n := &Net{}
+	n.SetPronunciation(true)
+	card := n.translationCard(query, pairs)
+	lines := strings.Split(card, "\n")
+	if len(lines) < 2 || lines[1] != "🗣 Q'ólam · [ˈqʼolam]" {
+		t.Fatalf("want the transcription right under the headword, got:\n%s", card)
+	}
+
+	// A Russian headword is not transcribed.
+	russian := []models.TranslationPairs{{
+		Original: "Карандаш", OriginalLang: "RUS",
+		Translate: "къолам", TranslateLang: "CHE",
+	}}
+	if card := n.translationCard("карандаш", russian); strings.Contains(card, "🗣") {
+		t.Fatalf("Russian-headed card got a pronunciation line:\n%s", card)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -13,12 +14,66 @@ import (
 // quizLetters label the answer options. Indices map 1:1 to option/row order.
 var quizLetters = []string{"А", "Б", "В", "Г", "Д", "Е"}
 
-// HandleQuiz sends a fresh question. In private chats it uses inline buttons
-// with per-user persistent scoring; in groups it uses a native Telegram quiz
-// poll so everyone can answer independently (inline buttons would let the first
-// tapper lock the question for the whole group).
-func (n *Net) HandleQuiz(ctx context.Context, chat *tgbotapi.Chat) error {
-	q, err := n.business.GenerateQuiz(ctx)
+// quizFilterWords maps /quiz arguments to the category they ask for. Parts of
+// speech go by the abbreviations word cards label them with, dot optional;
+// «формы» asks about word forms and set phrases instead of translations, and
+// «мои» about the user's saved words.
+var quizFilterWords = map[string]models.QuizFilter{
+	"гл":       {Subtype: 1},
+	"глаголы":  {Subtype: 1},
+	"сущ":      {Subtype: 2},
+	"нареч":    {Subtype: 3},
+	"прил":     {Subtype: 4},
+	"мест":     {Subtype: 6},
+	"academic": {Rate: models.RateAcademic},
+	"академ":   {Rate: models.RateAcademic},
+	"акад":     {Rate: models.RateAcademic},
+	"формы":    {Grammar: true},
+	"грамм":    {Grammar: true},
+	"grammar":  {Grammar: true},
+	"мои":      {Saved: true},
+	"mine":     {Saved: true},
+}
+
+// parseQuizFilter reads /quiz arguments such as «гл.» or «сущ. academic»
+// into a filter. ok is false when a word names no category.
+func parseQuizFilter(args string) (f models.QuizFilter, ok bool) {
+	for _, word := range strings.Fields(strings.ToLower(args)) {
+		part, known := quizFilterWords[strings.TrimSuffix(word, ".")]
+		if !known {
+			return models.QuizFilter{}, false
+		}
+		if part.Subtype != 0 {
+			f.Subtype = part.Subtype
+		}
+		if part.Rate != 0 {
+			f.Rate = part.Rate
+		}
+		f.Grammar = f.Grammar || part.Grammar
+		f.Saved = f.Saved || part.Saved
+	}
+	return f, true
+}
+
+// HandleQuizCommand serves /quiz with its optional category arguments.
+func (n *Net) HandleQuizCommand(ctx context.Context, m *tgbotapi.Message) error {
+	filter, ok := parseQuizFilter(m.CommandArguments())
+	if !ok {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, QuizFilterUsageText))
+		return err
+	}
+	return n.HandleQuiz(ctx, m.Chat, filter)
+}
+
+// HandleQuiz sends a fresh question from filter's category. In private chats
+// it uses inline buttons with per-user persistent scoring; in groups it uses a
+// native Telegram quiz poll so everyone can answer independently (inline
+// buttons would let the first tapper lock the question for the whole group).
+func (n *Net) HandleQuiz(ctx context.Context, chat *tgbotapi.Chat, filter models.QuizFilter) error {
+	if filter.Saved {
+		return n.sendSavedQuiz(ctx, chat)
+	}
+	q, err := n.business.GenerateQuiz(ctx, filter)
 	if err != nil {
 		n.log.WithError(err).Warn("GenerateQuiz failed")
 		_, sErr := n.send(tgbotapi.NewMessage(chat.ID, QuizErrorText))
@@ -26,59 +81,179 @@ func (n *Net) HandleQuiz(ctx context.Context, chat *tgbotapi.Chat) error {
 	}
 
 	if chat.Type == "group" || chat.Type == "supergroup" {
-		return n.sendQuizPoll(ctx, chat.ID, q)
+		return n.sendQuizPoll(ctx, chat, q)
 	}
-	return n.sendQuizButtons(chat.ID, q)
+	return n.sendQuizButtons(ctx, chat.ID, chat.Type, q)
 }
 
 // sendQuizPoll posts a native quiz poll — the idiomatic group experience. Each
 // member answers on their own and Telegram reveals the correct option to them.
-// The poll's correct option is cached by poll ID so answers can be graded into
-// the leaderboard when poll_answer updates arrive.
-func (n *Net) sendQuizPoll(ctx context.Context, chatID int64, q *models.QuizQuestion) error {
-	question := fmt.Sprintf("🧠 Как переводится на русский: %s?", q.Prompt)
-	if q.Reversed {
-		question = fmt.Sprintf("🧠 Как сказать по-чеченски: %s?", q.Prompt)
-	}
-	poll := tgbotapi.NewPoll(chatID, question, q.Options...)
-	poll.Type = "quiz"
-	poll.CorrectOptionID = int64(q.CorrectIdx)
-	poll.IsAnonymous = false
+func (n *Net) sendQuizPoll(ctx context.Context, chat *tgbotapi.Chat, q *models.QuizQuestion) error {
+	poll := tgbotapi.NewPoll(chat.ID, "🧠 "+quizPollQuestion(q), q.Options...)
 	// Let the group chain questions without retyping /quiz.
 	poll.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(QuizNextButtonText, "quiz_n"),
+			tgbotapi.NewInlineKeyboardButtonData(QuizNextButtonText, quizNextData(q.Filter)),
 		),
 	)
+	_, _, err := n.postQuizPoll(ctx, chat, q, poll)
+	return err
+}
+
+// quizPollQuestion is a poll's question text for q.
+func quizPollQuestion(q *models.QuizQuestion) string {
+	switch {
+	case q.Kind == models.QuizKindForm:
+		return fmt.Sprintf("Какое из слов — форма слова «%s»?", q.Prompt)
+	case q.Kind == models.QuizKindIdiom:
+		return fmt.Sprintf("Что значит выражение «%s»?", q.Prompt)
+	case q.Kind == models.QuizKindPhrase:
+		return fmt.Sprintf("Какое слово пропущено: «%s» (%s)?", q.Prompt, q.Hint)
+	case q.Reversed:
+		return fmt.Sprintf("Как сказать по-чеченски: %s?", q.Prompt)
+	default:
+		return fmt.Sprintf("Как переводится на русский: %s?", q.Prompt)
+	}
+}
+
+// postQuizPoll sends poll as the quiz poll for q and returns the stored
+// question's ID with the sent message. The question is stored like a button
+// one and tied to the poll's ID, which is all a poll_answer update carries, so
+// answers can be graded into the leaderboards and the answer log when they
+// arrive.
+func (n *Net) postQuizPoll(ctx context.Context, chat *tgbotapi.Chat, q *models.QuizQuestion, poll tgbotapi.SendPollConfig) (string, tgbotapi.Message, error) {
+	poll.Type = "quiz"
+	poll.CorrectOptionID = int64(q.CorrectIdx)
+	poll.IsAnonymous = false
+	// Saved before sending so an answer can never beat the row; a poll that
+	// then fails to send leaves a row nothing points at, pruned with the rest.
+	id, err := n.repo.SaveQuizQuestion(ctx, *q, chat.ID, chat.Type, QuizQuestionTTL)
+	if err != nil {
+		return "", tgbotapi.Message{}, fmt.Errorf("repo.SaveQuizQuestion: %w", err)
+	}
 	sent, err := n.send(poll)
 	if err != nil {
-		return err
+		return "", sent, err
 	}
 	if sent.Poll != nil {
-		if err := n.cache.SetQuizPoll(ctx, sent.Poll.ID, q.CorrectIdx); err != nil {
-			n.log.WithError(err).Warn("failed to cache quiz poll mapping")
+		if err := n.repo.AttachQuizPoll(ctx, id, sent.Poll.ID); err != nil {
+			n.log.WithError(err).Warn("failed to store quiz poll mapping")
 		}
 	}
-	return nil
+	return id, sent, nil
 }
 
-// HandlePollAnswer grades a group quiz-poll answer into the leaderboard. Votes on
-// unknown/expired polls (or retracted votes) are ignored.
+// HandlePollAnswer grades a group quiz-poll answer into the leaderboards —
+// the global one, the group's own and a running tournament's — and the answer
+// log. Votes on unknown/expired polls, retracted votes and votes
+// cast again after a retraction are ignored: only a user's first answer counts.
 func (n *Net) HandlePollAnswer(ctx context.Context, pa *tgbotapi.PollAnswer) error {
 	if pa == nil || len(pa.OptionIDs) == 0 {
 		return nil
 	}
-	correctOption, err := n.cache.GetQuizPoll(ctx, pa.PollID)
+	q, err := n.repo.GetQuizQuestionByPoll(ctx, pa.PollID)
 	if err != nil {
+		return fmt.Errorf("repo.GetQuizQuestionByPoll: %w", err)
+	}
+	if q == nil {
 		return nil // not one of our quiz polls, or it expired
 	}
-	correct := pa.OptionIDs[0] == correctOption
+	chosen := pa.OptionIDs[0]
+	if chosen < 0 || chosen >= len(q.Options) {
+		return nil
+	}
+	first, err := n.repo.ClaimQuizAnswer(ctx, q.ID, pa.User.ID)
+	if err != nil {
+		return fmt.Errorf("repo.ClaimQuizAnswer (poll): %w", err)
+	}
+	if !first {
+		return nil
+	}
+	correct := chosen == q.CorrectIdx
 	if err := n.repo.RecordQuizAnswer(ctx, pa.User.ID, pa.User.UserName, pa.User.FirstName, correct); err != nil {
 		return fmt.Errorf("RecordQuizAnswer (poll): %w", err)
 	}
+	if q.ChatID != 0 {
+		if err := n.repo.RecordChatQuizAnswer(ctx, q.ChatID, pa.User.ID, pa.User.UserName, pa.User.FirstName, correct); err != nil {
+			n.log.WithError(err).WithField("chat_id", q.ChatID).Warn("RecordChatQuizAnswer failed")
+		}
+		n.creditTournament(q, &pa.User, correct)
+	}
+	n.logQuizAnswer(ctx, pa.User.ID, q, chosen)
 	return nil
 }
 
+// HandleTopCommand serves /top and its boards: «/top week» and «/top month»
+// rank the current season, «/top winners» lists past seasons' champions. A
+// plain /top is the lifetime board, except in a group, which sees its own
+// board and needs «/top all» for the global one.
+func (n *Net) HandleTopCommand(ctx context.Context, m *tgbotapi.Message) error {
+	arg := strings.ToLower(strings.TrimSpace(m.CommandArguments()))
+	switch board := quizTopBoards[arg]; {
+	case board == models.SeasonWeek || board == models.SeasonMonth:
+		return n.HandleSeasonTop(ctx, m.Chat.ID, m.From.ID, board)
+	case board == quizTopWinners:
+		return n.HandleSeasonWinners(ctx, m.Chat.ID)
+	case isGroup(m.Chat) && board != quizTopAllTime:
+		return n.HandleChatTop(ctx, m.Chat.ID, m.From.ID)
+	default:
+		return n.HandleTop(ctx, m.Chat.ID, m.From.ID)
+	}
+}
+
+const (
+	quizTopAllTime = "all"
+	quizTopWinners = "winners"
+)
+
+// quizTopBoards maps /top arguments to the board they ask for.
+var quizTopBoards = map[string]string{
+	"all":      quizTopAllTime,
+	"все":      quizTopAllTime,
+	"общий":    quizTopAllTime,
+	"week":     models.SeasonWeek,
+	"неделя":   models.SeasonWeek,
+	"month":    models.SeasonMonth,
+	"месяц":    models.SeasonMonth,
+	"winners":  quizTopWinners,
+	"чемпионы": quizTopWinners,
+	"архив":    quizTopWinners,
+}
+
+// HandleChatTop renders a group's own quiz board, built from the polls posted
+// in it. Like the global board, a requester below the visible top gets their
+// own position appended.
+func (n *Net) HandleChatTop(ctx context.Context, chatID, userID int64) error {
+	scorers, err := n.repo.TopChatQuizScorers(ctx, chatID, QuizTopLimit)
+	if err != nil {
+		return fmt.Errorf("repo.TopChatQuizScorers: %w", err)
+	}
+	if len(scorers) == 0 {
+		_, err = n.send(tgbotapi.NewMessage(chatID, QuizChatTopEmptyText))
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString(QuizChatTopHeader)
+	shown := false
+	for i, s := range scorers {
+		shown = shown || s.UserID == userID
+		fmt.Fprintf(&b, "%s <b>%s</b> — %d/%d\n", quizMedal(i), tgbotapi.EscapeText(tgbotapi.ModeHTML, scorerName(s)), s.Correct, s.Total)
+	}
+	if !shown {
+		if rank, correct, total, err := n.repo.GetChatQuizRank(ctx, chatID, userID); err != nil {
+			n.log.WithError(err).WithField("user_id", userID).Warn("GetChatQuizRank failed")
+		} else if rank > 0 {
+			fmt.Fprintf(&b, "\n👤 Вы: <b>№%d</b> — %d/%d\n", rank, correct, total)
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	msg.ParseMode = "html"
+	_, err = n.send(msg)
+	return err
+}
+
 // HandleTop renders the quiz leaderboard — friendly competition to encourage
 // sustained vocabulary practice. A requester ranked below the visible top gets
 // their own position appended so progress always feels within reach.
@@ -94,24 +269,7 @@ func (n *Net) HandleTop(ctx context.Context, chatID, userID int64) error {
 
 	var b strings.Builder
 	b.WriteString(QuizTopHeader)
-	shown := false
-	for i, s := range scorers {
-		if s.UserID == userID {
-			shown = true
-		}
-		name := scorerName(s)
-		pct := 0
-		if s.Total > 0 {
-			pct = s.Correct * 100 / s.Total
-		}
-		fmt.Fprintf(&b, "%s <b>%s</b> — %d/%d (%d%%)", quizMedal(i), tgbotapi.EscapeText(tgbotapi.ModeHTML, name), s.Correct, s.Total, pct)
-		if s.Streak >= 2 {
-			fmt.Fprintf(&b, " 🔥%d", s.Streak)
-		}
-		b.WriteByte('\n')
-	}
-
-	if !shown {
+	if !writeQuizBoard(&b, scorers, userID) {
 		if rank, err := n.repo.GetQuizRank(ctx, userID); err != nil {
 			n.log.WithError(err).WithField("user_id", userID).Warn("GetQuizRank failed")
 		} else if rank > 0 {
@@ -120,6 +278,7 @@ func (n *Net) HandleTop(ctx context.Context, chatID, userID int64) error {
 			}
 		}
 	}
+	b.WriteString(QuizTopBoardsHint)
 
 	msg := tgbotapi.NewMessage(chatID, b.String())
 	msg.ParseMode = "html"
@@ -127,6 +286,27 @@ func (n *Net) HandleTop(ctx context.Context, chatID, userID int64) error {
 	return err
 }
 
+// writeQuizBoard writes a leaderboard's rows, medals first, and reports
+// whether userID is among them.
+func writeQuizBoard(b *strings.Builder, scorers []models.QuizScorer, userID int64) (shown bool) {
+	for i, s := range scorers {
+		if s.UserID == userID {
+			shown = true
+		}
+		name := scorerName(s)
+		pct := 0
+		if s.Total > 0 {
+			pct = s.Correct * 100 / s.Total
+		}
+		fmt.Fprintf(b, "%s <b>%s</b> — %d/%d (%d%%)", quizMedal(i), tgbotapi.EscapeText(tgbotapi.ModeHTML, name), s.Correct, s.Total, pct)
+		if s.Streak >= 2 {
+			fmt.Fprintf(b, " 🔥%d", s.Streak)
+		}
+		b.WriteByte('\n')
+	}
+	return shown
+}
+
 // scorerName picks the leaderboard display name: @username, then first name,
 // then an anonymous placeholder.
 func scorerName(s models.QuizScorer) string {
@@ -163,36 +343,60 @@ func quizMedal(rank int) string {
 }
 
 // sendQuizButtons posts the inline-button quiz used in private chats. The
-// correct answer index is encoded in each button's callback data, so grading
-// needs no server-side state.
-func (n *Net) sendQuizButtons(chatID int64, q *models.QuizQuestion) error {
+// question is stored server-side under an opaque ID and each button carries
+// only that ID and its own index: callback data is whatever the client sends,
+// so a correct index in it would let a modified client answer right every
+// time.
+func (n *Net) sendQuizButtons(ctx context.Context, chatID int64, chatType string, q *models.QuizQuestion) error {
+	id, err := n.repo.SaveQuizQuestion(ctx, *q, chatID, chatType, QuizQuestionTTL)
+	if err != nil {
+		return fmt.Errorf("repo.SaveQuizQuestion: %w", err)
+	}
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(q.Options))
 	for i, opt := range q.Options {
 		letter := ""
 		if i < len(quizLetters) {
 			letter = quizLetters[i] + ". "
 		}
+		data := fmt.Sprintf("quiz_a_%s_%d", id, i)
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(
-				letter+opt,
-				fmt.Sprintf("quiz_a_%d_%d", i, q.CorrectIdx),
-			),
+			tgbotapi.NewInlineKeyboardButtonData(letter+opt, data),
 		))
 	}
 
-	format := QuizQuestionFormat
-	if q.Reversed {
-		format = QuizQuestionReverseFormat
+	prompt := tgbotapi.EscapeText(tgbotapi.ModeHTML, q.Prompt)
+	var text string
+	switch {
+	case q.Kind == models.QuizKindForm:
+		text = fmt.Sprintf(QuizFormQuestionFormat, prompt)
+	case q.Kind == models.QuizKindIdiom:
+		text = fmt.Sprintf(QuizIdiomQuestionFormat, prompt)
+	case q.Kind == models.QuizKindPhrase:
+		text = fmt.Sprintf(QuizPhraseQuestionFormat, tgbotapi.EscapeText(tgbotapi.ModeHTML, q.Hint), prompt)
+	case q.ReviewID > 0 && q.Reversed:
+		text = fmt.Sprintf(LearnQuestionReverseFormat, prompt)
+	case q.ReviewID > 0:
+		text = fmt.Sprintf(LearnQuestionFormat, prompt)
+	case q.Reversed:
+		text = fmt.Sprintf(QuizQuestionReverseFormat, prompt)
+	default:
+		text = fmt.Sprintf(QuizQuestionFormat, prompt)
 	}
-	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(format, tgbotapi.EscapeText(tgbotapi.ModeHTML, q.Prompt)))
+	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "html"
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
-	_, err := n.send(msg)
+	_, err = n.send(msg)
 	return err
 }
 
 // HandleQuizCallback grades an answer (or serves the next question). Callback
-// data formats: "quiz_a_<chosen>_<correct>", "quiz_n" (next), "quiz_done" (noop).
+// data formats: "quiz_a_<question>_<chosen>", "quiz_n" (next),
+// "quiz_n_<subtype>_<rate>[_g]" (next from a filtered /quiz), "quiz_ns" (next
+// from the saved words), "quiz_l" (next /learn card), "quiz_ls" (next /learn
+// card of the saved words), "quiz_w" (next /write question), "quiz_wg" (give
+// up on one), "quiz_done" (noop). Everything grading needs — the correct
+// option, the /learn card — is read from the stored question, and only the
+// user's first answer to it counts.
 func (n *Net) HandleQuizCallback(ctx context.Context, cq *tgbotapi.CallbackQuery) error {
 	data := cq.Data
 	chatID := cq.Message.Chat.ID
@@ -201,45 +405,94 @@ func (n *Net) HandleQuizCallback(ctx context.Context, cq *tgbotapi.CallbackQuery
 	case "quiz_done":
 		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, ""))
 		return err
-	case "quiz_n":
+	case "quiz_n", "quiz_ns":
+		if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, "")); err != nil {
+			n.log.WithError(err).Warn("failed to ack quiz next callback")
+		}
+		return n.HandleQuiz(ctx, cq.Message.Chat, models.QuizFilter{Saved: data == "quiz_ns"})
+	case "quiz_l", "quiz_ls":
+		if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, "")); err != nil {
+			n.log.WithError(err).Warn("failed to ack learn next callback")
+		}
+		return n.sendLearnCard(ctx, chatID, cq.From.ID, data == "quiz_ls")
+	case "quiz_w":
+		if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, "")); err != nil {
+			n.log.WithError(err).Warn("failed to ack write next callback")
+		}
+		return n.sendWriteQuestion(ctx, chatID)
+	case "quiz_wg":
+		return n.giveUpWrite(ctx, cq)
+	}
+
+	if filter, ok := parseQuizNextData(data); ok {
 		if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, "")); err != nil {
 			n.log.WithError(err).Warn("failed to ack quiz next callback")
 		}
-		return n.HandleQuiz(ctx, cq.Message.Chat)
+		return n.HandleQuiz(ctx, cq.Message.Chat, filter)
 	}
 
-	parts := strings.Split(data, "_") // [quiz a chosen correct]
+	parts := strings.Split(data, "_") // [quiz a question chosen]
 	if len(parts) != 4 {
-		return fmt.Errorf("invalid quiz callback data: %q", data)
+		// Buttons from before questions were stored carry the old
+		// "quiz_a_<chosen>_<correct>" layout; they cannot be graded.
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, QuizExpiredToast))
+		return err
 	}
-	chosenIdx, err := strconv.Atoi(parts[2])
+	chosenIdx, err := strconv.Atoi(parts[3])
 	if err != nil {
 		return fmt.Errorf("invalid quiz chosen index: %w", err)
 	}
-	correctIdx, err := strconv.Atoi(parts[3])
+	q, err := n.repo.GetQuizQuestion(ctx, parts[2])
 	if err != nil {
-		return fmt.Errorf("invalid quiz correct index: %w", err)
+		return fmt.Errorf("repo.GetQuizQuestion: %w", err)
+	}
+	if q == nil {
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, QuizExpiredToast))
+		return err
+	}
+	if chosenIdx < 0 || chosenIdx >= len(q.Options) {
+		return fmt.Errorf("invalid quiz chosen index: %d", chosenIdx)
 	}
-
-	correct := chosenIdx == correctIdx
-
-	// Record the answer and fetch the running score for motivating feedback.
 	userID := cq.From.ID
-	if err := n.repo.RecordQuizAnswer(ctx, userID, cq.From.UserName, cq.From.FirstName, correct); err != nil {
-		n.log.WithError(err).WithField("user_id", userID).Warn("RecordQuizAnswer failed")
+	first, err := n.repo.ClaimQuizAnswer(ctx, q.ID, userID)
+	if err != nil {
+		return fmt.Errorf("repo.ClaimQuizAnswer: %w", err)
+	}
+	if !first {
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, QuizAlreadyAnsweredToast))
+		return err
 	}
 
+	correctIdx, reviewID := q.CorrectIdx, q.ReviewID
+	correct := chosenIdx == correctIdx
+	n.logQuizAnswer(ctx, userID, q, chosenIdx)
+
 	toast := QuizWrongToast
 	if correct {
 		toast = QuizCorrectToast
 	}
-	if score, total, streak, err := n.repo.GetQuizScore(ctx, userID); err != nil {
-		n.log.WithError(err).WithField("user_id", userID).Warn("GetQuizScore failed")
-	} else if total > 0 {
-		toast += fmt.Sprintf("  ·  Счёт: %d/%d (%d%%)", score, total, score*100/total)
-		if streak >= 2 {
-			toast += fmt.Sprintf("  ·  🔥 %d дн.", streak)
+	next := quizNextData(q.Filter)
+	if reviewID > 0 {
+		// A /learn answer moves the word's schedule, not the /top score:
+		// the deck repeats words on purpose, and a leaderboard fed by
+		// repeats would reward drilling the same ten words.
+		next = "quiz_l"
+		if q.Filter.Saved {
+			next = "quiz_ls"
+		}
+		if when, err := n.gradeReview(ctx, userID, reviewID, correct); err != nil {
+			n.log.WithError(err).WithField("user_id", userID).Warn("gradeReview failed")
+		} else {
+			toast += when
+		}
+	} else if !q.Filter.Saved {
+		// Record the answer and fetch the running score for motivating
+		// feedback. Questions on the user's own saved words stay off /top,
+		// as /learn answers do: they repeat the same words by design.
+		if err := n.repo.RecordQuizAnswer(ctx, userID, cq.From.UserName, cq.From.FirstName, correct); err != nil {
+			n.log.WithError(err).WithField("user_id", userID).Warn("RecordQuizAnswer failed")
 		}
+		toast += n.quizScoreSuffix(ctx, userID)
 	}
 
 	if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, toast)); err != nil {
@@ -265,14 +518,23 @@ func (n *Net) HandleQuizCallback(ctx context.Context, cq *tgbotapi.CallbackQuery
 		))
 	}
 	// Once answered, the word is worth a closer look — open its full
-	// dictionary card via an inline query pre-filled with the prompt.
-	if word := quizPromptFromMessage(cq.Message.Text); word != "" {
+	// dictionary card via an inline query pre-filled with the prompt. A
+	// phrase with a word missing is no query; the missing word is.
+	word := quizPromptFromMessage(cq.Message.Text)
+	if q.Kind == models.QuizKindPhrase {
+		word = q.Options[correctIdx]
+	}
+	if word != "" {
 		newRows = append(newRows, tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.InlineKeyboardButton{Text: QuizLookupButtonText, SwitchInlineQueryCurrentChat: &word},
 		))
 	}
+	nextText := QuizNextButtonText
+	if reviewID > 0 {
+		nextText = LearnNextButtonText
+	}
 	newRows = append(newRows, tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData(QuizNextButtonText, "quiz_n"),
+		tgbotapi.NewInlineKeyboardButtonData(nextText, next),
 	))
 
 	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, cq.Message.MessageID, tgbotapi.NewInlineKeyboardMarkup(newRows...))
@@ -281,3 +543,114 @@ func (n *Net) HandleQuizCallback(ctx context.Context, cq *tgbotapi.CallbackQuery
 	}
 	return nil
 }
+
+// quizNextData is the "next question" callback for a question drawn under f:
+// plain "quiz_n" for an unfiltered one, so buttons sent before filters
+// existed keep working, "_g" appended for grammar questions, and "quiz_ns"
+// for the saved words, whatever else the filter says.
+func quizNextData(f models.QuizFilter) string {
+	if f.Saved {
+		return "quiz_ns"
+	}
+	if f == (models.QuizFilter{}) {
+		return "quiz_n"
+	}
+	data := fmt.Sprintf("quiz_n_%d_%d", f.Subtype, f.Rate)
+	if f.Grammar {
+		data += "_g"
+	}
+	return data
+}
+
+// parseQuizNextData reads a filtered "next question" callback back.
+func parseQuizNextData(data string) (models.QuizFilter, bool) {
+	var f models.QuizFilter
+	rest, ok := strings.CutPrefix(data, "quiz_n_")
+	if !ok {
+		return f, false
+	}
+	rest, f.Grammar = strings.CutSuffix(rest, "_g")
+	subtype, rate, ok := strings.Cut(rest, "_")
+	if !ok {
+		return f, false
+	}
+	var err error
+	if f.Subtype, err = strconv.Atoi(subtype); err != nil {
+		return f, false
+	}
+	if f.Rate, err = strconv.Atoi(rate); err != nil {
+		return f, false
+	}
+	return f, true
+}
+
+// quizScoreSuffix is the running score appended to an answer's verdict, with
+// the streak once it is worth mentioning; "" when there is none to show.
+func (n *Net) quizScoreSuffix(ctx context.Context, userID int64) string {
+	score, total, streak, err := n.repo.GetQuizScore(ctx, userID)
+	if err != nil {
+		n.log.WithError(err).WithField("user_id", userID).Warn("GetQuizScore failed")
+		return ""
+	}
+	if total == 0 {
+		return ""
+	}
+	suffix := fmt.Sprintf("  ·  Счёт: %d/%d (%d%%)", score, total, score*100/total)
+	if streak >= 2 {
+		suffix += fmt.Sprintf("  ·  🔥 %d дн.", streak)
+	}
+	return suffix
+}
+
+// logQuizAnswer records an answer to a stored question in the answer log.
+// Latency runs from when the question was stored, just before it was sent.
+func (n *Net) logQuizAnswer(ctx context.Context, userID int64, q *models.StoredQuiz, chosenIdx int) {
+	answer := models.QuizAnswer{
+		UserID:        userID,
+		Prompt:        q.Prompt,
+		Reversed:      q.Reversed,
+		Chosen:        q.Options[chosenIdx],
+		CorrectOption: q.Options[q.CorrectIdx],
+		Correct:       chosenIdx == q.CorrectIdx,
+		ChatType:      q.ChatType,
+		Mode:          quizAnswerMode(q),
+		Latency:       time.Since(q.SentAt),
+		Kind:          q.Kind,
+	}
+	if err := n.repo.LogQuizAnswer(ctx, answer); err != nil {
+		n.log.WithError(err).WithField("user_id", userID).Warn("LogQuizAnswer failed")
+	}
+}
+
+// quizAnswerMode is the mode a stored question's answer is logged under. A
+// question on the user's own saved words gets its own: the season boards
+// read the log, and those answers stay off them as they stay off /top.
+func quizAnswerMode(q *models.StoredQuiz) string {
+	switch {
+	case q.ReviewID > 0:
+		return models.QuizModeLearn
+	case q.Filter.Saved:
+		return models.QuizModeSaved
+	}
+	return models.QuizModeQuiz
+}
+
+// logQuestionAnswer is logQuizAnswer for a question kept outside the quiz
+// question store, such as a daily challenge's or a duel's, which carries no
+// send time to measure latency from.
+func (n *Net) logQuestionAnswer(ctx context.Context, userID int64, q *models.QuizQuestion, chosenIdx int, chatType, mode string) {
+	answer := models.QuizAnswer{
+		UserID:        userID,
+		Prompt:        q.Prompt,
+		Reversed:      q.Reversed,
+		Chosen:        q.Options[chosenIdx],
+		CorrectOption: q.Options[q.CorrectIdx],
+		Correct:       chosenIdx == q.CorrectIdx,
+		ChatType:      chatType,
+		Mode:          mode,
+		Kind:          q.Kind,
+	}
+	if err := n.repo.LogQuizAnswer(ctx, answer); err != nil {
+		n.log.WithError(err).WithField("user_id", userID).Warn("LogQuizAnswer failed")
+	}
+}
@@ -1,6 +1,9 @@
 package net
 
-import "testing"
+import (
+	"chetoru/internal/models"
+	"testing"
+)
 
 func TestQuizPromptFromMessage(t *testing.T) {
 	cases := []struct {
@@ -17,3 +20,68 @@ func TestQuizPromptFromMessage(t *testing.T) {
 		}
 	}
 }
+
+func TestParseQuizFilter(t *testing.T) {
+	cases := []struct {
+		args string
+		want models.QuizFilter
+		ok   bool
+	}{
+		{"", models.QuizFilter{}, true},
+		{"гл.", models.QuizFilter{Subtype: 1}, true},
+		{"Сущ", models.QuizFilter{Subtype: 2}, true},
+		{"academic", models.QuizFilter{Rate: models.RateAcademic}, true},
+		{"гл. academic", models.QuizFilter{Subtype: 1, Rate: models.RateAcademic}, true},
+		{"формы", models.QuizFilter{Grammar: true}, true},
+		{"сущ. формы", models.QuizFilter{Subtype: 2, Grammar: true}, true},
+		{"мои", models.QuizFilter{Saved: true}, true},
+		{"животные", models.QuizFilter{}, false},
+	}
+	for _, c := range cases {
+		got, ok := parseQuizFilter(c.args)
+		if got != c.want || ok != c.ok {
+			t.Errorf("parseQuizFilter(%q) = %+v, %v; want %+v, %v", c.args, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+// The "next question" button must keep a filtered quiz in its category.
+func TestQuizNextDataRoundTrips(t *testing.T) {
+	if got := quizNextData(models.QuizFilter{}); got != "quiz_n" {
+		t.Errorf("unfiltered next = %q, want quiz_n", got)
+	}
+	for _, f := range []models.QuizFilter{
+		{Subtype: 2, Rate: models.RateAcademic},
+		{Grammar: true},
+		{Subtype: 1, Grammar: true},
+	} {
+		if got, ok := parseQuizNextData(quizNextData(f)); !ok || got != f {
+			t.Errorf("round trip = %+v, %v; want %+v", got, ok, f)
+		}
+	}
+	if got := quizNextData(models.QuizFilter{Saved: true}); got != "quiz_ns" {
+		t.Errorf("saved-words next = %q, want quiz_ns", got)
+	}
+	if _, ok := parseQuizNextData("quiz_n"); ok {
+		t.Error("plain quiz_n is not a filtered callback")
+	}
+}
+
+func TestQuizAnswerMode(t *testing.T) {
+	tests := []struct {
+		name string
+		q    models.StoredQuiz
+		want string
+	}{
+		{"quiz", models.StoredQuiz{}, models.QuizModeQuiz},
+		{"filtered quiz", models.StoredQuiz{QuizQuestion: models.QuizQuestion{Filter: models.QuizFilter{Grammar: true}}}, models.QuizModeQuiz},
+		{"learn review", models.StoredQuiz{QuizQuestion: models.QuizQuestion{ReviewID: 3}}, models.QuizModeLearn},
+		{"saved-words learn", models.StoredQuiz{QuizQuestion: models.QuizQuestion{ReviewID: 3, Filter: models.QuizFilter{Saved: true}}}, models.QuizModeLearn},
+		{"saved-words quiz", models.StoredQuiz{QuizQuestion: models.QuizQuestion{Filter: models.QuizFilter{Saved: true}}}, models.QuizModeSaved},
+	}
+	for _, tt := range tests {
+		if got := quizAnswerMode(&tt.q); got != tt.want {
+			t.Errorf("%s: quizAnswerMode = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -32,6 +32,12 @@ func (n *Net) grammarHint(ctx context.Context, word string) string {
 // Deliberately unstyled: the same card carries a usage example, and italic is
 // what marks an example.
 func grammarSummaryLine(g *models.WordGrammar) string {
+	return tgbotapi.EscapeText(tgbotapi.ModeHTML, grammarSummaryText(g))
+}
+
+// grammarSummaryText is grammarSummaryLine as plain text, for where the hint
+// leaves Telegram: an /export deck's grammar field.
+func grammarSummaryText(g *models.WordGrammar) string {
 	if g == nil {
 		return ""
 	}
@@ -46,26 +52,37 @@ func grammarSummaryLine(g *models.WordGrammar) string {
 		}
 		cleaned := make([]string, 0, len(forms))
 		for _, f := range forms {
-			cleaned = append(cleaned, tgbotapi.EscapeText(tgbotapi.ModeHTML, tools.Clean(f)))
+			cleaned = append(cleaned, tools.Clean(f))
 		}
 		parts = append(parts, "формы: "+strings.Join(cleaned, ", "))
 	}
-	if len(parts) == 0 {
-		return ""
-	}
 	return strings.Join(parts, " · ")
 }
 
 // wordCardButtons builds the keyboard for discovery cards: another word, plus
 // sharing this one into any chat via a pre-filled inline query — every shared
-// card carries the bot's handle to whoever receives it.
+// card carries the bot's handle to whoever receives it — and saving it to
+// the tapper's /mywords.
 func wordCardButtons(chechen string) tgbotapi.InlineKeyboardMarkup {
-	return tgbotapi.NewInlineKeyboardMarkup(
+	rows := [][]tgbotapi.InlineKeyboardButton{
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData(RandomMoreButtonText, "random_more"),
 			tgbotapi.InlineKeyboardButton{Text: ShareWordButtonText, SwitchInlineQuery: &chechen},
 		),
-	)
+	}
+	if save, ok := saveButtonRow(chechen); ok {
+		rows = append(rows, save)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// spelling is the Chechen side of a discovery card to transcribe: the
+// stressed spelling when the dictionary had one.
+func spelling(word *models.RandomWord) string {
+	if word.Stressed != "" {
+		return word.Stressed
+	}
+	return word.Chechen
 }
 
 // HandleRandom sends a random Chechen word from the dictionary so users can
@@ -96,6 +113,9 @@ func (n *Net) HandleRandom(ctx context.Context, chatID int64) error {
 		tgbotapi.EscapeText(tgbotapi.ModeHTML, tools.Clean(word.Chechen)),
 		tgbotapi.EscapeText(tgbotapi.ModeHTML, tools.Clean(word.Russian)),
 	)
+	if line := n.pronunciationLine(spelling(word)); line != "" {
+		text += "\n" + line
+	}
 
 	// Both enrichments hit live APIs on fresh words; fetch them concurrently so
 	// the card costs one round trip, not two.
@@ -0,0 +1,184 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ruMonths names the months in the nominative, for season labels.
+var ruMonths = [...]string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"}
+
+// seasonLabel names a season for people: «неделя 12.10–18.10», «октябрь 2026».
+func seasonLabel(s models.QuizSeason) string {
+	if s.Period == models.SeasonMonth {
+		return fmt.Sprintf("%s %d", ruMonths[s.Start.Month()-1], s.Start.Year())
+	}
+	return fmt.Sprintf("неделя %s–%s", s.Start.Format("02.01"), s.End.AddDate(0, 0, -1).Format("02.01"))
+}
+
+// HandleSeasonTop renders the current week's or month's board. The lifetime
+// board rewards whoever started first; this one gives a newcomer a top ten
+// they can reach by Sunday.
+func (n *Net) HandleSeasonTop(ctx context.Context, chatID, userID int64, period string) error {
+	season := models.SeasonAt(period, time.Now())
+	scorers, err := n.repo.TopQuizScorersBetween(ctx, season.Start, season.End, QuizTopLimit)
+	if err != nil {
+		return fmt.Errorf("repo.TopQuizScorersBetween: %w", err)
+	}
+	if len(scorers) == 0 {
+		_, err = n.send(tgbotapi.NewMessage(chatID, QuizSeasonTopEmptyText))
+		return err
+	}
+
+	var b strings.Builder
+	if period == models.SeasonMonth {
+		b.WriteString(QuizMonthTopHeader)
+	} else {
+		b.WriteString(QuizWeekTopHeader)
+	}
+	if !writeQuizBoard(&b, scorers, userID) {
+		if rank, correct, total, err := n.repo.GetQuizRankBetween(ctx, userID, season.Start, season.End); err != nil {
+			n.log.WithError(err).WithField("user_id", userID).Warn("GetQuizRankBetween failed")
+		} else if rank > 0 {
+			fmt.Fprintf(&b, "\n👤 Вы: <b>№%d</b> — %d/%d (%d%%)\n", rank, correct, total, correct*100/total)
+		}
+	}
+	b.WriteString(QuizTopBoardsHint)
+
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	msg.ParseMode = "html"
+	_, err = n.send(msg)
+	return err
+}
+
+// HandleSeasonWinners lists the podiums of the last closed weeks and months.
+func (n *Net) HandleSeasonWinners(ctx context.Context, chatID int64) error {
+	var b strings.Builder
+	b.WriteString(SeasonWinnersHeader)
+	found := false
+	for _, period := range []string{models.SeasonWeek, models.SeasonMonth} {
+		winners, err := n.repo.ListSeasonWinners(ctx, period, SeasonWinnersLimit)
+		if err != nil {
+			return fmt.Errorf("repo.ListSeasonWinners: %w", err)
+		}
+		season := ""
+		for _, w := range winners {
+			if w.Season != season {
+				season = w.Season
+				fmt.Fprintf(&b, "\n<b>%s</b>\n", seasonKeyLabel(period, season))
+			}
+			fmt.Fprintf(&b, "%s %s — %d/%d\n", quizMedal(w.Rank-1), tgbotapi.EscapeText(tgbotapi.ModeHTML, scorerName(w.QuizScorer)), w.Correct, w.Total)
+			found = true
+		}
+	}
+	if !found {
+		_, err := n.send(tgbotapi.NewMessage(chatID, SeasonWinnersEmptyText))
+		return err
+	}
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	msg.ParseMode = "html"
+	_, err := n.send(msg)
+	return err
+}
+
+// seasonKeyLabel is seasonLabel for an archived season known by its key,
+// which for a week is its ISO week and for a month its first day.
+func seasonKeyLabel(period, key string) string {
+	var t time.Time
+	var err error
+	if period == models.SeasonMonth {
+		t, err = time.ParseInLocation("2006-01", key, time.Local)
+	} else {
+		var year, week int
+		if _, err = fmt.Sscanf(key, "%d-W%d", &year, &week); err == nil {
+			// 4 January is always in ISO week 1.
+			jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.Local)
+			t = models.SeasonAt(models.SeasonWeek, jan4).Start.AddDate(0, 0, 7*(week-1))
+		}
+	}
+	if err != nil {
+		return key
+	}
+	return seasonLabel(models.SeasonAt(period, t))
+}
+
+// StartSeasonScheduler launches the daily check that closes finished weeks
+// and months: it archives each one's podium and tells every ranked player
+// where they finished. Closing is idempotent — the archive row is the marker
+// — so the check runs on startup too, catching up a close missed while the
+// bot was down.
+func (n *Net) StartSeasonScheduler(ctx context.Context) {
+	go func() {
+		n.closeSeasons(ctx)
+		for {
+			next := nextWordOfDayTime(time.Now(), SeasonCloseHour)
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				n.closeSeasons(ctx)
+			}
+		}
+	}()
+}
+
+// closeSeasons closes the week and the month before the current ones, if
+// they are not closed yet.
+func (n *Net) closeSeasons(ctx context.Context) {
+	now := time.Now()
+	for _, period := range []string{models.SeasonWeek, models.SeasonMonth} {
+		n.closeSeason(ctx, models.SeasonAt(period, now).Previous())
+	}
+}
+
+func (n *Net) closeSeason(ctx context.Context, season models.QuizSeason) {
+	standings, err := n.repo.TopQuizScorersBetween(ctx, season.Start, season.End, -1)
+	if err != nil {
+		n.log.WithError(err).WithField("season", season.Key).Error("seasons: standings")
+		return
+	}
+	podium := standings[:min(len(standings), SeasonPodiumSize)]
+	// Archived before the announcements, mirroring the word-of-day
+	// at-most-once rule: a crash mid-send loses some messages, never
+	// repeats them.
+	closed, err := n.repo.CloseQuizSeason(ctx, season, len(standings), podium)
+	if err != nil {
+		n.log.WithError(err).WithField("season", season.Key).Error("seasons: close")
+		return
+	}
+	if !closed || len(standings) == 0 {
+		return
+	}
+	n.log.Infof("seasons: %s %s closed, announcing to %d players", season.Period, season.Key, len(standings))
+
+	var board strings.Builder
+	writeQuizBoard(&board, podium, 0)
+	for i, s := range standings {
+		select {
+		case <-ctx.Done():
+			n.log.Info("seasons: interrupted by shutdown")
+			return
+		default:
+		}
+		text := fmt.Sprintf(SeasonEndFormat, seasonLabel(season), i+1, len(standings), s.Correct, s.Total, board.String(), season.Period)
+		out := tgbotapi.NewMessage(s.UserID, text)
+		out.ParseMode = "html"
+		if _, err := n.send(out); err != nil {
+			if n.isBlockedError(err) {
+				if mErr := n.repo.MarkUserBlocked(ctx, s.UserID, "season_end"); mErr != nil {
+					n.log.WithError(mErr).WithField("user_id", s.UserID).Warn("seasons: mark blocked")
+				}
+			} else {
+				n.log.WithError(err).WithField("user_id", s.UserID).Warn("seasons: send failed")
+			}
+		}
+		time.Sleep(BroadcastSendDelay)
+	}
+}
@@ -0,0 +1,28 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"testing"
+	"time"
+)
+
+func TestSeasonLabels(t *testing.T) {
+	at := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	week := models.SeasonAt(models.SeasonWeek, at)
+	month := models.SeasonAt(models.SeasonMonth, at)
+	if got := seasonLabel(week); got != "неделя 12.10–18.10" {
+		t.Errorf("week label = %q", got)
+	}
+	if got := seasonLabel(month); got != "октябрь 2026" {
+		t.Errorf("month label = %q", got)
+	}
+	// The archive keeps only the key; it must name the same season.
+	for _, s := range []models.QuizSeason{week, month, models.SeasonAt(models.SeasonWeek, time.Date(2027, 1, 1, 0, 0, 0, 0, time.Local))} {
+		if got, want := seasonKeyLabel(s.Period, s.Key), seasonLabel(s); got != want {
+			t.Errorf("seasonKeyLabel(%s) = %q, want %q", s.Key, got, want)
+		}
+	}
+	if got := seasonKeyLabel(models.SeasonWeek, "garbage"); got != "garbage" {
+		t.Errorf("unparsable key = %q, want it shown as is", got)
+	}
+}
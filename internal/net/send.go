@@ -30,6 +30,17 @@ func sendWithRetry(send func(tgbotapi.Chattable) (tgbotapi.Message, error), c tg
 	return send(plain)
 }
 
+// request is send for calls Telegram answers with something other than a
+// Message — an edit of a message posted through inline mode answers true —
+// which bot.Send would fail to decode.
+func (n *Net) request(c tgbotapi.Chattable) error {
+	_, err := sendWithRetry(func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+		_, err := n.bot.Request(c)
+		return tgbotapi.Message{}, err
+	}, c)
+	return err
+}
+
 // answerInline delivers an inline answer with the same fallback. Telegram
 // validates the whole result set at once, so a single malformed card blanks the
 // picker for every result beside it.
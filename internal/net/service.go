@@ -5,6 +5,7 @@ import (
 	"chetoru/internal/cache"
 	"chetoru/internal/models"
 	"chetoru/internal/repository"
+	"chetoru/pkg/tools"
 	"sync"
 
 	"context"
@@ -26,8 +27,27 @@ const (
 	// No <i>: on a translation card italic marks a usage example and nothing
 	// else, and this text sits right under one.
 	MoreTranslationsHelpText = `Чтобы просмотреть все доступные переводы, нажмите на кнопку «Ещё» или воспользуйтесь инлайн-режимом: введите @chetoru_bot и слово, которое хотите перевести. Это позволит вам увидеть все варианты.`
-	StartMessageText         = "Отправь мне слово на русском или чеченском, а я скину перевод. Ещё ты можешь пользоваться ботом в других переписках, как на видео.\n\n🎲 /random — случайное чеченское слово.\n🧠 /quiz — викторина: проверь, как хорошо ты знаешь чеченский.\n🏆 /top — рейтинг знатоков.\n👤 /me — мой прогресс.\n📖 /wotd — слово дня каждое утро.\n✍️ /check — проверить орфографию (или начни сообщение с точки).\n\nСловарные данные предоставлены проектом dosham.app"
+	StartMessageText         = "Отправь мне слово на русском или чеченском, а я скину перевод. Ещё ты можешь пользоваться ботом в других переписках, как на видео.\n\n🎲 /random — случайное чеченское слово.\n🧠 /quiz — викторина: проверь, как хорошо ты знаешь чеченский.\n📚 /learn — учить слова с интервальным повторением.\n⭐ /mywords — сохранённые слова: /quiz мои и /learn мои — только по ним.\n📤 /export — сохранённые слова колодой Anki или таблицей.\n🕘 /history — недавние запросы.\n✍️ /write — написать слово по-чеченски.\n📅 /daily — вызов дня: 10 вопросов, одни на всех.\n⚔️ /duel — дуэль с другом: @chetoru_bot дуэль в любом чате.\n🟩 /wordle — угадай чеченское слово дня.\n🏟 /tournament — турнир в группе.\n🏆 /top — рейтинг знатоков.\n👤 /me — мой прогресс.\n📖 /wotd — слово дня каждое утро.\n✍️ /check — проверить орфографию (или начни сообщение с точки).\n🔤 /gloss — разобрать текст по словам.\n\nСловарные данные предоставлены проектом dosham.app"
 	NoTranslationText        = "К сожалению, нет перевода"
+	// Heads a card answered through the form index: the user typed an inflected
+	// Chechen form and is reading its headword's entry.
+	FormOfNoteFormat = "<i>форма слова</i> <b>%s</b>"
+	// Heads a card found by retyping a Latin query in Cyrillic, so the user
+	// sees which word the bot decided they meant.
+	RespelledNoteFormat = "<i>по запросу</i> <b>%s</b>"
+	// Under the headword when pronunciation is on: learner's Latin, then IPA.
+	// Unstyled, like the grammar hint — italic is the example's.
+	PronunciationFormat = "🗣 %s · [%s]"
+	// Admin-only line under a card: which dictionary sources answered.
+	ProvenanceFooterFormat = "<i>источники: %s</i>"
+	// /gloss: an interlinear, word-by-word reading of a text.
+	GlossUsageText     = "Использование: /gloss <текст на чеченском или русском>\n\nИли ответь командой /gloss на сообщение, которое нужно разобрать."
+	GlossHeaderText    = "🔤 <b>Подстрочник</b>"
+	GlossUnknownMark   = "❓"
+	GlossFailedMark    = "⚠️ словарь недоступен"
+	GlossTruncatedText = "<i>…дальше текст не разобран: слишком длинный</i>"
+	// Heads the row of nearest lexicon words offered under a miss.
+	DidYouMeanText = "Возможно, вы имели в виду:"
 	// Shown when the dictionary itself failed. Saying "нет перевода" there is a
 	// lie, and it is the lie that also files the user's word as a vocabulary gap.
 	DictionaryUnavailableText = "Словарь сейчас недоступен. Попробуйте через минуту."
@@ -45,17 +65,65 @@ const (
 	RandomEmptyText           = "Словарь пока пуст. Попробуйте перевести несколько слов, и они появятся здесь!"
 	QuizQuestionFormat        = "🧠 <b>Викторина</b>\n\nКак переводится на русский?\n\n<b>%s</b>"
 	QuizQuestionReverseFormat = "🧠 <b>Викторина</b>\n\nКак сказать по-чеченски?\n\n<b>%s</b>"
+	QuizFormQuestionFormat    = "🧠 <b>Викторина</b>\n\nКакое из слов — форма слова?\n\n<b>%s</b>"
+	QuizIdiomQuestionFormat   = "🧠 <b>Викторина</b>\n\nЧто значит выражение?\n\n<b>%s</b>"
+	QuizPhraseQuestionFormat  = "🧠 <b>Викторина</b>\n\nКакое слово пропущено в выражении?\n<i>%s</i>\n\n<b>%s</b>"
 	QuizNextButtonText        = "➡️ Следующий вопрос"
 	QuizLookupButtonText      = "📖 Открыть в словаре"
 	QuizCorrectToast          = "✅ Верно!"
 	QuizWrongToast            = "❌ Неверно"
 	QuizErrorText             = "Не удалось составить вопрос. Попробуйте /quiz ещё раз."
 	QuizTopLimit              = 10
-	QuizTopHeader             = "🏆 <b>Топ знатоков чеченского</b>\n<i>по количеству верных ответов в /quiz</i>\n\n"
-	QuizTopEmptyText          = "Пока никто не набрал очков в /quiz. Стань первым! 🧠"
-	WordOfDayHour             = 9 // local hour (container TZ is Europe/Moscow)
-	WordOfDayFormat           = "📖 <b>Слово дня</b>\n\n<b>%s</b> — %s"
-	WordOfDayExampleFormat    = "✍️ <i>%s</i>"
+	QuizFilterUsageText       = "Викторина по категории:\n/quiz гл. — глаголы\n/quiz сущ. — существительные\n/quiz прил. — прилагательные\n/quiz нареч. — наречия\n/quiz мест. — местоимения\n/quiz academic — слова из академического словаря\n/quiz формы — формы слов и устойчивые выражения\n/quiz мои — ваши сохранённые слова (/mywords)\n\nКатегории можно совмещать: /quiz гл. academic"
+	// QuizQuestionTTL is how long a sent question can still be answered;
+	// after it the question is pruned and its buttons answer QuizExpiredToast.
+	QuizQuestionTTL          = 24 * time.Hour
+	QuizExpiredToast         = "⌛ Этот вопрос устарел. Нажмите /quiz для нового."
+	QuizAlreadyAnsweredToast = "Вы уже ответили на этот вопрос."
+	MeWeakestWordsLimit      = 5
+	HardestWordsLimit        = 20
+	HardestWordsMinAttempts  = 5 // answers a prompt needs before its error rate means anything
+	HardestWordsHeader       = "<b>🤔 Самые трудные слова викторины</b>\n\n<i>По доле неверных ответов; учитываются слова минимум с 5 ответами.</i>\n\n"
+	HardestWordsEmpty        = "Пока мало ответов, чтобы судить о трудных словах."
+	HardestWordRowFormat     = "%d. <b>%s</b> %s — %d из %d неверно (%d%%)\n"
+	// /learn: spaced repetition over the user's own deck.
+	LearnNewPerDay             = 10 // new words a user is introduced to per day
+	LearnQuestionFormat        = "📚 <b>Повторение</b>\n\nКак переводится на русский?\n\n<b>%s</b>"
+	LearnQuestionReverseFormat = "📚 <b>Повторение</b>\n\nКак сказать по-чеченски?\n\n<b>%s</b>"
+	LearnNextButtonText        = "➡️ Дальше"
+	LearnNextReviewFormat      = "  ·  повтор через %d дн."
+	LearnRelearnToast          = "  ·  повторим через 10 минут"
+	LearnDoneText              = "📚 На сегодня всё: повторять нечего, новые слова на сегодня закончились. Возвращайтесь завтра!"
+	LearnErrorText             = "Не удалось составить карточку. Попробуйте /learn ещё раз."
+	LearnPrivateOnlyText       = "📚 Повторение — личное: напишите /learn боту в личные сообщения."
+	// /write: the typed-answer quiz.
+	WriteQuestionFormat     = "✍️ <b>Напишите по-чеченски</b>\n\n<b>%s</b>\n\n<i>Ответ — следующим сообщением. Вместо Ӏ можно писать 1.</i>"
+	WriteGiveUpButtonText   = "🏳️ Показать ответ"
+	WriteNextButtonText     = "✍️ Ещё слово"
+	WriteFormAcceptedFormat = "✅ Верно! Это форма слова <b>%s</b>."
+	WriteNearMissFormat     = "🤏 Почти! Правильно: <b>%s</b>"
+	WriteWrongFormat        = "❌ Неверно. Правильно: <b>%s</b>"
+	WriteGaveUpFormat       = "🏳️ Правильный ответ: <b>%s</b>"
+	WriteErrorText          = "Не удалось подобрать слово. Попробуйте /write ещё раз."
+	WritePrivateOnlyText    = "✍️ Ответ пишется сообщением, поэтому /write работает только в личных сообщениях с ботом."
+	WriteAnswerTimeout      = 10 * time.Minute // after it the next message is a lookup again
+	QuizTopHeader           = "🏆 <b>Топ знатоков чеченского</b>\n<i>по количеству верных ответов в /quiz</i>\n\n"
+	QuizTopEmptyText        = "Пока никто не набрал очков в /quiz. Стань первым! 🧠"
+	QuizChatTopHeader       = "🏆 <b>Рейтинг чата</b>\n<i>по верным ответам на викторины в этом чате; общий рейтинг — /top all</i>\n\n"
+	QuizChatTopEmptyText    = "В этом чате пока никто не отвечал на викторины. Начните с /quiz или /tournament! 🧠"
+	QuizTopBoardsHint       = "\n<i>Топ недели — /top week, месяца — /top month, чемпионы прошлых сезонов — /top winners</i>"
+	QuizWeekTopHeader       = "🏆 <b>Топ недели</b>\n<i>по верным ответам в /quiz с понедельника; в понедельник все начнут с нуля</i>\n\n"
+	QuizMonthTopHeader      = "🏆 <b>Топ месяца</b>\n<i>по верным ответам в /quiz с 1-го числа; новый месяц все начнут с нуля</i>\n\n"
+	QuizSeasonTopEmptyText  = "В этом сезоне ещё никто не ответил на 3 вопроса /quiz. Стань первым! 🧠"
+	SeasonWinnersHeader     = "🏅 <b>Чемпионы прошлых сезонов</b>\n"
+	SeasonWinnersEmptyText  = "Ни один сезон ещё не закончился. Первые итоги — в понедельник!"
+	SeasonWinnersLimit      = 5 // past seasons of each period shown
+	SeasonPodiumSize        = 3
+	SeasonCloseHour         = 10 // local hour the finished seasons are announced
+	SeasonEndFormat         = "🏁 <b>Итоги: %s</b>\n\nВаше место — <b>№%d</b> из %d (%d/%d верных).\n\n%s\nНовый сезон уже идёт: /top %s"
+	WordOfDayHour           = 9 // local hour (container TZ is Europe/Moscow)
+	WordOfDayFormat         = "📖 <b>Слово дня</b>\n\n<b>%s</b> — %s"
+	WordOfDayExampleFormat  = "✍️ <i>%s</i>"
 	// No 🇨🇪: CE is unassigned in ISO 3166-1, so it is not a flag anywhere —
 	// clients render two letter tiles. And no <i>: the card above already
 	// spends italic on its usage example.
@@ -80,6 +148,140 @@ const (
 	SubscriptionPriceKopecks   = 10000 // 100 RUB
 	SubscriptionPriceFormatted = "100 ₽"
 	SubscriptionDuration       = 30 * 24 * time.Hour // 30 days
+
+	// /tournament: timed quiz polls in a row in a group.
+	TournamentDefaultRounds     = 5
+	TournamentMaxRounds         = 20
+	TournamentRoundTime         = 30 * time.Second
+	TournamentAnswerGrace       = 3 * time.Second // for answers sent just before the poll closed
+	TournamentStandingsLimit    = 10
+	TournamentUsageText         = "🏟 <b>Турнир</b>: /tournament N — N вопросов подряд (от 1 до 20), на каждый 30 секунд. Без числа — 5 вопросов.\n\nОстановить турнир может администратор чата: /tournament стоп"
+	TournamentGroupOnlyText     = "🏟 Турниры проходят в группах: добавьте бота в чат и напишите там /tournament."
+	TournamentRunningText       = "🏟 В этом чате уже идёт турнир. Администратор чата может остановить его: /tournament стоп"
+	TournamentNotRunningText    = "🏟 Сейчас в этом чате нет турнира."
+	TournamentAdminOnlyText     = "Остановить турнир может только администратор чата."
+	TournamentStartFormat       = "🏟 <b>Турнир начинается!</b>\n\nВопросов: %d, на каждый — %d секунд. Очки идут и в рейтинг чата: /top"
+	TournamentPollFormat        = "🏟 %d/%d. %s"
+	TournamentRoundResultFormat = "⏱ <b>Вопрос %d/%d</b> — правильный ответ: <b>%s</b>\n\n"
+	TournamentFinalFormat       = "🏁 <b>Турнир окончен!</b>\nПравильный ответ на последний вопрос: <b>%s</b>\n\n"
+	TournamentNoAnswersText     = "<i>Никто не ответил.</i>"
+	TournamentCancelledText     = "🛑 Турнир остановлен администратором чата.\n\n"
+	TournamentInterruptedText   = "🛑 Турнир прерван: бот перезапускается.\n\n"
+	TournamentShutdownText      = "🛑 Бот перезапускается, турнир не начнётся. Попробуйте через минуту."
+	TournamentErrorText         = "🛑 Не удалось составить вопрос, турнир остановлен.\n\n"
+
+	QuizAskTranslation      = "Как переводится на русский?"
+	QuizAskChechen          = "Как сказать по-чеченски?"
+	QuizCorrectAnswerFormat = "Правильно: %s"
+
+	// /daily: the same ten questions for everyone, once a day.
+	DailyQuestionFormat    = "📅 <b>Вызов дня</b> · вопрос %d/%d\n\n%s\n\n<b>%s</b>"
+	DailyResultFormat      = "📅 <b>Вызов дня %s</b>\n\n%s\n\nВерно: <b>%d/%d</b> за %s"
+	DailyRankFormat        = "\nМесто: <b>№%d</b> из %d"
+	DailyComeBackText      = "\n\nНовый вызов — завтра. Поделитесь результатом с друзьями!"
+	DailyShareFormat       = "📅 Вызов дня %s: %d/%d\n%s\n⏱ %s\n\nСможете лучше?"
+	DailyShareQuery        = "#вызов"
+	DailyShareButtonText   = "📤 Поделиться результатом"
+	DailyTopButtonText     = "🏆 Рейтинг дня"
+	DailyAcceptButtonText  = "📅 Принять вызов"
+	DailyInlineTitleFormat = "📅 Мой вызов дня: %d/%d"
+	DailyInlinePlayText    = "📅 Сначала пройдите вызов дня"
+	DailyTopHeaderFormat   = "🏆 <b>Вызов дня %s</b>\n<i>по верным ответам, при равенстве — кто быстрее</i>\n\n"
+	DailyTopEmptyText      = "Сегодня вызов дня ещё никто не прошёл. Будьте первым: /daily"
+	DailyTopLimit          = 10
+	DailyPrivateOnlyText   = "📅 Вызов дня проходят в личных сообщениях: напишите боту /daily. Рейтинг дня — /daily top"
+	DailyErrorText         = "Не удалось составить вызов дня. Попробуйте /daily ещё раз."
+
+	// Duels: one on one, challenged through inline mode.
+	DuelQuestions               = 5
+	DuelTTL                     = 24 * time.Hour // to accept a challenge, and to finish a duel
+	DuelQuery                   = "дуэль"
+	DuelInlineTitle             = "⚔️ Дуэль"
+	DuelInlineDescriptionFormat = "Вызвать собеседника на дуэль: %d вопросов, кто ответит верно на большее число"
+	DuelChallengeFormat         = "⚔️ <b>%s</b> вызывает на дуэль по чеченскому!\n\n%d вопросов, одни на двоих: кто ответит верно на большее число, тот и победил."
+	DuelAcceptButtonText        = "⚔️ Принять вызов"
+	DuelAcceptedFormat          = "⚔️ <b>Дуэль</b>: %s против %s\n\nИдёт игра — оба отвечают на %d вопросов в личных сообщениях с ботом. Счёт появится здесь."
+	DuelPlayButtonText          = "▶️ К вопросам"
+	DuelQuestionFormat          = "⚔️ <b>Дуэль с %s</b> · вопрос %d/%d\n\n%s\n\n<b>%s</b>"
+	DuelWaitingFormat           = "⚔️ <b>Дуэль с %s</b>\n\n%s\nВаш результат: <b>%d/%d</b>. Ждём соперника — итог придёт сюда."
+	DuelResultHeader            = "⚔️ <b>Дуэль окончена</b>\n\n"
+	DuelPlayerLineFormat        = "<b>%s</b> — %d/%d %s\n"
+	DuelWinnerFormat            = "\n🏆 Победа: <b>%s</b>!"
+	DuelDrawText                = "\n🤝 Ничья!"
+	DuelRatingFormat            = "\n\n<i>Рейтинг дуэлей: %s — %d (%+d), %s — %d (%+d)</i>"
+	DuelRematchButtonText       = "⚔️ Новая дуэль"
+	DuelOwnChallengeToast       = "Это ваш вызов — дождитесь соперника."
+	DuelTakenToast              = "Этот вызов уже принял другой участник."
+	DuelExpiredToast            = "⌛ Этот вызов устарел."
+	DuelErrorToast              = "Не удалось составить вопросы. Попробуйте ещё раз."
+	DuelExpiredText             = "⌛ Эта дуэль устарела или её не существует."
+	DuelNotYoursText            = "Это чужая дуэль. Бросьте вызов сами: наберите @chetoru_bot дуэль в любом чате."
+	DuelNotAcceptedText         = "Соперник ещё не принял вызов."
+	DuelHelpText                = "⚔️ <b>Дуэли</b>\n\nВ любом чате наберите <code>@chetoru_bot дуэль</code> и отправьте вызов. Соперник принимает его кнопкой, оба отвечают на одни и те же 5 вопросов здесь, в личке, а счёт появится в том же чате.\n\nПобеды поднимают рейтинг дуэлей (по системе Эло). Лучшие — /duel top"
+	DuelMyRatingFormat          = "\n\n👤 Ваш рейтинг: <b>%d</b> (№%d) · побед %d, ничьих %d, поражений %d"
+	DuelInviteButtonText        = "⚔️ Бросить вызов"
+	DuelTopHeader               = "⚔️ <b>Рейтинг дуэлей</b>\n<i>по системе Эло: победа над сильным соперником даёт больше очков; рядом — победы, ничьи и поражения</i>\n\n"
+	DuelTopEmptyText            = "Ещё ни одна дуэль не закончилась. Бросьте вызов первым: @chetoru_bot дуэль"
+	DuelTopLimit                = 10
+
+	// /wordle: guess the day's Chechen word, a letter grid at a time.
+	WordleMaxGuesses        = 6
+	WordleGuessTimeout      = 30 * time.Minute // after it a five-letter message is a lookup again
+	WordleHeaderFormat      = "🟩 <b>Чеченский вордл %s</b>\n\n"
+	WordleRulesFormat       = "Угадайте чеченское слово из %d букв за %d попыток. Кх, аь, гӏ и другие двойные буквы считаются одной буквой, вместо Ӏ можно писать 1.\n\n🟩 буква на месте\n🟨 буква есть в слове, но в другом месте\n⬜ буквы нет в слове\n"
+	WordleNextGuessFormat   = "\nПопытка %d/%d — напишите слово сообщением."
+	WordleSolvedFormat      = "\n🎉 Угадано с попытки №%d! Новое слово — завтра."
+	WordleLostFormat        = "\nПопытки кончились. Слово дня: <b>%s</b>. Новое слово — завтра."
+	WordleLengthFormat      = "В слове должно быть %d букв — двойные буквы вроде кх и аь считаются одной."
+	WordleUnknownWordFormat = "Слова <b>%s</b> нет в словаре бота. Попытка не засчитана — попробуйте другое."
+	WordleCardButtonFormat  = "📖 Что значит «%s»"
+	WordleShareQuery        = "#вордл"
+	WordleShareFormat       = "🟩 Чеченский вордл %s: %s\n%s"
+	WordleInlineTitleFormat = "🟩 Мой вордл: %s"
+	WordleInlinePlayText    = "🟩 Сначала сыграйте в вордл"
+	WordlePlayButtonText    = "🟩 Сыграть"
+	WordlePrivateOnlyText   = "🟩 Догадки пишутся сообщениями, поэтому /wordle работает только в личных сообщениях с ботом."
+	WordleErrorText         = "Не удалось загадать слово. Попробуйте /wordle ещё раз."
+
+	// Saved words: «⭐ Сохранить» under cards, and /mywords.
+	SaveWordButtonText     = "⭐ Сохранить"
+	SaveWordDoneFormat     = "⭐ «%s» сохранено. Все ваши слова — /mywords"
+	SaveWordAlreadyFormat  = "«%s» уже в ваших словах: /mywords"
+	SaveWordFailedToast    = "Не удалось сохранить слово. Попробуйте ещё раз."
+	MyWordsPageSize        = 10
+	MyWordsHeaderFormat    = "⭐ <b>Мои слова</b> · %d\n\n"
+	MyWordsEmptyText       = "⭐ Здесь будут слова, которые вы сохраните кнопкой «⭐ Сохранить» под карточкой слова — переводом, /random или словом дня."
+	MyWordsQuizButtonText  = "🧠 Викторина"
+	MyWordsLearnButtonText = "📚 Учить"
+	MyWordsDeletedToast    = "🗑 Удалено"
+	MyWordsPrivateOnlyText = "⭐ Ваши слова — личные: напишите боту /mywords в личные сообщения."
+	LearnSavedDoneText     = "📚 По вашим словам на сегодня всё: повторять нечего, а новые слова на сегодня закончились или все сохранённые уже в колоде. Сохраняйте новые кнопкой «⭐ Сохранить» или учите слова из словаря: /learn"
+
+	// /export: saved words as a file for Anki.
+	ExportMaxWords        = 1000
+	ExportLookupLimit     = 50 // words whose history entry, example and grammar cost a lookup
+	ExportCooldown        = 5 * time.Minute
+	ExportDeckName        = "Чеченский · chetoru"
+	ExportCaptionFormat   = "📤 Слов в файле: %d. В Anki: «Файл → Импорт»."
+	ExportEmptyText       = "📤 Выгружать пока нечего: сохраните слова кнопкой «⭐ Сохранить» под карточкой слова или поищите чеченские слова, и /export соберёт из них колоду для Anki."
+	ExportUsageText       = "📤 /export — колода Anki (.apkg) из ваших слов\n/export csv — таблица CSV\n/export tsv — таблица с табуляцией"
+	ExportPrivateOnlyText = "📤 Ваши слова — личные: напишите боту /export в личные сообщения."
+	ExportBusyText        = "📤 Файл уже собирается — он придёт через минуту."
+	ExportCooldownFormat  = "📤 Файл недавно выгружался. Следующий можно будет получить через %d мин."
+
+	// /history: the user's recent lookups.
+	HistoryListSize        = 20
+	HistoryRetentionDays   = 90
+	HistoryHeaderText      = "🕘 <b>Недавние запросы</b>\n\n"
+	HistoryMissLabel       = "не найдено"
+	HistoryFooterFormat    = "\nНажмите на слово, чтобы открыть его карточку. Запросы хранятся %d дней; /history off — не сохранять их, /history clear — стереть."
+	HistoryEmptyText       = "🕘 Здесь появятся слова, которые вы искали в боте."
+	HistoryIsOffText       = "🕘 История запросов выключена: бот не сохраняет, что вы ищете. Включить — /history on"
+	HistoryOffText         = "🕘 История выключена, сохранённые запросы стёрты. Включить снова — /history on"
+	HistoryOnText          = "🕘 История включена: /history покажет, что вы искали."
+	HistoryClearedText     = "🕘 История запросов стёрта."
+	HistoryUsageText       = "🕘 /history — недавние запросы\n/history clear — стереть историю\n/history off — не сохранять запросы\n/history on — сохранять снова"
+	HistoryPrivateOnlyText = "🕘 История запросов — личная: напишите боту /history в личные сообщения."
 )
 
 type AI interface {
@@ -89,13 +291,24 @@ type AI interface {
 type Business interface {
 	Translate(word string) ([]models.TranslationPairs, error)
 	SuggestTranslations(word string) []models.TranslationPairs
+	FindInExamples(word string) []models.TranslationPairs
+	Gloss(text string) models.Gloss
+	DidYouMean(word string) []tools.FuzzyMatch
 	SetAIFormatting(enabled bool)
 	AIFormattingEnabled() bool
+	SetOfflineMode(enabled bool)
+	OfflineMode() bool
 	RandomWordFromAPI(ctx context.Context) (*models.RandomWord, error)
-	GenerateQuiz(ctx context.Context) (*models.QuizQuestion, error)
+	GenerateQuiz(ctx context.Context, filter models.QuizFilter) (*models.QuizQuestion, error)
+	LearnQuiz(ctx context.Context, word models.RandomWord, reversed bool) (*models.QuizQuestion, error)
+	DailyChallenge(ctx context.Context, day string) ([]models.QuizQuestion, error)
+	WordleWord(ctx context.Context, day string) (string, error)
+	IsWordleWord(ctx context.Context, word string) bool
 	GrammarFor(ctx context.Context, word string) (*models.WordGrammar, error)
 	TranslationCacheStats() (hits, misses int64)
+	DoshamStats() models.DoshamStats
 	RecheckTranslation(word string) bool
+	CardWord(word string) (*models.RandomWord, error)
 }
 
 // Repository is the persistence boundary the handlers depend on. It is composed
@@ -110,6 +323,12 @@ type Repository interface {
 	SpellcheckStore
 	SubscriptionStore
 	QuizStore
+	ReviewStore
+	SavedWordStore
+	HistoryStore
+	DailyStore
+	DuelStore
+	WordleStore
 	WordOfDayStore
 }
 
@@ -172,12 +391,92 @@ type SubscriptionStore interface {
 // QuizStore records /quiz answers and the leaderboard behind /top.
 type QuizStore interface {
 	RecordQuizAnswer(ctx context.Context, userID int64, username, firstName string, correct bool) error
+	LogQuizAnswer(ctx context.Context, a models.QuizAnswer) error
+	HardestQuizWords(ctx context.Context, minAttempts, limit int) ([]models.WordDifficulty, error)
+	WeakestQuizWords(ctx context.Context, userID int64, limit int) ([]models.WordDifficulty, error)
 	GetQuizScore(ctx context.Context, userID int64) (correct, total, streak int, err error)
 	TopQuizScorers(ctx context.Context, limit int) ([]models.QuizScorer, error)
 	GetQuizRank(ctx context.Context, userID int64) (int, error)
 	CountQuizStats(ctx context.Context) (players, totalAnswers, correctAnswers int, err error)
 	ListLapsingStreaks(ctx context.Context, lastAnswerDate string) ([]models.QuizScorer, error)
+	RecordChatQuizAnswer(ctx context.Context, chatID, userID int64, username, firstName string, correct bool) error
+	TopChatQuizScorers(ctx context.Context, chatID int64, limit int) ([]models.QuizScorer, error)
+	GetChatQuizRank(ctx context.Context, chatID, userID int64) (rank, correct, total int, err error)
+	TopQuizScorersBetween(ctx context.Context, from, to time.Time, limit int) ([]models.QuizScorer, error)
+	GetQuizRankBetween(ctx context.Context, userID int64, from, to time.Time) (rank, correct, total int, err error)
+	CloseQuizSeason(ctx context.Context, season models.QuizSeason, players int, winners []models.QuizScorer) (bool, error)
+	ListSeasonWinners(ctx context.Context, period string, seasons int) ([]models.SeasonWinner, error)
 	CountActiveStreaks(ctx context.Context) (int, error)
+
+	SaveQuizQuestion(ctx context.Context, q models.QuizQuestion, chatID int64, chatType string, ttl time.Duration) (string, error)
+	AttachQuizPoll(ctx context.Context, id, pollID string) error
+	GetQuizQuestion(ctx context.Context, id string) (*models.StoredQuiz, error)
+	GetQuizQuestionByPoll(ctx context.Context, pollID string) (*models.StoredQuiz, error)
+	ClaimQuizAnswer(ctx context.Context, questionID string, userID int64) (bool, error)
+	PruneQuizQuestions(ctx context.Context, now time.Time) (int64, error)
+}
+
+// ReviewStore keeps each user's /learn deck and its review schedule.
+type ReviewStore interface {
+	AddWordReview(ctx context.Context, review models.WordReview, introducedOn string) (int64, bool, error)
+	HasWordReview(ctx context.Context, userID int64, headwordClean string) (bool, error)
+	NextDueWordReview(ctx context.Context, userID int64, now time.Time) (*models.WordReview, error)
+	GetWordReview(ctx context.Context, userID, id int64) (*models.WordReview, error)
+	UpdateWordReview(ctx context.Context, review models.WordReview, now time.Time) (bool, error)
+	CountNewWordReviews(ctx context.Context, userID int64, day string) (int, error)
+	CountDueWordReviews(ctx context.Context, userID int64, now time.Time) (due, total int, err error)
+}
+
+// SavedWordStore keeps the words users saved from cards for /mywords.
+type SavedWordStore interface {
+	SaveWord(ctx context.Context, w models.SavedWord) (bool, error)
+	ListSavedWords(ctx context.Context, userID int64, offset, limit int) ([]models.SavedWord, int, error)
+	DeleteSavedWord(ctx context.Context, userID, id int64) (bool, error)
+	RandomSavedWord(ctx context.Context, userID int64) (*models.SavedWord, error)
+	NextUnlearnedSavedWord(ctx context.Context, userID int64) (*models.SavedWord, error)
+	NextDueSavedWordReview(ctx context.Context, userID int64, now time.Time) (*models.WordReview, error)
+}
+
+// HistoryStore keeps what users looked up, for /history and /export.
+type HistoryStore interface {
+	RecordLookupActivity(ctx context.Context, userID int64, username string, activityType models.ActivityType, lookup models.Lookup) error
+	ListLookups(ctx context.Context, userID int64, limit int) ([]models.Lookup, error)
+	ClearLookups(ctx context.Context, userID int64) (int64, error)
+	SetHistoryOff(ctx context.Context, userID int64, off bool) error
+	IsHistoryOff(ctx context.Context, userID int64) (bool, error)
+	PruneLookups(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// DailyStore keeps each day's /daily challenge and everyone's attempt at it.
+type DailyStore interface {
+	GetDailyChallenge(ctx context.Context, day string) ([]models.QuizQuestion, error)
+	SaveDailyChallenge(ctx context.Context, day string, questions []models.QuizQuestion) error
+	StartDailyAttempt(ctx context.Context, day string, userID int64, username, firstName string) (*models.DailyAttempt, error)
+	GetDailyAttempt(ctx context.Context, day string, userID int64) (*models.DailyAttempt, error)
+	RecordDailyAnswer(ctx context.Context, day string, userID int64, index int, correct bool, questions int) (bool, error)
+	TopDailyScorers(ctx context.Context, day string, limit int) ([]models.DailyAttempt, error)
+	GetDailyRank(ctx context.Context, day string, userID int64) (rank, players int, err error)
+}
+
+// DuelStore keeps duels, their players' progress and the duel rating.
+type DuelStore interface {
+	CreateDuel(ctx context.Context, challengerID int64, username, firstName string) (string, error)
+	GetDuel(ctx context.Context, id string) (*models.Duel, error)
+	AcceptDuel(ctx context.Context, id string, opponentID int64, username, firstName, inlineMessageID string, questions []models.QuizQuestion) (bool, error)
+	RecordDuelAnswer(ctx context.Context, id string, userID int64, index int, correct bool, questions int) (bool, error)
+	SettleDuel(ctx context.Context, id string) (*models.DuelSettlement, error)
+	TopDuelRatings(ctx context.Context, limit int) ([]models.DuelRating, error)
+	GetDuelRating(ctx context.Context, userID int64) (*models.DuelRating, int, error)
+	PruneDuels(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// WordleStore keeps each day's /wordle word and everyone's game of it.
+type WordleStore interface {
+	GetWordleWord(ctx context.Context, day string) (string, error)
+	SaveWordleWord(ctx context.Context, day, word string) error
+	StartWordleGame(ctx context.Context, day string, userID int64, username, firstName string) (*models.WordleGame, error)
+	GetWordleGame(ctx context.Context, day string, userID int64) (*models.WordleGame, error)
+	AddWordleGuess(ctx context.Context, day string, userID int64, index int, guess string, solved bool, maxGuesses int) (bool, error)
 }
 
 // WordOfDayStore manages opt-in subscriptions for the daily "Word of the Day".
@@ -209,6 +508,30 @@ type Net struct {
 	inlineSpellMu     sync.Mutex
 	inlineSpellLatest map[int64]string
 
+	// writePending holds each chat's /write question until its answer.
+	writeMu      sync.Mutex
+	writePending map[int64]pendingWrite
+
+	// wordleActive is when each chat was last shown an unfinished /wordle
+	// board, for wordleGuessFor.
+	wordleMu     sync.Mutex
+	wordleActive map[int64]time.Time
+
+	// exportRunning marks users whose /export is being built, exportDone when
+	// each one's last finished, for the cooldown.
+	exportMu      sync.Mutex
+	exportRunning map[int64]bool
+	exportDone    map[int64]time.Time
+
+	// tournaments holds the /tournament running in each group chat;
+	// tournamentsStopped is set once shutdown has cancelled them.
+	tournamentMu       sync.Mutex
+	tournaments        map[int64]*tournament
+	tournamentsStopped bool
+
+	// pronunciation adds a transcription line to word cards (see SetPronunciation).
+	pronunciation bool
+
 	// bg tracks detached post-reply work (donation nudge, cache invalidation,
 	// missing-word records) so shutdown can wait for it.
 	bg sync.WaitGroup
@@ -228,6 +551,11 @@ func NewNet(log *logrus.Logger, repo Repository, bot *tgbotapi.BotAPI, business
 		ai:                aiClient,
 		cache:             cache,
 		inlineSpellLatest: make(map[int64]string),
+		writePending:      make(map[int64]pendingWrite),
+		wordleActive:      make(map[int64]time.Time),
+		tournaments:       make(map[int64]*tournament),
+		exportRunning:     make(map[int64]bool),
+		exportDone:        make(map[int64]time.Time),
 	}
 }
 
@@ -283,6 +611,7 @@ func (n *Net) Start(ctx context.Context) {
 			// Let in-flight handlers finish before main closes the DB: holding
 			// every semaphore slot means none are still running.
 			n.log.Info("shutting down, draining in-flight handlers")
+			n.stopTournaments()
 			for range maxConcurrentUpdates {
 				sem <- struct{}{}
 			}
@@ -302,11 +631,14 @@ func (n *Net) Start(ctx context.Context) {
 			continue
 		}
 
-		// Poll answers (group quiz scoring) — cheap DB write, kept in-loop.
+		// Poll answers (group quiz scoring)
 		if update.PollAnswer != nil {
-			if err := n.HandlePollAnswer(handlerCtx, update.PollAnswer); err != nil {
-				n.log.WithError(err).Error("service.HandlePollAnswer")
-			}
+			pa := update.PollAnswer
+			dispatch("poll_answer", func() {
+				if err := n.HandlePollAnswer(handlerCtx, pa); err != nil {
+					n.log.WithError(err).Error("service.HandlePollAnswer")
+				}
+			})
 			continue
 		}
 
@@ -349,10 +681,20 @@ func (n *Net) registerBotCommands() {
 	cmds := tgbotapi.NewSetMyCommands(
 		tgbotapi.BotCommand{Command: "random", Description: "🎲 Случайное чеченское слово"},
 		tgbotapi.BotCommand{Command: "quiz", Description: "🧠 Викторина по чеченскому"},
+		tgbotapi.BotCommand{Command: "learn", Description: "📚 Учить слова"},
+		tgbotapi.BotCommand{Command: "mywords", Description: "⭐ Мои слова"},
+		tgbotapi.BotCommand{Command: "export", Description: "📤 Мои слова для Anki"},
+		tgbotapi.BotCommand{Command: "history", Description: "🕘 Недавние запросы"},
+		tgbotapi.BotCommand{Command: "write", Description: "✍️ Написать слово по-чеченски"},
+		tgbotapi.BotCommand{Command: "daily", Description: "📅 Вызов дня"},
+		tgbotapi.BotCommand{Command: "duel", Description: "⚔️ Дуэли"},
+		tgbotapi.BotCommand{Command: "wordle", Description: "🟩 Угадай слово"},
+		tgbotapi.BotCommand{Command: "tournament", Description: "🏟 Турнир в группе"},
 		tgbotapi.BotCommand{Command: "top", Description: "🏆 Рейтинг знатоков"},
 		tgbotapi.BotCommand{Command: "me", Description: "👤 Мой прогресс"},
 		tgbotapi.BotCommand{Command: "wotd", Description: "📖 Слово дня"},
 		tgbotapi.BotCommand{Command: "check", Description: "✍️ Проверить орфографию"},
+		tgbotapi.BotCommand{Command: "gloss", Description: "🔤 Разобрать текст по словам"},
 		tgbotapi.BotCommand{Command: "subscribe", Description: "⭐ Подписка на безлимит"},
 	)
 	if _, err := n.bot.Request(cmds); err != nil {
@@ -369,10 +711,20 @@ func (n *Net) routeCallback(ctx context.Context, cq *tgbotapi.CallbackQuery) {
 		err = n.HandleBroadcastCallback(ctx, cq)
 	case strings.HasPrefix(data, "more_"):
 		err = n.HandleMoreTranslations(ctx, cq)
+	case strings.HasPrefix(data, "card_"):
+		err = n.HandleCardCallback(ctx, cq)
+	case strings.HasPrefix(data, "save_"):
+		err = n.HandleSaveWordCallback(ctx, cq)
+	case strings.HasPrefix(data, "mywords_"):
+		err = n.HandleMyWordsCallback(ctx, cq)
 	case strings.HasPrefix(data, "random_"):
 		err = n.HandleRandomCallback(ctx, cq)
 	case strings.HasPrefix(data, "quiz_"):
 		err = n.HandleQuizCallback(ctx, cq)
+	case strings.HasPrefix(data, "daily_"):
+		err = n.HandleDailyCallback(ctx, cq)
+	case strings.HasPrefix(data, "duel_"):
+		err = n.HandleDuelCallback(ctx, cq)
 	case strings.HasPrefix(data, "wotd_"):
 		err = n.HandleWordOfDayCallback(ctx, cq)
 	case strings.HasPrefix(data, "check_"):
@@ -393,17 +745,48 @@ func (n *Net) routeMessage(ctx context.Context, m *tgbotapi.Message) {
 
 	switch m.Command() {
 	case "start":
-		err = n.HandleStart(m)
+		// Shared daily and wordle results and duel cards link here with a
+		// payload.
+		payload := m.CommandArguments()
+		if id, ok := strings.CutPrefix(payload, duelStartPrefix); ok {
+			err = n.HandleDuelStart(ctx, m, id)
+		} else if payload == dailyStartParameter {
+			err = n.HandleDaily(ctx, m)
+		} else if payload == wordleStartParameter {
+			err = n.HandleWordle(ctx, m)
+		} else {
+			err = n.HandleStart(m)
+		}
 	case "stats":
 		err = n.HandleStats(ctx, m)
 	case "missing":
 		err = n.HandleMissingWords(ctx, m)
+	case "hardest":
+		err = n.HandleHardestWords(ctx, m)
 	case "random":
 		err = n.HandleRandom(ctx, m.Chat.ID)
 	case "quiz":
-		err = n.HandleQuiz(ctx, m.Chat)
+		err = n.HandleQuizCommand(ctx, m)
+	case "learn":
+		err = n.HandleLearn(ctx, m)
+	case "mywords":
+		err = n.HandleMyWords(ctx, m)
+	case "export":
+		err = n.HandleExport(ctx, m)
+	case "history":
+		err = n.HandleHistory(ctx, m)
+	case "write":
+		err = n.HandleWrite(ctx, m)
+	case "daily":
+		err = n.HandleDaily(ctx, m)
+	case "duel":
+		err = n.HandleDuel(ctx, m)
+	case "wordle":
+		err = n.HandleWordle(ctx, m)
+	case "tournament":
+		err = n.HandleTournament(ctx, m)
 	case "top":
-		err = n.HandleTop(ctx, m.Chat.ID, m.From.ID)
+		err = n.HandleTopCommand(ctx, m)
 	case "me":
 		err = n.HandleMe(ctx, m)
 	case "wotd":
@@ -412,11 +795,16 @@ func (n *Net) routeMessage(ctx context.Context, m *tgbotapi.Message) {
 		err = n.HandleModerate(ctx, m)
 	case "check":
 		err = n.HandleCheck(ctx, m)
+	case "gloss":
+		err = n.HandleGloss(ctx, m)
 	case "subscribe":
 		err = n.HandleSubscribe(ctx, m)
 	case "ai":
 		n.HandleAIToggle(m)
 		return
+	case "offline":
+		n.HandleOfflineToggle(m)
+		return
 	case "broadcast":
 		err = n.HandleBroadcast(ctx, m)
 	case "broadcast_cancel":
@@ -436,6 +824,10 @@ func (n *Net) routeMessage(ctx context.Context, m *tgbotapi.Message) {
 
 		if n.isAwaitingBroadcastContent(m) {
 			err = n.HandleBroadcastContent(m)
+		} else if p, ok := n.writeAnswerFor(m); ok {
+			err = n.HandleWriteAnswer(ctx, m, p)
+		} else if n.wordleGuessFor(m) {
+			err = n.HandleWordleGuess(ctx, m, m.Text)
 		} else {
 			err = n.HandleText(ctx, m)
 		}
@@ -455,6 +847,12 @@ func (n *Net) routeInline(ctx context.Context, iq *tgbotapi.InlineQuery) {
 
 	if strings.HasPrefix(iq.Query, ". ") && len(iq.Query) > 2 {
 		err = n.HandleInlineSpellcheck(ctx, iq)
+	} else if strings.TrimSpace(iq.Query) == DailyShareQuery {
+		err = n.HandleInlineDaily(ctx, iq)
+	} else if strings.TrimSpace(iq.Query) == WordleShareQuery {
+		err = n.HandleInlineWordle(ctx, iq)
+	} else if isDuelQuery(iq.Query) {
+		err = n.HandleInlineDuel(ctx, iq)
 	} else {
 		err = n.HandleInline(ctx, iq)
 	}
@@ -492,6 +890,28 @@ func (n *Net) HandleAIToggle(msg *tgbotapi.Message) {
 	}
 }
 
+// HandleOfflineToggle switches lookups to the local mirror and back. Meant for
+// a dosham outage long enough that even the breaker's probes are unwelcome.
+func (n *Net) HandleOfflineToggle(msg *tgbotapi.Message) {
+	if !n.isAdmin(msg.From.ID) {
+		return
+	}
+	switch strings.TrimSpace(msg.CommandArguments()) {
+	case "on":
+		n.business.SetOfflineMode(true)
+		n.send(tgbotapi.NewMessage(msg.Chat.ID, "Offline mode: ON"))
+	case "off":
+		n.business.SetOfflineMode(false)
+		n.send(tgbotapi.NewMessage(msg.Chat.ID, "Offline mode: OFF"))
+	default:
+		status := "OFF"
+		if n.business.OfflineMode() {
+			status = "ON"
+		}
+		n.send(tgbotapi.NewMessage(msg.Chat.ID, "Offline mode: "+status+"\n/offline on | /offline off"))
+	}
+}
+
 func (n *Net) isBlockedError(err error) bool {
 	if err == nil {
 		return false
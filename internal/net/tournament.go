@@ -0,0 +1,312 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Why a tournament stopped early, as its context's cause.
+var (
+	errTournamentCancelled = errors.New("tournament cancelled by a chat admin")
+	errTournamentShutdown  = errors.New("bot shutting down")
+	errTournamentRunning   = errors.New("a tournament is already running in the chat")
+)
+
+// tournamentStopWords are the /tournament arguments that stop the running one.
+var tournamentStopWords = map[string]bool{"стоп": true, "stop": true, "отмена": true, "cancel": true}
+
+// tournament is a /tournament running in a group: a fixed number of timed
+// quiz polls in a row, scored among the chat's players. Its standings live
+// in memory only — a restart ends the tournament anyway — while every answer
+// also goes to the chat's board and the global one like any poll answer.
+type tournament struct {
+	chatID int64
+	rounds int
+	cancel context.CancelCauseFunc
+
+	mu         sync.Mutex
+	questionID string // the round being played
+	scores     map[int64]*tournamentScore
+}
+
+type tournamentScore struct {
+	scorer models.QuizScorer
+	order  int // when the player first answered, to break ties
+}
+
+// credit counts an answer to question toward the standings, if question is
+// the round being played.
+func (t *tournament) credit(question string, u *tgbotapi.User, correct bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if question != t.questionID {
+		return
+	}
+	s, ok := t.scores[u.ID]
+	if !ok {
+		s = &tournamentScore{scorer: models.QuizScorer{UserID: u.ID}, order: len(t.scores)}
+		t.scores[u.ID] = s
+	}
+	s.scorer.Username, s.scorer.FirstName = u.UserName, u.FirstName
+	s.scorer.Total++
+	if correct {
+		s.scorer.Correct++
+	}
+}
+
+func (t *tournament) setQuestion(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.questionID = id
+}
+
+// standings ranks the players by correct answers; a tie goes to whoever
+// joined the tournament first, so the order never shuffles between rounds.
+func (t *tournament) standings() []models.QuizScorer {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	scores := make([]*tournamentScore, 0, len(t.scores))
+	for _, s := range t.scores {
+		scores = append(scores, s)
+	}
+	slices.SortFunc(scores, func(a, b *tournamentScore) int {
+		if a.scorer.Correct != b.scorer.Correct {
+			return b.scorer.Correct - a.scorer.Correct
+		}
+		return a.order - b.order
+	})
+	out := make([]models.QuizScorer, len(scores))
+	for i, s := range scores {
+		out[i] = s.scorer
+	}
+	return out
+}
+
+// renderStandings formats tournament standings, the top
+// TournamentStandingsLimit of them.
+func renderStandings(scorers []models.QuizScorer) string {
+	if len(scorers) == 0 {
+		return TournamentNoAnswersText
+	}
+	var b strings.Builder
+	for i, s := range scorers[:min(len(scorers), TournamentStandingsLimit)] {
+		fmt.Fprintf(&b, "%s <b>%s</b> — %d/%d\n", quizMedal(i), tgbotapi.EscapeText(tgbotapi.ModeHTML, scorerName(s)), s.Correct, s.Total)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// parseTournamentRounds reads the /tournament round count: the default when
+// none is given, ok=false when it is not a number in range.
+func parseTournamentRounds(args string) (rounds int, ok bool) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return TournamentDefaultRounds, true
+	}
+	rounds, err := strconv.Atoi(args)
+	if err != nil || rounds < 1 || rounds > TournamentMaxRounds {
+		return 0, false
+	}
+	return rounds, true
+}
+
+// HandleTournament serves /tournament: «/tournament N» starts N timed rounds in
+// a group, «/tournament стоп» lets a chat admin end the running one. Polls are
+// what makes a group quiz fair — everyone answers on their own — so there is
+// no tournament in a private chat.
+func (n *Net) HandleTournament(ctx context.Context, m *tgbotapi.Message) error {
+	if !isGroup(m.Chat) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, TournamentGroupOnlyText))
+		return err
+	}
+	args := strings.ToLower(strings.TrimSpace(m.CommandArguments()))
+	if tournamentStopWords[args] {
+		return n.cancelTournament(m)
+	}
+	rounds, ok := parseTournamentRounds(args)
+	if !ok {
+		msg := tgbotapi.NewMessage(m.Chat.ID, TournamentUsageText)
+		msg.ParseMode = "html"
+		_, err := n.send(msg)
+		return err
+	}
+
+	tctx, cancel := context.WithCancelCause(ctx)
+	t := &tournament{chatID: m.Chat.ID, rounds: rounds, cancel: cancel, scores: make(map[int64]*tournamentScore)}
+	if err := n.registerTournament(t); err != nil {
+		cancel(nil)
+		text := TournamentRunningText
+		if errors.Is(err, errTournamentShutdown) {
+			text = TournamentShutdownText
+		}
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, text))
+		return err
+	}
+	msg := tgbotapi.NewMessage(m.Chat.ID, fmt.Sprintf(TournamentStartFormat, rounds, int(TournamentRoundTime/time.Second)))
+	msg.ParseMode = "html"
+	if _, err := n.send(msg); err != nil {
+		n.endTournament(t)
+		cancel(nil)
+		return err
+	}
+
+	// The rounds outlive this update's handler, so they run detached,
+	// tracked for shutdown like the rest of the background work.
+	n.bg.Go(func() {
+		defer cancel(nil)
+		n.runTournament(tctx, m.Chat, t)
+	})
+	return nil
+}
+
+// runTournament plays t's rounds: one quiz poll each, closed by Telegram
+// after TournamentRoundTime, then the answer and the standings so far. The
+// last round's results are the final standings.
+func (n *Net) runTournament(ctx context.Context, chat *tgbotapi.Chat, t *tournament) {
+	defer n.endTournament(t)
+
+	for round := 1; round <= t.rounds; round++ {
+		q, err := n.business.GenerateQuiz(ctx, models.QuizFilter{})
+		if err != nil {
+			n.log.WithError(err).WithField("chat_id", chat.ID).Warn("tournament: GenerateQuiz failed")
+			n.sendStandings(chat.ID, TournamentErrorText, t)
+			return
+		}
+		poll := tgbotapi.NewPoll(chat.ID, fmt.Sprintf(TournamentPollFormat, round, t.rounds, quizPollQuestion(q)), q.Options...)
+		poll.OpenPeriod = int(TournamentRoundTime / time.Second)
+		id, sent, err := n.postQuizPoll(ctx, chat, q, poll)
+		if err != nil {
+			n.log.WithError(err).WithField("chat_id", chat.ID).Warn("tournament: poll not sent")
+			n.sendStandings(chat.ID, TournamentErrorText, t)
+			return
+		}
+		t.setQuestion(id)
+
+		timer := time.NewTimer(TournamentRoundTime + TournamentAnswerGrace)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if _, err := n.bot.Request(tgbotapi.NewStopPoll(chat.ID, sent.MessageID)); err != nil {
+				n.log.WithError(err).WithField("chat_id", chat.ID).Warn("tournament: failed to close the poll")
+			}
+			header := TournamentCancelledText
+			if errors.Is(context.Cause(ctx), errTournamentShutdown) {
+				header = TournamentInterruptedText
+			}
+			n.sendStandings(chat.ID, header, t)
+			return
+		case <-timer.C:
+		}
+
+		answer := tgbotapi.EscapeText(tgbotapi.ModeHTML, q.Options[q.CorrectIdx])
+		header := fmt.Sprintf(TournamentRoundResultFormat, round, t.rounds, answer)
+		if round == t.rounds {
+			header = fmt.Sprintf(TournamentFinalFormat, answer)
+		}
+		n.sendStandings(chat.ID, header, t)
+	}
+}
+
+func (n *Net) sendStandings(chatID int64, header string, t *tournament) {
+	msg := tgbotapi.NewMessage(chatID, header+renderStandings(t.standings()))
+	msg.ParseMode = "html"
+	if _, err := n.send(msg); err != nil {
+		n.log.WithError(err).WithField("chat_id", chatID).Warn("tournament: standings not sent")
+	}
+}
+
+// creditTournament counts a poll answer toward the tournament running in the
+// poll's chat, if the poll is its current round.
+func (n *Net) creditTournament(q *models.StoredQuiz, u *tgbotapi.User, correct bool) {
+	n.tournamentMu.Lock()
+	t := n.tournaments[q.ChatID]
+	n.tournamentMu.Unlock()
+	if t != nil {
+		t.credit(q.ID, u, correct)
+	}
+}
+
+// cancelTournament ends the chat's tournament at a chat admin's request. The
+// tournament itself closes the open poll and posts the standings.
+func (n *Net) cancelTournament(m *tgbotapi.Message) error {
+	n.tournamentMu.Lock()
+	t := n.tournaments[m.Chat.ID]
+	n.tournamentMu.Unlock()
+	if t == nil {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, TournamentNotRunningText))
+		return err
+	}
+	if !n.isChatAdmin(m) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, TournamentAdminOnlyText))
+		return err
+	}
+	t.cancel(errTournamentCancelled)
+	return nil
+}
+
+// isChatAdmin reports whether m was sent by an admin of its chat: one posting
+// anonymously as the group itself, a member Telegram lists as creator or
+// administrator, or a bot admin.
+func (n *Net) isChatAdmin(m *tgbotapi.Message) bool {
+	if m.SenderChat != nil && m.SenderChat.ID == m.Chat.ID {
+		return true
+	}
+	if m.From == nil {
+		return false
+	}
+	if n.isAdmin(m.From.ID) {
+		return true
+	}
+	member, err := n.bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: m.Chat.ID, UserID: m.From.ID},
+	})
+	if err != nil {
+		n.log.WithError(err).WithField("chat_id", m.Chat.ID).Warn("GetChatMember failed")
+		return false
+	}
+	return member.IsCreator() || member.IsAdministrator()
+}
+
+// registerTournament makes t its chat's running tournament, unless one is
+// already running there or the bot is shutting down: stopTournaments has
+// already cancelled the ones it knew of, and a new one would hold up the
+// drain for all its rounds.
+func (n *Net) registerTournament(t *tournament) error {
+	n.tournamentMu.Lock()
+	defer n.tournamentMu.Unlock()
+	if n.tournamentsStopped {
+		return errTournamentShutdown
+	}
+	if _, running := n.tournaments[t.chatID]; running {
+		return errTournamentRunning
+	}
+	n.tournaments[t.chatID] = t
+	return nil
+}
+
+func (n *Net) endTournament(t *tournament) {
+	n.tournamentMu.Lock()
+	defer n.tournamentMu.Unlock()
+	if n.tournaments[t.chatID] == t {
+		delete(n.tournaments, t.chatID)
+	}
+}
+
+// stopTournaments ends every running tournament for shutdown and refuses new
+// ones from the handlers still draining.
+func (n *Net) stopTournaments() {
+	n.tournamentMu.Lock()
+	defer n.tournamentMu.Unlock()
+	n.tournamentsStopped = true
+	for _, t := range n.tournaments {
+		t.cancel(errTournamentShutdown)
+	}
+}
@@ -0,0 +1,100 @@
+package net
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestParseTournamentRounds(t *testing.T) {
+	cases := []struct {
+		args   string
+		rounds int
+		ok     bool
+	}{
+		{"", TournamentDefaultRounds, true},
+		{"10", 10, true},
+		{" 1 ", 1, true},
+		{"0", 0, false},
+		{"21", 0, false},
+		{"десять", 0, false},
+	}
+	for _, c := range cases {
+		if rounds, ok := parseTournamentRounds(c.args); rounds != c.rounds || ok != c.ok {
+			t.Errorf("parseTournamentRounds(%q) = %d, %v; want %d, %v", c.args, rounds, ok, c.rounds, c.ok)
+		}
+	}
+}
+
+// Only answers to the round being played count: a vote on an earlier round's
+// poll, still open in a slow client, must not move the standings.
+func TestTournament_CreditsOnlyTheCurrentRound(t *testing.T) {
+	tm := &tournament{scores: make(map[int64]*tournamentScore)}
+	ali := &tgbotapi.User{ID: 1, UserName: "ali"}
+	zara := &tgbotapi.User{ID: 2, FirstName: "Зара"}
+	musa := &tgbotapi.User{ID: 3, UserName: "musa"}
+
+	tm.setQuestion("q1")
+	tm.credit("q1", ali, false)
+	tm.credit("q1", zara, true)
+	tm.credit("q1", musa, true)
+	tm.setQuestion("q2")
+	tm.credit("q1", ali, true) // late
+	tm.credit("q2", ali, true)
+	tm.credit("q2", zara, true)
+	tm.credit("q2", musa, false)
+
+	got := tm.standings()
+	if len(got) != 3 || got[0].UserID != 2 || got[1].UserID != 1 || got[2].UserID != 3 {
+		t.Fatalf("standings = %+v, want Зара, then ali and musa tied in the order they joined", got)
+	}
+	if got[0].Correct != 2 || got[0].Total != 2 || got[1].Correct != 1 || got[1].Total != 2 {
+		t.Fatalf("standings = %+v, want 2/2 and 1/2", got)
+	}
+
+	board := renderStandings(got)
+	if !strings.HasPrefix(board, "🥇 <b>Зара</b> — 2/2") || strings.Count(board, "\n") != 2 {
+		t.Fatalf("renderStandings = %q", board)
+	}
+	if renderStandings(nil) != TournamentNoAnswersText {
+		t.Fatal("an empty tournament must say nobody answered")
+	}
+}
+
+func TestRegisterTournament_OnePerChat(t *testing.T) {
+	n := &Net{tournaments: make(map[int64]*tournament)}
+	first := &tournament{chatID: -1}
+	if err := n.registerTournament(first); err != nil {
+		t.Fatalf("the first tournament must start: %v", err)
+	}
+	if err := n.registerTournament(&tournament{chatID: -1}); !errors.Is(err, errTournamentRunning) {
+		t.Fatalf("a second tournament in the same chat: err = %v, want errTournamentRunning", err)
+	}
+	if err := n.registerTournament(&tournament{chatID: -2}); err != nil {
+		t.Fatalf("another chat's tournament must start: %v", err)
+	}
+	n.endTournament(first)
+	if err := n.registerTournament(&tournament{chatID: -1}); err != nil {
+		t.Fatalf("a chat must be free for a new tournament once its last one ended: %v", err)
+	}
+}
+
+func TestRegisterTournament_RefusedAfterStop(t *testing.T) {
+	n := &Net{tournaments: make(map[int64]*tournament)}
+	running := &tournament{chatID: -1}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	running.cancel = cancel
+	if err := n.registerTournament(running); err != nil {
+		t.Fatalf("registerTournament: %v", err)
+	}
+	n.stopTournaments()
+	if !errors.Is(context.Cause(ctx), errTournamentShutdown) {
+		t.Fatalf("running tournament cause = %v, want errTournamentShutdown", context.Cause(ctx))
+	}
+	if err := n.registerTournament(&tournament{chatID: -2}); !errors.Is(err, errTournamentShutdown) {
+		t.Fatalf("tournament after stop: err = %v, want errTournamentShutdown", err)
+	}
+}
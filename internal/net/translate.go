@@ -5,6 +5,7 @@ import (
 	"chetoru/pkg/tools"
 	"context"
 	"fmt"
+	"html"
 	"os"
 	"strconv"
 	"strings"
@@ -20,8 +21,11 @@ func (n *Net) HandleText(ctx context.Context, m *tgbotapi.Message) error {
 	go n.send(tgbotapi.NewChatAction(m.Chat.ID, tgbotapi.ChatTyping))
 
 	// Bookkeeping runs after the reply has been sent — storage writes should
-	// never sit between the user and the translation.
-	defer n.recordActivity(ctx, m.From.ID, m.From.UserName, models.ActivityTypeText)
+	// never sit between the user and the translation. A lookup that got an
+	// answer, found or not, goes to the user's /history with it; a failed one
+	// is just activity.
+	var lookup *models.Lookup
+	defer func() { n.recordActivity(ctx, m.From.ID, m.From.UserName, models.ActivityTypeText, lookup) }()
 
 	translations, err := n.business.Translate(m.Text)
 	if err != nil {
@@ -32,6 +36,8 @@ func (n *Net) HandleText(ctx context.Context, m *tgbotapi.Message) error {
 		_, sendErr := n.send(tgbotapi.NewMessage(m.Chat.ID, DictionaryUnavailableText))
 		return sendErr
 	}
+	l := lookupFor(m.Text, translations)
+	lookup = &l
 	if len(translations) == 0 {
 		cleanWord := tools.NormalizeSearch(m.Text)
 		// Whether the gap is worth recording also decides what we tell the
@@ -49,29 +55,42 @@ func (n *Net) HandleText(ctx context.Context, m *tgbotapi.Message) error {
 
 		text := NoTranslationText
 		if suggestions := n.business.SuggestTranslations(m.Text); len(suggestions) > 0 {
-			// Clamped like every other card: three long glosses clear 4096
-			// characters, and Telegram answers an oversized message by sending
-			// nothing — turning a near miss into a blank screen.
-			text = clampMessage(text + "\n\n" + SuggestionsHeaderText + "\n\n" + tools.FormatPairs(suggestions))
+			text += "\n\n" + SuggestionsHeaderText + "\n\n" + tools.FormatPairs(suggestions)
 		}
+		// No entry is about the word, but it may still be used somewhere —
+		// inside a gloss or an example the local index can reach.
+		if found := tools.FormatCard(m.Text, n.business.FindInExamples(m.Text)); found != "" {
+			text += "\n\n" + found
+		}
+		// Clamped like every other card: three long glosses clear 4096
+		// characters, and Telegram answers an oversized message by sending
+		// nothing — turning a near miss into a blank screen.
+		text = clampMessage(text)
 		msg := tgbotapi.NewMessage(m.Chat.ID, text)
 		msg.ParseMode = "html"
 
 		// A miss used to be a dead end. It now says what happened to the word
-		// and offers the one thing that most often explains it — a typo, which
-		// the checker already knows how to find.
+		// and offers the one thing that most often explains it — a typo. The
+		// lexicon's nearest words come first: they are local and free, and the
+		// paid checker is only worth a tap when none of them is the word.
+		var rows [][]tgbotapi.InlineKeyboardButton
 		if recordable {
 			msg.Text += "\n\n" + MissingWordRecordedText
+			if near := n.business.DidYouMean(m.Text); len(near) > 0 {
+				msg.Text += "\n\n" + DidYouMeanText
+				rows = append(rows, didYouMeanRows(near)...)
+			}
 			if n.ai != nil {
 				if data, ok := checkCallbackData(m.Text); ok {
-					msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
-						tgbotapi.NewInlineKeyboardRow(
-							tgbotapi.NewInlineKeyboardButtonData(CheckSpellingButtonText, data),
-						),
-					)
+					rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+						tgbotapi.NewInlineKeyboardButtonData(CheckSpellingButtonText, data),
+					))
 				}
 			}
 		}
+		if len(rows) > 0 {
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+		}
 
 		_, err := n.send(msg)
 		return err
@@ -90,13 +109,23 @@ func (n *Net) HandleText(ctx context.Context, m *tgbotapi.Message) error {
 	// One card holds the whole answer now, so there is no second page to offer:
 	// what «Ещё» used to paginate was the noise dosham's substring search
 	// returns, which the card drops instead of deferring.
-	card := tools.FormatCard(m.Text, translations)
-	if card == "" {
-		card = tools.FormatPairs(translations)
+	card := n.translationCard(m.Text, translations)
+	// Admins see which dictionary each pair came from — the only way to
+	// judge a source before giving it a higher priority.
+	if n.isAdmin(m.From.ID) {
+		if footer := provenanceFooter(translations); footer != "" {
+			card += "\n\n" + footer
+		}
 	}
 	msg := tgbotapi.NewMessage(m.Chat.ID, clampMessage(card))
 	msg.ParseMode = "html"
 
+	if word, ok := translationSaveWord(m.Text, translations); ok {
+		if save, ok := saveButtonRow(word); ok {
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(save)
+		}
+	}
+
 	hintInline := len(translations) > MaxTranslations && n.shouldHintInline(ctx, m.From.ID)
 	if hintInline {
 		msg.Text += "\n\n" + MoreTranslationsHelpText
@@ -240,10 +269,17 @@ func isRecordableMissingWord(cleanWord string) bool {
 	return hasCyrillic
 }
 
-// recordActivity persists per-user bookkeeping for a lookup. Failures are
-// logged, not returned — the reply has already been sent.
-func (n *Net) recordActivity(ctx context.Context, userID int64, username string, activityType models.ActivityType) {
-	if err := n.repo.RecordUserActivity(ctx, userID, username, activityType); err != nil {
+// recordActivity persists per-user bookkeeping for a lookup, with what was
+// looked up when lookup is not nil. Failures are logged, not returned — the
+// reply has already been sent.
+func (n *Net) recordActivity(ctx context.Context, userID int64, username string, activityType models.ActivityType, lookup *models.Lookup) {
+	var err error
+	if lookup != nil {
+		err = n.repo.RecordLookupActivity(ctx, userID, username, activityType, *lookup)
+	} else {
+		err = n.repo.RecordUserActivity(ctx, userID, username, activityType)
+	}
+	if err != nil {
 		n.log.WithError(err).WithField("user_id", userID).Warn("failed to record activity")
 	}
 }
@@ -318,7 +354,9 @@ func (n *Net) HandleInline(ctx context.Context, iq *tgbotapi.InlineQuery) error
 		return fmt.Errorf("answerInline: %w", err)
 	}
 
-	n.recordActivity(ctx, iq.From.ID, iq.From.UserName, models.ActivityTypeInline)
+	// Inline queries stay out of /history: one arrives per keystroke, and the
+	// history would fill with «д», «да», «дат» on the way to «дать».
+	n.recordActivity(ctx, iq.From.ID, iq.From.UserName, models.ActivityTypeInline, nil)
 	return nil
 }
 
@@ -356,6 +394,9 @@ func (n *Net) answerInlineDiscovery(ctx context.Context, iq *tgbotapi.InlineQuer
 			tgbotapi.EscapeText(tgbotapi.ModeHTML, w.Chechen),
 			tgbotapi.EscapeText(tgbotapi.ModeHTML, w.Russian),
 		)
+		if line := n.pronunciationLine(spelling(w)); line != "" {
+			text += "\n" + line
+		}
 		article := tgbotapi.NewInlineQueryResultArticle(iq.ID+strconv.Itoa(i), "🎲 "+w.Chechen, "")
 		article.Description = w.Russian
 		article.InputMessageContent = tgbotapi.InputTextMessageContent{
@@ -408,10 +449,7 @@ func (n *Net) HandleMoreTranslations(ctx context.Context, cq *tgbotapi.CallbackQ
 	// still fires for buttons sitting in older chats, and re-sends that card
 	// rather than a page of the noise the button used to leaf through.
 	_ = offset
-	card := tools.FormatCard(word, translations)
-	if card == "" {
-		card = tools.FormatPairs(translations)
-	}
+	card := n.translationCard(word, translations)
 	msg := tgbotapi.NewMessage(cq.Message.Chat.ID, clampMessage(card))
 	msg.ParseMode = "html"
 
@@ -421,6 +459,129 @@ func (n *Net) HandleMoreTranslations(ctx context.Context, cq *tgbotapi.CallbackQ
 	return nil
 }
 
+// provenanceFooter counts pairs per dictionary source, in the order the
+// sources first appear: "dosham 12 · glossary 2". A pair several sources
+// agreed on counts for each of them.
+func provenanceFooter(translations []models.TranslationPairs) string {
+	var names []string
+	counts := map[string]int{}
+	for _, p := range translations {
+		for _, name := range strings.Split(p.Provenance, ",") {
+			if name == "" {
+				continue
+			}
+			if counts[name] == 0 {
+				names = append(names, name)
+			}
+			counts[name]++
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", html.EscapeString(name), counts[name])
+	}
+	return fmt.Sprintf(ProvenanceFooterFormat, strings.Join(parts, " · "))
+}
+
+// didYouMeanButtonsPerRow keeps suggestion buttons wide enough to read a
+// long word on a phone.
+const didYouMeanButtonsPerRow = 3
+
+// didYouMeanRows lays out one button per suggestion; each reopens the lookup
+// for that word. A word too long for callback_data gets no button.
+func didYouMeanRows(matches []tools.FuzzyMatch) [][]tgbotapi.InlineKeyboardButton {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, m := range matches {
+		data, ok := cardCallbackData(m.Word)
+		if !ok {
+			continue
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(m.Word, data))
+		if len(row) == didYouMeanButtonsPerRow {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// cardCallbackData builds the payload of a button that opens word's card.
+// Telegram caps callback_data at 64 bytes; ok=false means the word does not
+// fit and the caller leaves the button out.
+func cardCallbackData(word string) (string, bool) {
+	data := "card_" + strings.TrimSpace(word)
+	return data, len(data) <= 64
+}
+
+// HandleCardCallback answers a button that names a word — a «did you mean»
+// suggestion — with the card that typing the word would have produced.
+func (n *Net) HandleCardCallback(ctx context.Context, cq *tgbotapi.CallbackQuery) error {
+	word, found := strings.CutPrefix(cq.Data, "card_")
+	if !found || word == "" {
+		return fmt.Errorf("invalid card callback data: %q", cq.Data)
+	}
+	defer func() {
+		if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, "")); err != nil {
+			n.log.WithError(err).Warn("failed to ack card callback")
+		}
+	}()
+
+	translations, err := n.business.Translate(word)
+	if err != nil {
+		n.log.WithError(err).WithField("word", word).Warn("card callback lookup failed")
+		_, sendErr := n.send(tgbotapi.NewMessage(cq.Message.Chat.ID, DictionaryUnavailableText))
+		return sendErr
+	}
+	if len(translations) == 0 {
+		_, err := n.send(tgbotapi.NewMessage(cq.Message.Chat.ID, NoTranslationText))
+		return err
+	}
+	msg := tgbotapi.NewMessage(cq.Message.Chat.ID, clampMessage(n.translationCard(word, translations)))
+	msg.ParseMode = "html"
+	if word, ok := translationSaveWord(word, translations); ok {
+		if save, ok := saveButtonRow(word); ok {
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(save)
+		}
+	}
+	_, err = n.send(msg)
+	return err
+}
+
+// translationCard renders the answer to query. When Translate answered for a
+// different word than was typed — the headword of an inflected form, or the
+// Cyrillic a Latin query meant — the card is built for that word, since built
+// for the typed one it would find no entry about it and come out empty, and
+// opens with a note saying which word it is. A Chechen headword gets the
+// pronunciation line under it, when that is on.
+func (n *Net) translationCard(query string, translations []models.TranslationPairs) string {
+	var note string
+	if respelled := translations[0].Respelled; respelled != "" {
+		query = respelled
+		note = fmt.Sprintf(RespelledNoteFormat, html.EscapeString(respelled)) + "\n"
+	}
+	if headword := translations[0].FormOf; headword != "" {
+		query = headword
+		note += fmt.Sprintf(FormOfNoteFormat, html.EscapeString(headword)) + "\n"
+	}
+	if note != "" {
+		note += "\n"
+	}
+	card := tools.FormatCard(query, translations)
+	if card == "" {
+		card = tools.FormatPairs(translations)
+	} else if head, ok := tools.ChechenHead(query, translations); ok {
+		card = withPronunciation(card, n.pronunciationLine(head))
+	}
+	return note + card
+}
+
 // moreCallbackData builds the "More" button payload. Telegram caps callback_data
 // at 64 bytes, so it returns ok=false when the word is too long to encode; the
 // caller then omits the button rather than letting the whole message send fail.
@@ -162,3 +162,34 @@ func TestParseMoreCallback(t *testing.T) {
 		}
 	}
 }
+
+func TestDidYouMeanRows(t *testing.T) {
+	rows := didYouMeanRows([]tools.FuzzyMatch{
+		{Word: "Гӏала", Distance: 1},
+		{Word: strings.Repeat("ц", 40), Distance: 1}, // too long for callback_data
+		{Word: "Гала", Distance: 1},
+		{Word: "Яблоко", Distance: 2},
+		{Word: "Дитт", Distance: 2},
+	})
+	if len(rows) != 2 || len(rows[0]) != didYouMeanButtonsPerRow || len(rows[1]) != 1 {
+		t.Fatalf("rows = %+v, want 3+1 buttons without the oversized word", rows)
+	}
+	first := rows[0][0]
+	if first.Text != "Гӏала" || first.CallbackData == nil || *first.CallbackData != "card_Гӏала" {
+		t.Fatalf("first button = %q/%v, want Гӏала opening its card", first.Text, first.CallbackData)
+	}
+}
+
+func TestProvenanceFooter(t *testing.T) {
+	got := provenanceFooter([]models.TranslationPairs{
+		{Provenance: "dosham,glossary"},
+		{Provenance: "dosham"},
+		{Provenance: "glossary"},
+	})
+	if want := "<i>источники: dosham 2 · glossary 2</i>"; got != want {
+		t.Errorf("provenanceFooter = %q, want %q", got, want)
+	}
+	if got := provenanceFooter([]models.TranslationPairs{{}}); got != "" {
+		t.Errorf("provenanceFooter without provenance = %q, want empty", got)
+	}
+}
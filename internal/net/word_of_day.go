@@ -157,6 +157,10 @@ func (n *Net) StartWordOfDayScheduler(ctx context.Context) {
 			case <-timer.C:
 				n.sendWordOfDay(ctx)
 				n.resolveMissingWords(ctx)
+				n.pruneQuizQuestions(ctx)
+				n.pruneDuels(ctx)
+				n.pruneLookups(ctx)
+				n.pickWordleWord(ctx)
 			}
 		}
 	}()
@@ -196,6 +200,19 @@ func (n *Net) resolveMissingWords(ctx context.Context) {
 	}
 }
 
+// pruneQuizQuestions drops quiz questions past their QuizQuestionTTL. Their
+// buttons already answer as expired; this only keeps the table from growing.
+func (n *Net) pruneQuizQuestions(ctx context.Context) {
+	pruned, err := n.repo.PruneQuizQuestions(ctx, time.Now())
+	if err != nil {
+		n.log.WithError(err).Warn("quiz questions: prune")
+		return
+	}
+	if pruned > 0 {
+		n.log.Infof("quiz questions: pruned %d expired", pruned)
+	}
+}
+
 // wordOfDayDue reports whether today's broadcast was missed: the send hour
 // has passed and the last recorded send happened on an earlier day.
 func wordOfDayDue(now time.Time, lastSent string, hour int) bool {
@@ -305,6 +322,9 @@ func (n *Net) sendWordOfDay(ctx context.Context) {
 		tgbotapi.EscapeText(tgbotapi.ModeHTML, word.Chechen),
 		tgbotapi.EscapeText(tgbotapi.ModeHTML, word.Russian),
 	)
+	if line := n.pronunciationLine(spelling(word)); line != "" {
+		text += "\n" + line
+	}
 	// One dictionary and one grammar lookup for the whole broadcast, not per
 	// subscriber: a real usage example turns the card from vocabulary into language.
 	if ex, ok := n.usageExample(word.Chechen); ok {
@@ -0,0 +1,288 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandleWordle serves /wordle: today's board, started on the first call, and
+// «/wordle <слово>» to guess. Guesses are also taken as plain messages for a
+// while after the board is shown (see wordleGuessFor), which a group would
+// make anyone's, so in a group it only points to the private chat.
+func (n *Net) HandleWordle(ctx context.Context, m *tgbotapi.Message) error {
+	if isGroup(m.Chat) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, WordlePrivateOnlyText))
+		return err
+	}
+	if err := n.repo.StoreUser(ctx, int(m.From.ID), m.From.UserName); err != nil {
+		return fmt.Errorf("repo.StoreUser: %w", err)
+	}
+	if guess := strings.TrimSpace(m.CommandArguments()); guess != "" {
+		return n.HandleWordleGuess(ctx, m, guess)
+	}
+
+	word, game, err := n.startWordle(ctx, m)
+	if err != nil || game == nil {
+		return err
+	}
+	return n.sendWordleBoard(m.Chat.ID, word, game)
+}
+
+// HandleWordleGuess checks a guess at today's word and answers with the
+// board. A guess of the wrong length or one the lexicon does not know is
+// turned back without costing an attempt, as in Wordle.
+func (n *Net) HandleWordleGuess(ctx context.Context, m *tgbotapi.Message, guess string) error {
+	word, game, err := n.startWordle(ctx, m)
+	if err != nil || game == nil {
+		return err
+	}
+	if !game.Finished() {
+		letters := tools.ChechenLetters(guess)
+		if len(letters) != models.WordleLength {
+			n.setWordleActive(m.Chat.ID)
+			_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, fmt.Sprintf(WordleLengthFormat, models.WordleLength)))
+			return err
+		}
+		key := strings.Join(letters, "")
+		if !n.business.IsWordleWord(ctx, key) {
+			n.setWordleActive(m.Chat.ID)
+			msg := tgbotapi.NewMessage(m.Chat.ID, fmt.Sprintf(WordleUnknownWordFormat, tgbotapi.EscapeText(tgbotapi.ModeHTML, wordleLetters(letters))))
+			msg.ParseMode = "html"
+			_, err := n.send(msg)
+			return err
+		}
+		// A guess racing another from the same user loses its turn and is
+		// simply shown the board the winner left.
+		if _, err := n.repo.AddWordleGuess(ctx, game.Day, m.From.ID, len(game.Guesses), key, key == word, WordleMaxGuesses); err != nil {
+			return fmt.Errorf("repo.AddWordleGuess: %w", err)
+		}
+		if game, err = n.repo.GetWordleGame(ctx, game.Day, m.From.ID); err != nil || game == nil {
+			return fmt.Errorf("repo.GetWordleGame: %w", err)
+		}
+	}
+	return n.sendWordleBoard(m.Chat.ID, word, game)
+}
+
+// startWordle returns today's word and the user's game of it, starting the
+// game if needed. When there is no word to play it tells the user and
+// returns a nil game.
+func (n *Net) startWordle(ctx context.Context, m *tgbotapi.Message) (string, *models.WordleGame, error) {
+	day := time.Now().Format(time.DateOnly)
+	word, err := n.wordleWord(ctx, day)
+	if err != nil {
+		n.log.WithError(err).Warn("wordle: no word")
+		_, sErr := n.send(tgbotapi.NewMessage(m.Chat.ID, WordleErrorText))
+		return "", nil, sErr
+	}
+	game, err := n.repo.StartWordleGame(ctx, day, m.From.ID, m.From.UserName, m.From.FirstName)
+	if err != nil {
+		return "", nil, fmt.Errorf("repo.StartWordleGame: %w", err)
+	}
+	return word, game, nil
+}
+
+// wordleWord returns day's word, picking and storing it if this is the
+// day's first player — or the word-of-the-day timer, which picks it ahead.
+// Everyone reads the stored word, as with dailyQuestions.
+func (n *Net) wordleWord(ctx context.Context, day string) (string, error) {
+	word, err := n.repo.GetWordleWord(ctx, day)
+	if err != nil || word != "" {
+		return word, err
+	}
+	picked, err := n.business.WordleWord(ctx, day)
+	if err != nil {
+		return "", fmt.Errorf("business.WordleWord: %w", err)
+	}
+	if err := n.repo.SaveWordleWord(ctx, day, picked); err != nil {
+		return "", fmt.Errorf("repo.SaveWordleWord: %w", err)
+	}
+	word, err = n.repo.GetWordleWord(ctx, day)
+	if err == nil && word == "" {
+		err = fmt.Errorf("wordle word for %s not stored", day)
+	}
+	return word, err
+}
+
+// pickWordleWord picks today's word ahead of the first player, so the index
+// build is paid by the timer rather than by them.
+func (n *Net) pickWordleWord(ctx context.Context) {
+	if _, err := n.wordleWord(ctx, time.Now().Format(time.DateOnly)); err != nil {
+		n.log.WithError(err).Warn("wordle: pick today's word")
+	}
+}
+
+func (n *Net) sendWordleBoard(chatID int64, word string, game *models.WordleGame) error {
+	msg := tgbotapi.NewMessage(chatID, wordleBoardText(word, game))
+	msg.ParseMode = "html"
+	if game.Finished() {
+		n.clearWordleActive(chatID)
+		msg.ReplyMarkup = wordleResultButtons(word)
+	} else {
+		n.setWordleActive(chatID)
+	}
+	_, err := n.send(msg)
+	return err
+}
+
+// wordleBoardText renders a game: the rules before the first guess, a row
+// of squares and letters per guess, and what is next.
+func wordleBoardText(word string, g *models.WordleGame) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, WordleHeaderFormat, dailyDayLabel(g.Day))
+	if len(g.Guesses) == 0 {
+		fmt.Fprintf(&b, WordleRulesFormat, models.WordleLength, WordleMaxGuesses)
+	}
+	answer := tools.ChechenLetters(word)
+	for _, guess := range g.Guesses {
+		letters := tools.ChechenLetters(guess)
+		fmt.Fprintf(&b, "%s  <b>%s</b>\n", wordleSquares(tools.ScoreGuess(answer, letters)), tgbotapi.EscapeText(tgbotapi.ModeHTML, wordleLetters(letters)))
+	}
+	switch {
+	case g.Solved:
+		fmt.Fprintf(&b, WordleSolvedFormat, len(g.Guesses))
+	case g.Finished():
+		fmt.Fprintf(&b, WordleLostFormat, tgbotapi.EscapeText(tgbotapi.ModeHTML, wordleLetters(answer)))
+	default:
+		fmt.Fprintf(&b, WordleNextGuessFormat, len(g.Guesses)+1, WordleMaxGuesses)
+	}
+	return b.String()
+}
+
+// wordleSquares is a guess's marks as Wordle's coloured squares.
+func wordleSquares(marks []tools.LetterMark) string {
+	var b strings.Builder
+	for _, m := range marks {
+		switch m {
+		case tools.LetterRight:
+			b.WriteString("🟩")
+		case tools.LetterElsewhere:
+			b.WriteString("🟨")
+		default:
+			b.WriteString("⬜")
+		}
+	}
+	return b.String()
+}
+
+// wordleLetters spells a word out letter by letter, capitalized, so a
+// digraph reads as the one letter it counts as: «Хь Е Къ А Л».
+func wordleLetters(letters []string) string {
+	upper := make([]string, len(letters))
+	for i, l := range letters {
+		upper[i] = tools.UpperLetter(l)
+	}
+	return strings.Join(upper, " ")
+}
+
+// wordleGrid is a game's squares alone, a row per guess — what a shared
+// result shows without giving the word away.
+func wordleGrid(word string, guesses []string) string {
+	answer := tools.ChechenLetters(word)
+	rows := make([]string, len(guesses))
+	for i, guess := range guesses {
+		rows[i] = wordleSquares(tools.ScoreGuess(answer, tools.ChechenLetters(guess)))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// wordleScore is a finished game's score as Wordle writes it: the guesses it
+// took out of the allowed, or X out of them when the word was not found.
+func wordleScore(g *models.WordleGame) string {
+	if !g.Solved {
+		return fmt.Sprintf("X/%d", WordleMaxGuesses)
+	}
+	return fmt.Sprintf("%d/%d", len(g.Guesses), WordleMaxGuesses)
+}
+
+// wordleResultButtons offer sharing the grid through inline mode, and the
+// card of the day's word: the game is also a way to learn one.
+func wordleResultButtons(word string) tgbotapi.InlineKeyboardMarkup {
+	share := WordleShareQuery
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.InlineKeyboardButton{Text: DailyShareButtonText, SwitchInlineQuery: &share},
+		),
+	}
+	if data, ok := cardCallbackData(word); ok {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(WordleCardButtonFormat, word), data),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// HandleInlineWordle answers the share button's inline query with the
+// user's grid for today, like HandleInlineDaily: personal, uncached, and
+// only once the game is over.
+func (n *Net) HandleInlineWordle(ctx context.Context, iq *tgbotapi.InlineQuery) error {
+	day := time.Now().Format(time.DateOnly)
+	game, err := n.repo.GetWordleGame(ctx, day, iq.From.ID)
+	if err != nil {
+		return fmt.Errorf("repo.GetWordleGame: %w", err)
+	}
+	conf := tgbotapi.InlineConfig{
+		InlineQueryID: iq.ID,
+		IsPersonal:    true,
+		CacheTime:     0,
+		Results:       []any{},
+	}
+	if game == nil || !game.Finished() {
+		conf.SwitchPMText = WordleInlinePlayText
+		conf.SwitchPMParameter = wordleStartParameter
+		return n.answerInline(conf)
+	}
+	word, err := n.repo.GetWordleWord(ctx, day)
+	if err != nil {
+		return fmt.Errorf("repo.GetWordleWord: %w", err)
+	}
+
+	grid := wordleGrid(word, game.Guesses)
+	article := tgbotapi.NewInlineQueryResultArticle(iq.ID+"_wordle", fmt.Sprintf(WordleInlineTitleFormat, wordleScore(game)),
+		fmt.Sprintf(WordleShareFormat, dailyDayLabel(day), wordleScore(game), grid))
+	article.Description = strings.ReplaceAll(grid, "\n", " ")
+	markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonURL(WordlePlayButtonText, "https://t.me/"+n.bot.Self.UserName+"?start="+wordleStartParameter),
+	))
+	article.ReplyMarkup = &markup
+	conf.Results = []any{article}
+	return n.answerInline(conf)
+}
+
+// wordleStartParameter is the /start payload that opens /wordle.
+const wordleStartParameter = "wordle"
+
+// wordleGuessFor reports whether m is a guess at the chat's game rather than
+// a word to look up: the chat was shown an unfinished board within
+// WordleGuessTimeout and m is one word of models.WordleLength letters. Anything
+// else stays a lookup, so a game left open does not swallow the dictionary.
+func (n *Net) wordleGuessFor(m *tgbotapi.Message) bool {
+	if m.Text == "" || len(tools.ChechenLetters(m.Text)) != models.WordleLength {
+		return false
+	}
+	n.wordleMu.Lock()
+	defer n.wordleMu.Unlock()
+	shown, ok := n.wordleActive[m.Chat.ID]
+	if ok && time.Since(shown) > WordleGuessTimeout {
+		delete(n.wordleActive, m.Chat.ID)
+		return false
+	}
+	return ok
+}
+
+func (n *Net) setWordleActive(chatID int64) {
+	n.wordleMu.Lock()
+	defer n.wordleMu.Unlock()
+	n.wordleActive[chatID] = time.Now()
+}
+
+func (n *Net) clearWordleActive(chatID int64) {
+	n.wordleMu.Lock()
+	defer n.wordleMu.Unlock()
+	delete(n.wordleActive, chatID)
+}
@@ -0,0 +1,74 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestWordleBoardText_RowsAndOutcome(t *testing.T) {
+	g := &models.WordleGame{Day: "2026-10-16", Guesses: []string{"кӏорни", "хьекъал"}, Solved: true, FinishedAt: time.Now()}
+	text := wordleBoardText("хьекъал", g)
+	for _, want := range []string{
+		"16.10.2026",
+		"⬜⬜⬜⬜⬜  <b>Кӏ О Р Н И</b>",
+		"🟩🟩🟩🟩🟩  <b>Хь Е Къ А Л</b>",
+		"№2",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("board missing %q:\n%s", want, text)
+		}
+	}
+
+	// A lost game reveals the word; an open one asks for the next guess.
+	g = &models.WordleGame{Day: "2026-10-16", Guesses: []string{"кӏорни"}, FinishedAt: time.Now()}
+	if text := wordleBoardText("хьекъал", g); !strings.Contains(text, "<b>Хь Е Къ А Л</b>. ") {
+		t.Errorf("lost board does not reveal the word:\n%s", text)
+	}
+	g = &models.WordleGame{Day: "2026-10-16"}
+	if text := wordleBoardText("хьекъал", g); !strings.Contains(text, "Попытка 1/6") {
+		t.Errorf("new board does not ask for the first guess:\n%s", text)
+	}
+}
+
+func TestWordleGrid_HidesLetters(t *testing.T) {
+	// а-хь-а-л-а against хь-е-къ-а-л: the word has one а, so only the first
+	// is marked, and хь counts as the one letter it is.
+	got := wordleGrid("хьекъал", []string{"ахьала", "хьекъал"})
+	want := "🟨🟨⬜🟨⬜\n🟩🟩🟩🟩🟩"
+	if got != want {
+		t.Errorf("wordleGrid = %q, want %q", got, want)
+	}
+	if s := wordleScore(&models.WordleGame{Guesses: make([]string, 6)}); s != "X/6" {
+		t.Errorf("wordleScore(lost) = %q, want X/6", s)
+	}
+}
+
+func TestWordleGuessFor(t *testing.T) {
+	n := &Net{wordleActive: make(map[int64]time.Time)}
+	chat := &tgbotapi.Chat{ID: 1, Type: "private"}
+
+	if n.wordleGuessFor(&tgbotapi.Message{Chat: chat, Text: "хьекъал"}) {
+		t.Fatal("without a board shown a message is a lookup")
+	}
+	n.setWordleActive(1)
+	for text, want := range map[string]bool{
+		"хьекъал":      true,
+		"к1орни":       true,  // the digit stand-in for Ӏ
+		"гӏала":        false, // four letters
+		"хьекъал дика": false,
+		"hello":        false,
+	} {
+		if got := n.wordleGuessFor(&tgbotapi.Message{Chat: chat, Text: text}); got != want {
+			t.Errorf("wordleGuessFor(%q) = %v, want %v", text, got, want)
+		}
+	}
+
+	n.wordleActive[1] = time.Now().Add(-WordleGuessTimeout - time.Second)
+	if n.wordleGuessFor(&tgbotapi.Message{Chat: chat, Text: "хьекъал"}) {
+		t.Fatal("a board shown long ago must not take the next message as a guess")
+	}
+}
@@ -0,0 +1,222 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// pendingWrite is a /write question waiting for the user's typed answer.
+type pendingWrite struct {
+	word   models.RandomWord
+	sentAt time.Time
+}
+
+// HandleWrite asks the user to type a Chechen word for a Russian one. Multiple
+// choice only asks to recognize the right spelling among four; typing it is
+// what tests spelling. The answer is the user's next message, which a group
+// would make anyone's, so in a group it only points to the private chat.
+func (n *Net) HandleWrite(ctx context.Context, m *tgbotapi.Message) error {
+	if isGroup(m.Chat) {
+		_, err := n.send(tgbotapi.NewMessage(m.Chat.ID, WritePrivateOnlyText))
+		return err
+	}
+	if err := n.repo.StoreUser(ctx, int(m.From.ID), m.From.UserName); err != nil {
+		return fmt.Errorf("repo.StoreUser: %w", err)
+	}
+	return n.sendWriteQuestion(ctx, m.Chat.ID)
+}
+
+func (n *Net) sendWriteQuestion(ctx context.Context, chatID int64) error {
+	word, err := n.business.RandomWordFromAPI(ctx)
+	if err != nil {
+		n.log.WithError(err).Warn("write: no word")
+		_, sErr := n.send(tgbotapi.NewMessage(chatID, WriteErrorText))
+		return sErr
+	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(WriteQuestionFormat, tgbotapi.EscapeText(tgbotapi.ModeHTML, word.Russian)))
+	msg.ParseMode = "html"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(WriteGiveUpButtonText, "quiz_wg"),
+	))
+	if _, err := n.send(msg); err != nil {
+		return err
+	}
+	n.setPendingWrite(chatID, pendingWrite{word: *word, sentAt: time.Now()})
+	return nil
+}
+
+// HandleWriteAnswer grades a typed answer to a /write question. Any Chechen
+// word the dictionary gives for the Russian one is right — the question names
+// a meaning, not a headword — and so is any of their inflected forms:
+// «цӏенна» knows the word as well as «цӏа» does. A wrong answer a slip away
+// from one of them is shown the letters it got wrong.
+func (n *Net) HandleWriteAnswer(ctx context.Context, m *tgbotapi.Message, p pendingWrite) error {
+	pairs, err := n.business.Translate(p.word.Russian)
+	if err != nil {
+		n.log.WithError(err).WithField("word", p.word.Russian).Warn("write: translation lookup failed")
+	}
+	accepted := writeAnswers(p.word, pairs)
+	grade := tools.GradeAnswer(m.Text, accepted)
+	formOf := ""
+	for i, headword := range accepted {
+		if grade.Correct || i == writeFormLookups {
+			break
+		}
+		g, err := n.business.GrammarFor(ctx, headword)
+		if err != nil {
+			n.log.WithError(err).WithField("word", headword).Warn("write: grammar lookup failed")
+			continue
+		}
+		if g == nil {
+			continue
+		}
+		if fg := tools.GradeAnswer(m.Text, g.Forms); fg.Correct {
+			grade, formOf = fg, headword
+		}
+	}
+
+	var verdict string
+	switch {
+	case grade.Correct && formOf != "":
+		verdict = fmt.Sprintf(WriteFormAcceptedFormat, tgbotapi.EscapeText(tgbotapi.ModeHTML, formOf))
+	case grade.Correct:
+		verdict = QuizCorrectToast
+	case grade.NearMiss:
+		verdict = fmt.Sprintf(WriteNearMissFormat, tools.SpellingDiff(m.Text, grade.Closest))
+	default:
+		verdict = fmt.Sprintf(WriteWrongFormat, tgbotapi.EscapeText(tgbotapi.ModeHTML, p.word.Chechen))
+	}
+	return n.finishWrite(ctx, m.Chat, m.From, p, strings.TrimSpace(m.Text), grade.Correct, verdict)
+}
+
+// writeFormLookups caps the headwords whose inflected forms a wrong-looking
+// /write answer is checked against, each a grammar lookup.
+const writeFormLookups = 4
+
+// writeAnswers lists the Chechen words a /write answer may be: the
+// question's own word first, then every other one the dictionary pairs with
+// its Russian meaning — «лук» is хох and ӏад both, and the question does not
+// say which it means.
+func writeAnswers(word models.RandomWord, pairs []models.TranslationPairs) []string {
+	accepted := tools.AnswerVariants(word.Chechen)
+	if len(accepted) == 0 {
+		accepted = []string{word.Chechen}
+	}
+	seen := make(map[string]bool)
+	for _, a := range accepted {
+		seen[tools.FuzzyKey(a)] = true
+	}
+	meaning := tools.NormalizeSearch(word.Russian)
+	for _, p := range pairs {
+		chechen, russian := p.Original, p.Translate
+		switch {
+		case p.OriginalLang == "RUS" && p.TranslateLang == "CHE":
+			chechen, russian = p.Translate, p.Original
+		case p.OriginalLang != "CHE" || p.TranslateLang != "RUS":
+			continue
+		}
+		if !slices.ContainsFunc(tools.AnswerVariants(russian), func(r string) bool {
+			return tools.NormalizeSearch(r) == meaning
+		}) {
+			continue
+		}
+		for _, a := range tools.AnswerVariants(chechen) {
+			if key := tools.FuzzyKey(a); !seen[key] {
+				seen[key] = true
+				accepted = append(accepted, a)
+			}
+		}
+	}
+	return accepted
+}
+
+// finishWrite records a /write answer, typed or given up on, like a button
+// answer: into the quiz stats and the answer log, and replies with the
+// verdict, the running score and the way on.
+func (n *Net) finishWrite(ctx context.Context, chat *tgbotapi.Chat, from *tgbotapi.User, p pendingWrite, typed string, correct bool, verdict string) error {
+	if err := n.repo.RecordQuizAnswer(ctx, from.ID, from.UserName, from.FirstName, correct); err != nil {
+		n.log.WithError(err).WithField("user_id", from.ID).Warn("RecordQuizAnswer (write) failed")
+	}
+	answer := models.QuizAnswer{
+		UserID:        from.ID,
+		Prompt:        p.word.Russian,
+		Reversed:      true,
+		Chosen:        typed,
+		CorrectOption: p.word.Chechen,
+		Correct:       correct,
+		ChatType:      chat.Type,
+		Mode:          models.QuizModeWrite,
+		Latency:       time.Since(p.sentAt),
+	}
+	if err := n.repo.LogQuizAnswer(ctx, answer); err != nil {
+		n.log.WithError(err).WithField("user_id", from.ID).Warn("LogQuizAnswer (write) failed")
+	}
+
+	word := p.word.Chechen
+	msg := tgbotapi.NewMessage(chat.ID, verdict+n.quizScoreSuffix(ctx, from.ID))
+	msg.ParseMode = "html"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.InlineKeyboardButton{Text: QuizLookupButtonText, SwitchInlineQueryCurrentChat: &word},
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(WriteNextButtonText, "quiz_w"),
+		),
+	)
+	_, err := n.send(msg)
+	return err
+}
+
+// giveUpWrite reveals the answer to the pending /write question, which counts
+// as a wrong answer: a free pass on every hard word would make the score say
+// more than the user knows.
+func (n *Net) giveUpWrite(ctx context.Context, cq *tgbotapi.CallbackQuery) error {
+	p, ok := n.takePendingWrite(cq.Message.Chat.ID)
+	if !ok {
+		_, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, QuizExpiredToast))
+		return err
+	}
+	if _, err := n.bot.Request(tgbotapi.NewCallback(cq.ID, "")); err != nil {
+		n.log.WithError(err).Warn("failed to ack write give-up callback")
+	}
+	verdict := fmt.Sprintf(WriteGaveUpFormat, tgbotapi.EscapeText(tgbotapi.ModeHTML, p.word.Chechen))
+	return n.finishWrite(ctx, cq.Message.Chat, cq.From, p, "", false, verdict)
+}
+
+// writeAnswerFor claims the /write question m answers, if there is one. A
+// message without text — a sticker, a photo — answers nothing and leaves the
+// question waiting.
+func (n *Net) writeAnswerFor(m *tgbotapi.Message) (pendingWrite, bool) {
+	if m.Text == "" {
+		return pendingWrite{}, false
+	}
+	return n.takePendingWrite(m.Chat.ID)
+}
+
+func (n *Net) setPendingWrite(chatID int64, p pendingWrite) {
+	n.writeMu.Lock()
+	defer n.writeMu.Unlock()
+	n.writePending[chatID] = p
+}
+
+// takePendingWrite claims the chat's /write question, so an answer is graded
+// once however fast the next message follows. A question older than
+// WriteAnswerTimeout is dropped instead: by then the user's message is far
+// likelier a word to look up than an answer.
+func (n *Net) takePendingWrite(chatID int64) (pendingWrite, bool) {
+	n.writeMu.Lock()
+	defer n.writeMu.Unlock()
+	p, ok := n.writePending[chatID]
+	if !ok {
+		return pendingWrite{}, false
+	}
+	delete(n.writePending, chatID)
+	return p, time.Since(p.sentAt) <= WriteAnswerTimeout
+}
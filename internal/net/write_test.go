@@ -0,0 +1,66 @@
+package net
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/tools"
+	"slices"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestPendingWrite_AnsweredOnce(t *testing.T) {
+	n := &Net{writePending: make(map[int64]pendingWrite)}
+	chat := &tgbotapi.Chat{ID: 1, Type: "private"}
+	n.setPendingWrite(1, pendingWrite{word: models.RandomWord{Chechen: "цӏа", Russian: "дом"}, sentAt: time.Now()})
+
+	if _, ok := n.writeAnswerFor(&tgbotapi.Message{Chat: chat}); ok {
+		t.Fatal("a message without text must not answer the question")
+	}
+	p, ok := n.writeAnswerFor(&tgbotapi.Message{Chat: chat, Text: "цӏа"})
+	if !ok || p.word.Chechen != "цӏа" {
+		t.Fatalf("writeAnswerFor = %+v, %v; want the pending question", p, ok)
+	}
+	if _, ok := n.writeAnswerFor(&tgbotapi.Message{Chat: chat, Text: "цӏа"}); ok {
+		t.Fatal("a question must be answered only once")
+	}
+}
+
+func TestPendingWrite_StaleQuestionDropped(t *testing.T) {
+	n := &Net{writePending: make(map[int64]pendingWrite)}
+	n.setPendingWrite(1, pendingWrite{sentAt: time.Now().Add(-WriteAnswerTimeout - time.Second)})
+
+	if _, ok := n.takePendingWrite(1); ok {
+		t.Fatal("a stale question must not take the next message as its answer")
+	}
+	if len(n.writePending) != 0 {
+		t.Fatal("a stale question must be dropped")
+	}
+}
+
+func TestWriteAnswers_EverySenseOfThePrompt(t *testing.T) {
+	// «лук» is both the onion and the bow; the question asks for хох, but
+	// ӏад answers it as well. A pair only containing the word is no sense
+	// of it.
+	word := models.RandomWord{Chechen: "хох", Russian: "лук"}
+	pairs := []models.TranslationPairs{
+		{Original: "Хох", OriginalLang: "CHE", Translate: "лук", TranslateLang: "RUS"},
+		{Original: "Ӏад", OriginalLang: "CHE", Translate: "лук (оружие), самострел", TranslateLang: "RUS"},
+		{Original: "лук", OriginalLang: "RUS", Translate: "бӏар", TranslateLang: "CHE"},
+		{Original: "кӏорни", OriginalLang: "CHE", Translate: "луковица", TranslateLang: "RUS"},
+	}
+	got := writeAnswers(word, pairs)
+	want := []string{"хох", "Ӏад", "бӏар"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("writeAnswers = %q, want %q", got, want)
+	}
+	for _, typed := range []string{"хох", "1ад", "бӏар"} {
+		if !tools.GradeAnswer(typed, got).Correct {
+			t.Errorf("%q graded wrong for «лук»", typed)
+		}
+	}
+	if tools.GradeAnswer("кӏорни", got).Correct {
+		t.Error("кӏорни graded right for «лук»")
+	}
+}
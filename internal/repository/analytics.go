@@ -35,6 +35,13 @@ func (r *Repository) StoreUser(ctx context.Context, userID int, username string)
 // flag, activity row) in one transaction: it fires on every message and inline
 // keystroke, and three separate commits meant three WAL syncs where one does.
 func (r *Repository) RecordUserActivity(ctx context.Context, userID int64, username string, activityType entities.ActivityType) error {
+	return r.recordActivity(ctx, userID, username, activityType, nil)
+}
+
+// recordActivity is RecordUserActivity, plus the lookup's detail row when
+// lookup is not nil — in the same transaction, so the detail costs no extra
+// sync either.
+func (r *Repository) recordActivity(ctx context.Context, userID int64, username string, activityType entities.ActivityType, lookup *entities.Lookup) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -47,9 +54,21 @@ func (r *Repository) RecordUserActivity(ctx context.Context, userID int64, usern
 	if _, err := tx.ExecContext(ctx, unblockUserQuery, userID); err != nil {
 		return err
 	}
-	if _, err := tx.ExecContext(ctx, insertActivityQuery, userID, activityType); err != nil {
+	res, err := tx.ExecContext(ctx, insertActivityQuery, userID, activityType)
+	if err != nil {
 		return err
 	}
+	if lookup != nil {
+		activityID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, insertLookupQuery,
+			activityID, userID, lookup.Query, lookup.Word, lookup.Direction, lookup.Hit, formatReviewTime(time.Now()), userID,
+		); err != nil {
+			return err
+		}
+	}
 	return tx.Commit()
 }
 
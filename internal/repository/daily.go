@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// GetDailyChallenge returns day's challenge questions, or nil when none has
+// been generated for the day yet.
+func (r *Repository) GetDailyChallenge(ctx context.Context, day string) ([]models.QuizQuestion, error) {
+	var raw string
+	err := r.db.QueryRowContext(ctx, `SELECT questions FROM daily_challenges WHERE day = ?;`, day).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var questions []models.QuizQuestion
+	if err := json.Unmarshal([]byte(raw), &questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// SaveDailyChallenge stores day's challenge unless one is stored already: two
+// players opening a new day at once both generate one, and the first stored
+// is the one everybody plays. Read it back with GetDailyChallenge.
+func (r *Repository) SaveDailyChallenge(ctx context.Context, day string, questions []models.QuizQuestion) error {
+	raw, err := json.Marshal(questions)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `INSERT OR IGNORE INTO daily_challenges (day, questions) VALUES (?, ?);`, day, string(raw))
+	return err
+}
+
+// StartDailyAttempt returns the user's attempt at day's challenge, starting
+// it if they have none. Starting twice resumes the first attempt: there is
+// one per user per day.
+func (r *Repository) StartDailyAttempt(ctx context.Context, day string, userID int64, username, firstName string) (*models.DailyAttempt, error) {
+	if _, err := r.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO daily_challenge_attempts (day, user_id, username, first_name, started_at) VALUES (?, ?, ?, ?, ?);`,
+		day, userID, username, firstName, formatReviewTime(time.Now()),
+	); err != nil {
+		return nil, err
+	}
+	return r.GetDailyAttempt(ctx, day, userID)
+}
+
+// GetDailyAttempt returns the user's attempt at day's challenge, or nil when
+// they have not started one.
+func (r *Repository) GetDailyAttempt(ctx context.Context, day string, userID int64) (*models.DailyAttempt, error) {
+	attempts, err := r.queryDailyAttempts(ctx,
+		`SELECT `+dailyAttemptColumns+` FROM daily_challenge_attempts WHERE day = ? AND user_id = ?;`,
+		day, userID,
+	)
+	if err != nil || len(attempts) == 0 {
+		return nil, err
+	}
+	return &attempts[0], nil
+}
+
+// RecordDailyAnswer records the answer to question index of the user's
+// attempt, out of questions, finishing the attempt on the last one. It
+// returns false, recording nothing, unless index is the attempt's next
+// unanswered question: a double tap or a stale button cannot answer twice or
+// out of turn.
+func (r *Repository) RecordDailyAnswer(ctx context.Context, day string, userID int64, index int, correct bool, questions int) (bool, error) {
+	mark, inc := "0", 0
+	if correct {
+		mark, inc = "1", 1
+	}
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE daily_challenge_attempts
+		 SET results = results || ?,
+		     correct = correct + ?,
+		     finished_at = CASE WHEN length(results) + 1 >= ? THEN ? ELSE finished_at END
+		 WHERE day = ? AND user_id = ? AND length(results) = ? AND finished_at IS NULL;`,
+		mark, inc, questions, formatReviewTime(time.Now()), day, userID, index,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// TopDailyScorers returns day's leaderboard: finished attempts by correct
+// answers, ties going to the faster.
+func (r *Repository) TopDailyScorers(ctx context.Context, day string, limit int) ([]models.DailyAttempt, error) {
+	return r.queryDailyAttempts(ctx,
+		`SELECT `+dailyAttemptColumns+` FROM daily_challenge_attempts
+		 WHERE day = ? AND finished_at IS NOT NULL
+		 ORDER BY `+dailyAttemptOrder+`
+		 LIMIT ?;`,
+		day, limit,
+	)
+}
+
+// GetDailyRank returns the user's place on day's leaderboard and how many
+// have finished the challenge. rank is 0 until the user has finished.
+func (r *Repository) GetDailyRank(ctx context.Context, day string, userID int64) (rank, players int, err error) {
+	err = r.db.QueryRowContext(ctx,
+		`WITH board AS (
+		     SELECT user_id, ROW_NUMBER() OVER (ORDER BY `+dailyAttemptOrder+`) AS place
+		     FROM daily_challenge_attempts
+		     WHERE day = ? AND finished_at IS NOT NULL
+		 )
+		 SELECT COALESCE((SELECT place FROM board WHERE user_id = ?), 0), (SELECT COUNT(*) FROM board);`,
+		day, userID,
+	).Scan(&rank, &players)
+	return rank, players, err
+}
+
+const (
+	dailyAttemptColumns = `day, user_id, username, first_name, results, correct, started_at, COALESCE(finished_at, '')`
+	dailyAttemptOrder   = `correct DESC, julianday(finished_at) - julianday(started_at) ASC, user_id`
+)
+
+func (r *Repository) queryDailyAttempts(ctx context.Context, query string, args ...any) ([]models.DailyAttempt, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []models.DailyAttempt
+	for rows.Next() {
+		var a models.DailyAttempt
+		var results, started, finished string
+		if err := rows.Scan(&a.Day, &a.UserID, &a.Username, &a.FirstName, &results, &a.Correct, &started, &finished); err != nil {
+			return nil, err
+		}
+		for _, c := range results {
+			a.Results = append(a.Results, c == '1')
+		}
+		a.Total = len(a.Results)
+		if a.StartedAt, err = time.ParseInLocation(reviewTime, started, time.UTC); err != nil {
+			return nil, err
+		}
+		if finished = strings.TrimSpace(finished); finished != "" {
+			if a.FinishedAt, err = time.ParseInLocation(reviewTime, finished, time.UTC); err != nil {
+				return nil, err
+			}
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"testing"
+)
+
+func TestDailyChallenge_FirstStoredWins(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	if got, err := r.GetDailyChallenge(ctx, "2026-10-16"); err != nil || got != nil {
+		t.Fatalf("GetDailyChallenge(new day) = %+v, %v; want nothing", got, err)
+	}
+	first := []models.QuizQuestion{{Prompt: "дитт", Options: []string{"дом", "дерево"}, CorrectIdx: 1}}
+	second := []models.QuizQuestion{{Prompt: "цӏа", Options: []string{"дом", "дерево"}}}
+	for _, qs := range [][]models.QuizQuestion{first, second} {
+		if err := r.SaveDailyChallenge(ctx, "2026-10-16", qs); err != nil {
+			t.Fatalf("SaveDailyChallenge: %v", err)
+		}
+	}
+	got, err := r.GetDailyChallenge(ctx, "2026-10-16")
+	if err != nil || len(got) != 1 || got[0].Prompt != "дитт" || got[0].CorrectIdx != 1 {
+		t.Fatalf("GetDailyChallenge = %+v, %v; want the first challenge stored", got, err)
+	}
+}
+
+func TestDailyAttempt_AnswersInTurnOnceEach(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+	const day, questions = "2026-10-16", 3
+
+	a, err := r.StartDailyAttempt(ctx, day, 7, "ali", "Али")
+	if err != nil || a == nil || len(a.Results) != 0 || a.Finished() {
+		t.Fatalf("StartDailyAttempt = %+v, %v; want a fresh attempt", a, err)
+	}
+	answer := func(index int, correct, want bool) {
+		t.Helper()
+		if ok, err := r.RecordDailyAnswer(ctx, day, 7, index, correct, questions); err != nil || ok != want {
+			t.Fatalf("RecordDailyAnswer(%d) = %v, %v; want %v", index, ok, err, want)
+		}
+	}
+	answer(0, true, true)
+	answer(0, false, false) // double tap
+	answer(2, true, false)  // out of turn
+	answer(1, false, true)
+	answer(2, true, true)
+	answer(3, true, false) // past the end
+
+	a, err = r.StartDailyAttempt(ctx, day, 7, "ali", "Али")
+	if err != nil || !a.Finished() || a.Correct != 2 || a.Total != 3 || a.Results[0] != true || a.Results[1] != false {
+		t.Fatalf("attempt after answering = %+v, %v; want finished with ✅❌✅", a, err)
+	}
+
+	// An unfinished attempt is not on the board.
+	if _, err := r.StartDailyAttempt(ctx, day, 8, "zara", ""); err != nil {
+		t.Fatalf("StartDailyAttempt: %v", err)
+	}
+	top, err := r.TopDailyScorers(ctx, day, 10)
+	if err != nil || len(top) != 1 || top[0].UserID != 7 {
+		t.Fatalf("TopDailyScorers = %+v, %v; want only the finished attempt", top, err)
+	}
+	if rank, players, err := r.GetDailyRank(ctx, day, 7); err != nil || rank != 1 || players != 1 {
+		t.Fatalf("GetDailyRank = %d of %d, %v; want 1 of 1", rank, players, err)
+	}
+	if rank, _, err := r.GetDailyRank(ctx, day, 8); err != nil || rank != 0 {
+		t.Fatalf("GetDailyRank(unfinished) = %d, %v; want 0", rank, err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"unicode"
 )
 
 type TranslationPair struct {
@@ -93,8 +94,9 @@ func (r *Repository) InsertTranslationPair(ctx context.Context, pair Translation
 			entry_type,
 			subtype,
 			entry_index,
-			entry_notes
-		) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`,
+			entry_notes,
+			fetched_at
+		) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, current_timestamp);`,
 		pair.OriginalRaw,
 		pair.OriginalClean,
 		pair.OriginalLang,
@@ -518,3 +520,147 @@ func (r *Repository) StoreSpellcheckFeedback(ctx context.Context, userID int64,
 	)
 	return err
 }
+
+// SearchTranslationPairs is a ranked full-text search over headwords, glosses
+// and parsed article examples, for a word the exact and prefix lookups cannot
+// reach because it only occurs inside a longer text. query is already
+// normalized; every word in it must appear. A headword hit outranks a gloss
+// hit, which outranks an example. Pairs keep their stored orientation.
+func (r *Repository) SearchTranslationPairs(ctx context.Context, query string, limit int) ([]models.TranslationPairs, error) {
+	match := ftsMatch(query)
+	if match == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`select
+			p.original_raw,
+			p.original_lang,
+			p.translation_raw,
+			p.translation_lang,
+			p.rate,
+			p.entry_type,
+			p.subtype,
+			p.entry_index,
+			p.entry_notes,
+			p.structured_json
+		from dictionary_fts f
+		join dictionary_pairs p on p.id = f.rowid
+		where dictionary_fts match ?
+		  and (p.formatted_chosen is null or p.formatted_chosen != 'deleted')
+		order by bm25(dictionary_fts, 10.0, 5.0, 1.0), p.id
+		limit ?;`,
+		match, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.TranslationPairs
+	for rows.Next() {
+		var pair models.TranslationPairs
+		var entryType, entryNotes, structured sql.NullString
+		if err := rows.Scan(
+			&pair.Original,
+			&pair.OriginalLang,
+			&pair.Translate,
+			&pair.TranslateLang,
+			&pair.Rate,
+			&entryType,
+			&pair.Subtype,
+			&pair.EntryIndex,
+			&entryNotes,
+			&structured,
+		); err != nil {
+			return nil, err
+		}
+		pair.EntryType = entryType.String
+		pair.Notes = entryNotes.String
+		pair.Structured = structured.String
+		results = append(results, pair)
+	}
+
+	return results, rows.Err()
+}
+
+// ftsMatch turns user text into an FTS5 expression: each word a quoted
+// string, all required. Quoting is what keeps a stray "-", "*" or "OR" in a
+// query from being read as FTS syntax — or failing to parse at all.
+func ftsMatch(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = `"` + w + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// ListChechenLexicon returns the Chechen side of the lexicon: headwords —
+// entries' own and Chechen translations of Russian entries, many of which
+// are glosses the caller has to filter — and the inflected forms the form
+// index knows. Phrases and deleted pairs are left out, as in ListLexicon.
+func (r *Repository) ListChechenLexicon(ctx context.Context) (headwords, forms []string, err error) {
+	headwords, err = r.listWords(ctx,
+		`select min(original_raw)
+		from dictionary_pairs
+		where original_lang = 'CHE'
+		  and coalesce(entry_type, '') != 'TEXT'
+		  and (formatted_chosen is null or formatted_chosen != 'deleted')
+		group by original_clean
+		union
+		select min(translation_raw)
+		from dictionary_pairs
+		where translation_lang = 'CHE'
+		  and coalesce(entry_type, '') != 'TEXT'
+		  and (formatted_chosen is null or formatted_chosen != 'deleted')
+		group by translation_clean;`,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	forms, err = r.listWords(ctx, `select form_clean from entry_forms;`)
+	if err != nil {
+		return nil, nil, err
+	}
+	return headwords, forms, nil
+}
+
+func (r *Repository) listWords(ctx context.Context, query string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var words []string
+	for rows.Next() {
+		var w string
+		if err := rows.Scan(&w); err != nil {
+			return nil, err
+		}
+		words = append(words, w)
+	}
+	return words, rows.Err()
+}
+
+// ListLexicon returns every word a lookup can land on: the stored headwords
+// of both languages and the inflected forms the form index knows. Phrases are
+// left out — a typo in one is not what fuzzy matching can fix — and so are
+// pairs a moderator deleted.
+func (r *Repository) ListLexicon(ctx context.Context) ([]string, error) {
+	return r.listWords(ctx,
+		`select min(original_raw)
+		from dictionary_pairs
+		where coalesce(entry_type, '') != 'TEXT'
+		  and (formatted_chosen is null or formatted_chosen != 'deleted')
+		group by original_clean
+		union all
+		select form_clean from entry_forms;`,
+	)
+}
@@ -281,3 +281,65 @@ func TestInsertTranslationPair_ReportsInserted(t *testing.T) {
 		t.Fatalf("duplicate resolved to id %d, want original %d", dupID, id1)
 	}
 }
+
+func TestSearchTranslationPairs(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	insert := func(p TranslationPair) int64 {
+		t.Helper()
+		p.Source = "api"
+		id, _, err := r.InsertTranslationPair(ctx, p)
+		if err != nil {
+			t.Fatalf("insert %q: %v", p.OriginalRaw, err)
+		}
+		return id
+	}
+	insert(TranslationPair{
+		OriginalRaw: "Ӏаж", OriginalClean: "ӏаж", OriginalLang: "CHE",
+		TranslationRaw: "яблоко, плод яблони", TranslationClean: "яблоко, плод яблони", TranslationLang: "RUS",
+	})
+	article := insert(TranslationPair{
+		OriginalRaw: "Сад", OriginalClean: "сад", OriginalLang: "RUS",
+		TranslationRaw: "беш", TranslationClean: "беш", TranslationLang: "CHE",
+	})
+	deleted := insert(TranslationPair{
+		OriginalRaw: "Яблоко", OriginalClean: "яблоко", OriginalLang: "RUS",
+		TranslationRaw: "Ӏаж", TranslationClean: "ӏаж", TranslationLang: "CHE",
+	})
+	if err := r.SetTranslationPairFormattingChoice(ctx, deleted, "deleted"); err != nil {
+		t.Fatalf("mark deleted: %v", err)
+	}
+
+	// The example arrives later, through cmd/parse_articles; the index has to
+	// follow the update, and the stress mark, ё and a typed palochka stand-in
+	// must not hide the word.
+	if _, err := r.db.ExecContext(ctx,
+		`update dictionary_pairs set structured_json = ? where id = ?`,
+		`{"senses":[{"gloss":"беш"}],"examples":[{"ce":"бешахь 1ежаш ду","ru":"в саду́ растут я́блони, ёлки"}]}`, article,
+	); err != nil {
+		t.Fatalf("set structured_json: %v", err)
+	}
+
+	got, err := r.SearchTranslationPairs(ctx, "яблони", 10)
+	if err != nil {
+		t.Fatalf("SearchTranslationPairs: %v", err)
+	}
+	if len(got) != 2 || got[0].Original != "Ӏаж" || got[1].Original != "Сад" {
+		t.Fatalf("got %+v, want the gloss hit ranked above the example hit", got)
+	}
+
+	if got, err := r.SearchTranslationPairs(ctx, "елки", 10); err != nil || len(got) != 1 {
+		t.Fatalf("SearchTranslationPairs(елки) = %+v (err %v), want the ё-folded example", got, err)
+	}
+	if got, err := r.SearchTranslationPairs(ctx, "Ӏежаш", 10); err != nil || len(got) != 1 || got[0].Original != "Сад" {
+		t.Fatalf("SearchTranslationPairs(Ӏежаш) = %+v (err %v), want the example spelled «1ежаш»", got, err)
+	}
+
+	// FTS syntax in user text is literal, not a parse error.
+	for _, q := range []string{`яблони OR*`, `"`, `-`, `NEAR(`} {
+		if _, err := r.SearchTranslationPairs(ctx, q, 10); err != nil {
+			t.Fatalf("SearchTranslationPairs(%q): %v", q, err)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/elo"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CreateDuel opens a duel challenged by the user and returns its new opaque
+// ID, hex like a quiz question's so it fits a callback.
+func (r *Repository) CreateDuel(ctx context.Context, challengerID int64, username, firstName string) (string, error) {
+	raw := make([]byte, quizQuestionIDBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(raw)
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO duels (id, challenger_id, challenger_username, challenger_first_name, created_at) VALUES (?, ?, ?, ?, ?);`,
+		id, challengerID, username, firstName, formatReviewTime(time.Now()),
+	)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// GetDuel returns a duel with both sides' progress, or nil when there is no
+// such duel.
+func (r *Repository) GetDuel(ctx context.Context, id string) (*models.Duel, error) {
+	var d models.Duel
+	var opponentID sql.NullInt64
+	var questions, created string
+	var accepted, finished sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, challenger_id, challenger_username, challenger_first_name,
+		        opponent_id, opponent_username, opponent_first_name,
+		        inline_message_id, questions, created_at, accepted_at, finished_at
+		 FROM duels WHERE id = ?;`,
+		id,
+	).Scan(&d.ID, &d.Challenger.UserID, &d.Challenger.Username, &d.Challenger.FirstName,
+		&opponentID, &d.Opponent.Username, &d.Opponent.FirstName,
+		&d.InlineMessageID, &questions, &created, &accepted, &finished)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.Opponent.UserID = opponentID.Int64
+	if questions != "" {
+		if err := json.Unmarshal([]byte(questions), &d.Questions); err != nil {
+			return nil, err
+		}
+	}
+	if d.CreatedAt, err = time.ParseInLocation(reviewTime, created, time.UTC); err != nil {
+		return nil, err
+	}
+	if d.AcceptedAt, err = parseNullTime(accepted); err != nil {
+		return nil, err
+	}
+	if d.FinishedAt, err = parseNullTime(finished); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT user_id, results, correct, finished_at FROM duel_players WHERE duel_id = ?;`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID int64
+		var results string
+		var correct int
+		var done sql.NullString
+		if err := rows.Scan(&userID, &results, &correct, &done); err != nil {
+			return nil, err
+		}
+		p := d.Player(userID)
+		if p == nil {
+			continue
+		}
+		p.Results = nil
+		for _, c := range results {
+			p.Results = append(p.Results, c == '1')
+		}
+		p.Correct, p.Total = correct, len(p.Results)
+		if p.FinishedAt, err = parseNullTime(done); err != nil {
+			return nil, err
+		}
+	}
+	return &d, rows.Err()
+}
+
+// AcceptDuel makes the user the duel's opponent, recording the posted
+// challenge it was accepted from and the questions both will answer. It
+// returns false, changing nothing, when the duel is already accepted or the
+// user is its challenger: the first other tap on «accept» wins.
+func (r *Repository) AcceptDuel(ctx context.Context, id string, opponentID int64, username, firstName, inlineMessageID string, questions []models.QuizQuestion) (bool, error) {
+	raw, err := json.Marshal(questions)
+	if err != nil {
+		return false, err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE duels
+		 SET opponent_id = ?, opponent_username = ?, opponent_first_name = ?,
+		     inline_message_id = ?, questions = ?, accepted_at = ?
+		 WHERE id = ? AND opponent_id IS NULL AND challenger_id != ?;`,
+		opponentID, username, firstName, inlineMessageID, string(raw), formatReviewTime(time.Now()), id, opponentID,
+	)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO duel_players (duel_id, user_id)
+		 SELECT id, challenger_id FROM duels WHERE id = ?
+		 UNION ALL SELECT ?, ?;`,
+		id, id, opponentID,
+	); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// RecordDuelAnswer records the user's answer to question index of the duel,
+// out of questions, finishing their side on the last one. Like
+// RecordDailyAnswer it returns false, recording nothing, unless index is the
+// user's next unanswered question.
+func (r *Repository) RecordDuelAnswer(ctx context.Context, id string, userID int64, index int, correct bool, questions int) (bool, error) {
+	mark, inc := "0", 0
+	if correct {
+		mark, inc = "1", 1
+	}
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE duel_players
+		 SET results = results || ?,
+		     correct = correct + ?,
+		     finished_at = CASE WHEN length(results) + 1 >= ? THEN ? ELSE finished_at END
+		 WHERE duel_id = ? AND user_id = ? AND length(results) = ? AND finished_at IS NULL;`,
+		mark, inc, questions, formatReviewTime(time.Now()), id, userID, index,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// SettleDuel closes a duel both sides have finished and moves their duel
+// ratings by its result: more right answers wins, equal is a draw. It
+// returns nil when the duel is not ready or already settled, so of the two
+// last answers racing, exactly one settles it.
+func (r *Repository) SettleDuel(ctx context.Context, id string) (*models.DuelSettlement, error) {
+	d, err := r.GetDuel(ctx, id)
+	if err != nil || d == nil || d.Opponent.UserID == 0 || d.Challenger.FinishedAt.IsZero() || d.Opponent.FinishedAt.IsZero() {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE duels SET finished_at = ? WHERE id = ? AND finished_at IS NULL;`, formatReviewTime(time.Now()), id)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return nil, err
+	}
+
+	s := &models.DuelSettlement{}
+	s.Challenger.QuizScorer = d.Challenger.QuizScorer
+	s.Opponent.QuizScorer = d.Opponent.QuizScorer
+	for _, p := range []*models.DuelRating{&s.Challenger, &s.Opponent} {
+		err := tx.QueryRowContext(ctx,
+			`SELECT rating, wins, losses, draws FROM duel_ratings WHERE user_id = ?;`, p.UserID,
+		).Scan(&p.Rating, &p.Wins, &p.Losses, &p.Draws)
+		if errors.Is(err, sql.ErrNoRows) {
+			p.Rating = elo.Initial
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	score := elo.Draw
+	switch {
+	case d.Challenger.Correct > d.Opponent.Correct:
+		score = elo.Win
+		s.Challenger.Wins++
+		s.Opponent.Losses++
+	case d.Challenger.Correct < d.Opponent.Correct:
+		score = elo.Loss
+		s.Challenger.Losses++
+		s.Opponent.Wins++
+	default:
+		s.Challenger.Draws++
+		s.Opponent.Draws++
+	}
+	before := s.Challenger.Rating
+	s.Challenger.Rating, s.Opponent.Rating = elo.Update(s.Challenger.Rating, s.Opponent.Rating, score)
+	s.Delta = s.Challenger.Rating - before
+
+	for _, p := range []models.DuelRating{s.Challenger, s.Opponent} {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO duel_ratings (user_id, username, first_name, rating, wins, losses, draws)
+			 VALUES (?, ?, ?, ?, ?, ?, ?)
+			 ON CONFLICT(user_id) DO UPDATE SET
+			     username = excluded.username,
+			     first_name = excluded.first_name,
+			     rating = excluded.rating,
+			     wins = excluded.wins,
+			     losses = excluded.losses,
+			     draws = excluded.draws,
+			     updated_at = CURRENT_TIMESTAMP;`,
+			p.UserID, p.Username, p.FirstName, p.Rating, p.Wins, p.Losses, p.Draws,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return s, tx.Commit()
+}
+
+// TopDuelRatings returns the duel leaderboard, highest rating first.
+func (r *Repository) TopDuelRatings(ctx context.Context, limit int) ([]models.DuelRating, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, username, first_name, rating, wins, losses, draws
+		 FROM duel_ratings
+		 ORDER BY rating DESC, wins DESC, user_id
+		 LIMIT ?;`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ratings []models.DuelRating
+	for rows.Next() {
+		var p models.DuelRating
+		if err := rows.Scan(&p.UserID, &p.Username, &p.FirstName, &p.Rating, &p.Wins, &p.Losses, &p.Draws); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, p)
+	}
+	return ratings, rows.Err()
+}
+
+// GetDuelRating returns the user's duel rating and place among all rated
+// players, or nil when they have not finished a duel. Ties are broken as
+// TopDuelRatings breaks them, so the place is the user's line on the board.
+func (r *Repository) GetDuelRating(ctx context.Context, userID int64) (*models.DuelRating, int, error) {
+	var p models.DuelRating
+	var rank int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id, username, first_name, rating, wins, losses, draws,
+		        (SELECT COUNT(*) FROM duel_ratings o
+		         WHERE o.rating > d.rating
+		            OR (o.rating = d.rating AND o.wins > d.wins)
+		            OR (o.rating = d.rating AND o.wins = d.wins AND o.user_id < d.user_id)) + 1
+		 FROM duel_ratings d WHERE user_id = ?;`,
+		userID,
+	).Scan(&p.UserID, &p.Username, &p.FirstName, &p.Rating, &p.Wins, &p.Losses, &p.Draws, &rank)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return &p, rank, nil
+}
+
+// PruneDuels deletes unsettled duels idle since before cutoff — challenges
+// created before it that nobody accepted, and duels accepted before it that
+// a side abandoned — and returns how many went. An accepted duel is timed
+// from its acceptance, so one taken up late in its challenge's life still
+// has the whole TTL to be played. Settled duels stay: their ratings are
+// already counted.
+func (r *Repository) PruneDuels(ctx context.Context, cutoff time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stale := `SELECT id FROM duels WHERE finished_at IS NULL AND COALESCE(accepted_at, created_at) <= ?`
+	if _, err := tx.ExecContext(ctx, `DELETE FROM duel_players WHERE duel_id IN (`+stale+`);`, formatReviewTime(cutoff)); err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM duels WHERE id IN (`+stale+`);`, formatReviewTime(cutoff))
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
+
+// parseNullTime reads an optional formatReviewTime column; NULL is the zero
+// time.
+func parseNullTime(s sql.NullString) (time.Time, error) {
+	if !s.Valid {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(reviewTime, s.String, time.UTC)
+}
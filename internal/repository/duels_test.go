@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"chetoru/pkg/elo"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDuel_AcceptPlaySettle(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	id, err := r.CreateDuel(ctx, 1, "ali", "Али")
+	if err != nil {
+		t.Fatalf("CreateDuel: %v", err)
+	}
+	questions := []models.QuizQuestion{
+		{Prompt: "цӏа", Options: []string{"дом", "лес"}},
+		{Prompt: "хи", Options: []string{"вода", "огонь"}},
+	}
+	if ok, err := r.AcceptDuel(ctx, id, 1, "ali", "Али", "inl", questions); err != nil || ok {
+		t.Fatalf("AcceptDuel(challenger) = %v, %v; want refused", ok, err)
+	}
+	if ok, err := r.AcceptDuel(ctx, id, 2, "zara", "Зара", "inl", questions); err != nil || !ok {
+		t.Fatalf("AcceptDuel = %v, %v; want accepted", ok, err)
+	}
+	if ok, err := r.AcceptDuel(ctx, id, 3, "musa", "", "inl2", questions); err != nil || ok {
+		t.Fatalf("AcceptDuel(second taker) = %v, %v; want refused", ok, err)
+	}
+
+	answer := func(userID int64, index int, correct, want bool) {
+		t.Helper()
+		if ok, err := r.RecordDuelAnswer(ctx, id, userID, index, correct, len(questions)); err != nil || ok != want {
+			t.Fatalf("RecordDuelAnswer(%d, %d) = %v, %v; want %v", userID, index, ok, err, want)
+		}
+	}
+	answer(1, 0, true, true)
+	answer(1, 0, true, false) // double tap
+	answer(1, 1, true, true)
+	answer(3, 0, true, false) // not in the duel
+
+	if s, err := r.SettleDuel(ctx, id); err != nil || s != nil {
+		t.Fatalf("SettleDuel(one side left) = %+v, %v; want nothing", s, err)
+	}
+	answer(2, 0, true, true)
+	answer(2, 1, false, true)
+
+	d, err := r.GetDuel(ctx, id)
+	if err != nil || d.Opponent.UserID != 2 || d.InlineMessageID != "inl" || len(d.Questions) != 2 {
+		t.Fatalf("GetDuel = %+v, %v", d, err)
+	}
+	if d.Challenger.Correct != 2 || d.Opponent.Correct != 1 || d.Opponent.FinishedAt.IsZero() || !d.FinishedAt.IsZero() {
+		t.Fatalf("duel progress = %+v / %+v", d.Challenger, d.Opponent)
+	}
+
+	s, err := r.SettleDuel(ctx, id)
+	if err != nil || s == nil {
+		t.Fatalf("SettleDuel = %+v, %v; want settled", s, err)
+	}
+	if s.Delta != 16 || s.Challenger.Rating != elo.Initial+16 || s.Opponent.Rating != elo.Initial-16 || s.Challenger.Wins != 1 || s.Opponent.Losses != 1 {
+		t.Fatalf("settlement = %+v", s)
+	}
+	if again, err := r.SettleDuel(ctx, id); err != nil || again != nil {
+		t.Fatalf("second SettleDuel = %+v, %v; want nothing", again, err)
+	}
+
+	top, err := r.TopDuelRatings(ctx, 10)
+	if err != nil || len(top) != 2 || top[0].UserID != 1 || top[0].Username != "ali" {
+		t.Fatalf("TopDuelRatings = %+v, %v", top, err)
+	}
+	if p, rank, err := r.GetDuelRating(ctx, 2); err != nil || p == nil || rank != 2 || p.Rating != elo.Initial-16 {
+		t.Fatalf("GetDuelRating = %+v, %d, %v", p, rank, err)
+	}
+	if p, _, err := r.GetDuelRating(ctx, 3); err != nil || p != nil {
+		t.Fatalf("GetDuelRating(unrated) = %+v, %v; want nil", p, err)
+	}
+}
+
+func TestPruneDuels_KeepsSettled(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	open, err := r.CreateDuel(ctx, 1, "", "")
+	if err != nil {
+		t.Fatalf("CreateDuel: %v", err)
+	}
+	settled, _ := r.CreateDuel(ctx, 1, "", "")
+	q := []models.QuizQuestion{{Prompt: "хи", Options: []string{"вода", "огонь"}}}
+	if ok, err := r.AcceptDuel(ctx, settled, 2, "", "", "inl", q); err != nil || !ok {
+		t.Fatalf("AcceptDuel = %v, %v", ok, err)
+	}
+	r.RecordDuelAnswer(ctx, settled, 1, 0, true, 1)
+	r.RecordDuelAnswer(ctx, settled, 2, 0, true, 1)
+	if s, err := r.SettleDuel(ctx, settled); err != nil || s == nil || s.Delta != 0 || s.Challenger.Draws != 1 {
+		t.Fatalf("SettleDuel(draw) = %+v, %v", s, err)
+	}
+
+	n, err := r.PruneDuels(ctx, time.Now().Add(time.Minute))
+	if err != nil || n != 1 {
+		t.Fatalf("PruneDuels = %d, %v; want the open duel pruned", n, err)
+	}
+	if d, _ := r.GetDuel(ctx, open); d != nil {
+		t.Fatalf("open duel survived pruning")
+	}
+	if d, _ := r.GetDuel(ctx, settled); d == nil || d.Challenger.Correct != 1 {
+		t.Fatalf("settled duel pruned or lost its players: %+v", d)
+	}
+}
+
+func TestPruneDuels_TimesAcceptedDuelsFromAcceptance(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	// Challenged a day ago, accepted a minute before the TTL ran out: both
+	// sides are still playing and the duel must stay.
+	playing, err := r.CreateDuel(ctx, 1, "", "")
+	if err != nil {
+		t.Fatalf("CreateDuel: %v", err)
+	}
+	q := []models.QuizQuestion{{Prompt: "хи", Options: []string{"вода", "огонь"}}}
+	if ok, err := r.AcceptDuel(ctx, playing, 2, "", "", "inl", q); err != nil || !ok {
+		t.Fatalf("AcceptDuel = %v, %v", ok, err)
+	}
+	now := time.Now()
+	if _, err := r.db.Exec(`UPDATE duels SET created_at = ?, accepted_at = ? WHERE id = ?;`,
+		formatReviewTime(now.Add(-25*time.Hour)), formatReviewTime(now.Add(-23*time.Hour)), playing); err != nil {
+		t.Fatal(err)
+	}
+	// Accepted as long ago and abandoned: this one goes.
+	abandoned, _ := r.CreateDuel(ctx, 1, "", "")
+	if ok, err := r.AcceptDuel(ctx, abandoned, 2, "", "", "inl2", q); err != nil || !ok {
+		t.Fatalf("AcceptDuel = %v, %v", ok, err)
+	}
+	if _, err := r.db.Exec(`UPDATE duels SET created_at = ?, accepted_at = ? WHERE id = ?;`,
+		formatReviewTime(now.Add(-50*time.Hour)), formatReviewTime(now.Add(-25*time.Hour)), abandoned); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := r.PruneDuels(ctx, now.Add(-24*time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("PruneDuels = %d, %v; want only the abandoned duel", n, err)
+	}
+	if d, _ := r.GetDuel(ctx, playing); d == nil || d.Opponent.UserID != 2 {
+		t.Fatalf("duel accepted within the TTL was pruned: %+v", d)
+	}
+	if d, _ := r.GetDuel(ctx, abandoned); d != nil {
+		t.Fatal("abandoned duel survived pruning")
+	}
+}
+
+func TestGetDuelRating_RanksTiesAsTheBoard(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	// Three players level on rating: more wins first, then the lower ID.
+	for _, p := range []struct {
+		userID int64
+		wins   int
+	}{{7, 1}, {5, 1}, {9, 2}} {
+		if _, err := r.db.Exec(`INSERT INTO duel_ratings (user_id, username, first_name, rating, wins, losses, draws) VALUES (?, '', '', 1200, ?, ?, 0);`,
+			p.userID, p.wins, p.wins); err != nil {
+			t.Fatal(err)
+		}
+	}
+	top, err := r.TopDuelRatings(ctx, 10)
+	if err != nil || len(top) != 3 {
+		t.Fatalf("TopDuelRatings = %+v, %v", top, err)
+	}
+	for i, p := range top {
+		if _, rank, err := r.GetDuelRating(ctx, p.UserID); err != nil || rank != i+1 {
+			t.Errorf("GetDuelRating(%d) rank = %d, %v; want %d as on the board", p.UserID, rank, err, i+1)
+		}
+	}
+	if top[0].UserID != 9 || top[1].UserID != 5 {
+		t.Errorf("board = %+v, want 9 (more wins), then 5 (lower ID)", top)
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+)
+
+// StoreEntryForms records formsClean as inflected forms of headword and
+// returns how many were new. A paradigm is re-seen on every grammar fetch, so
+// known forms are ignored rather than rewritten.
+func (r *Repository) StoreEntryForms(ctx context.Context, headword, headwordClean string, formsClean []string) (int, error) {
+	if headwordClean == "" || len(formsClean) == 0 {
+		return 0, nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx,
+		`insert or ignore into entry_forms (form_clean, headword, headword_clean) values (?, ?, ?);`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	added := 0
+	for _, form := range formsClean {
+		if form == "" {
+			continue
+		}
+		res, err := stmt.ExecContext(ctx, form, headword, headwordClean)
+		if err != nil {
+			return 0, err
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			added += int(n)
+		}
+	}
+	return added, tx.Commit()
+}
+
+// FindFormHeadwords returns the headwords formClean is an inflected form of,
+// in the order they were learned. A word that is itself an analyzed headword
+// returns nothing: paradigms list the base form too, and «цӏа» must open its
+// own card rather than be read as a form of something else that shares it.
+func (r *Repository) FindFormHeadwords(ctx context.Context, formClean string) ([]string, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`select headword, headword_clean from entry_forms where form_clean = ? order by rowid;`,
+		formClean,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var headword, headwordClean string
+		if err := rows.Scan(&headword, &headwordClean); err != nil {
+			return nil, err
+		}
+		if headwordClean == formClean {
+			return nil, rows.Close()
+		}
+		out = append(out, headword)
+	}
+	return out, rows.Err()
+}
+
+// Headword is a stored Chechen headword and the id of the first pair carrying
+// it, which is the cursor cmd/backfill_forms pages by.
+type Headword struct {
+	ID   int64
+	Word string
+}
+
+// ListChechenHeadwords pages through distinct Chechen headwords stored after
+// afterID.
+func (r *Repository) ListChechenHeadwords(ctx context.Context, afterID int64, limit int) ([]Headword, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	rows, err := r.db.QueryContext(
+		ctx,
+		`select min(id), original_raw
+		from dictionary_pairs
+		where original_lang = 'CHE' and coalesce(entry_type, '') != 'TEXT'
+		group by original_clean
+		having min(id) > ?
+		order by min(id)
+		limit ?;`,
+		afterID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Headword
+	for rows.Next() {
+		var h Headword
+		if err := rows.Scan(&h.ID, &h.Word); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEntryForms_StoreAndResolve(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	n, err := r.StoreEntryForms(ctx, "Дийца", "дийца", []string{"дийца", "дийцира", "дийцина", ""})
+	if err != nil || n != 3 {
+		t.Fatalf("StoreEntryForms = %d, %v; want 3 new forms", n, err)
+	}
+	// A paradigm is seen again on every grammar fetch.
+	if n, err := r.StoreEntryForms(ctx, "Дийца", "дийца", []string{"дийцира"}); err != nil || n != 0 {
+		t.Fatalf("StoreEntryForms again = %d, %v; want nothing new", n, err)
+	}
+
+	got, err := r.FindFormHeadwords(ctx, "дийцира")
+	if err != nil || len(got) != 1 || got[0] != "Дийца" {
+		t.Fatalf("FindFormHeadwords(дийцира) = %v, %v; want [Дийца]", got, err)
+	}
+	if got, err := r.FindFormHeadwords(ctx, "нетформы"); err != nil || got != nil {
+		t.Fatalf("FindFormHeadwords(нетформы) = %v, %v; want nothing", got, err)
+	}
+
+	// «цӏа» is a headword and also appears in another word's paradigm; it must
+	// stay its own word.
+	if _, err := r.StoreEntryForms(ctx, "Цӏе", "цӏе", []string{"цӏе", "цӏа"}); err != nil {
+		t.Fatalf("StoreEntryForms(цӏе): %v", err)
+	}
+	if _, err := r.StoreEntryForms(ctx, "Цӏа", "цӏа", []string{"цӏа", "цӏенош"}); err != nil {
+		t.Fatalf("StoreEntryForms(цӏа): %v", err)
+	}
+	if got, err := r.FindFormHeadwords(ctx, "цӏа"); err != nil || got != nil {
+		t.Fatalf("FindFormHeadwords(цӏа) = %v, %v; want nothing for a headword", got, err)
+	}
+}
+
+func TestListChechenHeadwords_Pages(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	for _, p := range []TranslationPair{
+		{OriginalRaw: "Дийца", OriginalClean: "дийца", OriginalLang: "CHE", TranslationRaw: "рассказать", TranslationClean: "рассказать", TranslationLang: "RUS"},
+		{OriginalRaw: "Дийца", OriginalClean: "дийца", OriginalLang: "CHE", TranslationRaw: "сказать", TranslationClean: "сказать", TranslationLang: "RUS"},
+		{OriginalRaw: "Яблоко", OriginalClean: "яблоко", OriginalLang: "RUS", TranslationRaw: "Ӏаж", TranslationClean: "ӏаж", TranslationLang: "CHE"},
+		{OriginalRaw: "Ӏежан мутта", OriginalClean: "ӏежан мутта", OriginalLang: "CHE", TranslationRaw: "яблочный сок", TranslationClean: "яблочный сок", TranslationLang: "RUS", EntryType: "TEXT"},
+		{OriginalRaw: "Ӏаж", OriginalClean: "ӏаж", OriginalLang: "CHE", TranslationRaw: "яблоко", TranslationClean: "яблоко", TranslationLang: "RUS"},
+	} {
+		p.Source = "api"
+		if _, _, err := r.InsertTranslationPair(ctx, p); err != nil {
+			t.Fatalf("insert %q: %v", p.OriginalRaw, err)
+		}
+	}
+
+	first, err := r.ListChechenHeadwords(ctx, 0, 1)
+	if err != nil || len(first) != 1 || first[0].Word != "Дийца" {
+		t.Fatalf("first page = %+v, %v; want [Дийца]", first, err)
+	}
+	rest, err := r.ListChechenHeadwords(ctx, first[0].ID, 10)
+	if err != nil || len(rest) != 1 || rest[0].Word != "Ӏаж" {
+		t.Fatalf("second page = %+v, %v; want [Ӏаж] without the phrase or the Russian headword", rest, err)
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// insertLookupQuery writes a lookup's detail row unless its user turned
+// /history off; the check rides in the insert so recording stays one
+// statement.
+const insertLookupQuery = `INSERT INTO activity_lookups (activity_id, user_id, query, word, direction, hit, created_at)
+SELECT ?, ?, ?, ?, ?, ?, ?
+WHERE NOT EXISTS (SELECT 1 FROM users WHERE user_id = ? AND history_off = 1);`
+
+// RecordLookupActivity is RecordUserActivity for a text lookup, keeping what
+// was looked up for /history alongside the activity row.
+func (r *Repository) RecordLookupActivity(ctx context.Context, userID int64, username string, activityType models.ActivityType, lookup models.Lookup) error {
+	return r.recordActivity(ctx, userID, username, activityType, &lookup)
+}
+
+// ListLookups returns the user's most recent lookups, newest first, each
+// word once — at its latest lookup.
+func (r *Repository) ListLookups(ctx context.Context, userID int64, limit int) ([]models.Lookup, error) {
+	// SQLite takes the bare columns of an aggregate query from the row max()
+	// picked, so each word comes back as it was last looked up.
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT query, word, direction, hit, created_at, MAX(activity_id) AS latest
+		 FROM activity_lookups
+		 WHERE user_id = ?
+		 GROUP BY word
+		 ORDER BY latest DESC
+		 LIMIT ?;`,
+		userID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lookups []models.Lookup
+	for rows.Next() {
+		var l models.Lookup
+		var at string
+		var latest int64
+		if err := rows.Scan(&l.Query, &l.Word, &l.Direction, &l.Hit, &at, &latest); err != nil {
+			return nil, err
+		}
+		if l.At, err = time.ParseInLocation(reviewTime, at, time.UTC); err != nil {
+			return nil, err
+		}
+		lookups = append(lookups, l)
+	}
+	return lookups, rows.Err()
+}
+
+// ClearLookups deletes the user's lookup history and returns how many
+// lookups went.
+func (r *Repository) ClearLookups(ctx context.Context, userID int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM activity_lookups WHERE user_id = ?;`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// SetHistoryOff turns keeping the user's lookups off or back on. Turning it
+// off also deletes what was kept: off means the bot holds none of it.
+func (r *Repository) SetHistoryOff(ctx context.Context, userID int64, off bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE users SET history_off = ? WHERE user_id = ?;`, off, userID); err != nil {
+		return err
+	}
+	if off {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM activity_lookups WHERE user_id = ?;`, userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// IsHistoryOff reports whether the user turned /history off. An unknown
+// user has it on, as a new one does.
+func (r *Repository) IsHistoryOff(ctx context.Context, userID int64) (bool, error) {
+	var off bool
+	err := r.db.QueryRowContext(ctx, `SELECT history_off FROM users WHERE user_id = ?;`, userID).Scan(&off)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return off, err
+}
+
+// PruneLookups deletes lookups made before cutoff and returns how many went.
+// The activity rows they detail stay: the analytics count those.
+func (r *Repository) PruneLookups(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM activity_lookups WHERE created_at < ?;`, formatReviewTime(cutoff))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
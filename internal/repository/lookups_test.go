@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLookups_ListLatestFirstOncePerWord(t *testing.T) {
+	r, db := newActivityTestRepo(t)
+	ctx := context.Background()
+
+	for _, l := range []models.Lookup{
+		{Query: "Дитт", Word: "дитт", Direction: models.LookupChechenRussian, Hit: true},
+		{Query: "дом", Word: "дом", Direction: models.LookupRussianChechen, Hit: true},
+		{Query: "ахьала", Word: "ахьала"},
+		{Query: "дитт ", Word: "дитт", Direction: models.LookupChechenRussian, Hit: true},
+	} {
+		if err := r.RecordLookupActivity(ctx, 7, "amadi", models.ActivityTypeText, l); err != nil {
+			t.Fatalf("RecordLookupActivity(%q): %v", l.Query, err)
+		}
+	}
+	if err := r.RecordLookupActivity(ctx, 8, "other", models.ActivityTypeText, models.Lookup{Query: "хи", Word: "хи", Hit: true}); err != nil {
+		t.Fatalf("RecordLookupActivity(other): %v", err)
+	}
+
+	got, err := r.ListLookups(ctx, 7, 10)
+	if err != nil {
+		t.Fatalf("ListLookups: %v", err)
+	}
+	want := []models.Lookup{
+		{Query: "дитт ", Word: "дитт", Direction: models.LookupChechenRussian, Hit: true},
+		{Query: "ахьала", Word: "ахьала"},
+		{Query: "дом", Word: "дом", Direction: models.LookupRussianChechen, Hit: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ListLookups = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		g := got[i]
+		g.At = time.Time{}
+		if g != want[i] {
+			t.Errorf("lookup %d = %+v, want %+v", i, g, want[i])
+		}
+	}
+
+	// Lookups are activity too: the analytics still count every one.
+	var activities int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM activity WHERE user_id = 7`).Scan(&activities); err != nil || activities != 4 {
+		t.Fatalf("activities = %d (err %v), want 4", activities, err)
+	}
+}
+
+func TestLookups_HistoryOffKeepsNothing(t *testing.T) {
+	r, db := newActivityTestRepo(t)
+	ctx := context.Background()
+	lookup := models.Lookup{Query: "дитт", Word: "дитт", Hit: true}
+
+	if off, err := r.IsHistoryOff(ctx, 7); err != nil || off {
+		t.Fatalf("IsHistoryOff(new user) = %v, %v; want on", off, err)
+	}
+	if err := r.RecordLookupActivity(ctx, 7, "amadi", models.ActivityTypeText, lookup); err != nil {
+		t.Fatalf("RecordLookupActivity: %v", err)
+	}
+	if err := r.SetHistoryOff(ctx, 7, true); err != nil {
+		t.Fatalf("SetHistoryOff: %v", err)
+	}
+	if off, err := r.IsHistoryOff(ctx, 7); err != nil || !off {
+		t.Fatalf("IsHistoryOff = %v, %v; want off", off, err)
+	}
+	if err := r.RecordLookupActivity(ctx, 7, "amadi", models.ActivityTypeText, lookup); err != nil {
+		t.Fatalf("RecordLookupActivity (off): %v", err)
+	}
+	if got, err := r.ListLookups(ctx, 7, 10); err != nil || len(got) != 0 {
+		t.Fatalf("ListLookups (off) = %+v, %v; want none, old ones deleted too", got, err)
+	}
+	var activities int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM activity WHERE user_id = 7`).Scan(&activities); err != nil || activities != 2 {
+		t.Fatalf("activities = %d (err %v), want 2", activities, err)
+	}
+
+	if err := r.SetHistoryOff(ctx, 7, false); err != nil {
+		t.Fatalf("SetHistoryOff(on): %v", err)
+	}
+	if err := r.RecordLookupActivity(ctx, 7, "amadi", models.ActivityTypeText, lookup); err != nil {
+		t.Fatalf("RecordLookupActivity (on): %v", err)
+	}
+	if n, err := r.ClearLookups(ctx, 7); err != nil || n != 1 {
+		t.Fatalf("ClearLookups = %d, %v; want 1", n, err)
+	}
+}
+
+func TestLookups_Prune(t *testing.T) {
+	r, db := newActivityTestRepo(t)
+	ctx := context.Background()
+
+	for _, w := range []string{"дитт", "хи"} {
+		if err := r.RecordLookupActivity(ctx, 7, "amadi", models.ActivityTypeText, models.Lookup{Query: w, Word: w, Hit: true}); err != nil {
+			t.Fatalf("RecordLookupActivity(%q): %v", w, err)
+		}
+	}
+	old := formatReviewTime(time.Now().Add(-100 * 24 * time.Hour))
+	if _, err := db.Exec(`UPDATE activity_lookups SET created_at = ? WHERE word = 'дитт'`, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := r.PruneLookups(ctx, time.Now().Add(-90*24*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("PruneLookups = %d, %v; want 1", n, err)
+	}
+	got, err := r.ListLookups(ctx, 7, 10)
+	if err != nil || len(got) != 1 || got[0].Word != "хи" {
+		t.Fatalf("ListLookups after prune = %+v, %v; want хи", got, err)
+	}
+}
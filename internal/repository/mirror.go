@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// RefreshTranslationPair stores a pair the mirror crawler has just seen on
+// dosham. A new pair is inserted like any other; a known one gets its entry
+// metadata overwritten and fetched_at bumped, which InsertTranslationPair
+// deliberately never does — the bot re-sees stored pairs on every lookup and
+// cannot afford a write for each, but the crawler exists to keep them current.
+// Moderation state is left alone either way.
+func (r *Repository) RefreshTranslationPair(ctx context.Context, pair TranslationPair) (int64, bool, error) {
+	id, inserted, err := r.InsertTranslationPair(ctx, pair)
+	if err != nil || inserted {
+		return id, inserted, err
+	}
+	_, err = r.db.ExecContext(
+		ctx,
+		`update dictionary_pairs
+		set source_entry_id = coalesce(?, source_entry_id),
+		    source_translation_id = coalesce(?, source_translation_id),
+		    rate = ?,
+		    entry_type = ?,
+		    subtype = ?,
+		    entry_index = ?,
+		    entry_notes = ?,
+		    fetched_at = current_timestamp
+		where id = ?;`,
+		pair.SourceEntryID,
+		pair.SourceTranslationID,
+		pair.Rate,
+		pair.EntryType,
+		pair.Subtype,
+		pair.EntryIndex,
+		pair.EntryNotes,
+		id,
+	)
+	return id, false, err
+}
+
+// FindTranslationPairsContaining is the offline stand-in for dosham's `find`:
+// pairs where either side contains cleanWord, in the orientation they were
+// stored — which is dosham's, headword first — and in insertion order, which
+// for one entry is the order dosham listed its senses. FindTranslationPairs
+// swaps reverse hits and sorts by rate; doing either here would make the same
+// word render differently depending on whether dosham was up.
+//
+// Moderation state is left out on purpose: dosham does not carry it, and a
+// moderated pair ranks ahead of its bucket, so including it would reorder the
+// card the moment dosham went down.
+//
+// Every lookup comes here, so the match runs on dictionary_trigram rather
+// than scanning the table. A trigram needs three letters; a shorter query
+// falls back to instr — not LIKE, since a query is user text and % or _ in
+// it must match themselves.
+func (r *Repository) FindTranslationPairsContaining(ctx context.Context, cleanWord string, limit int) ([]models.TranslationPairs, error) {
+	if cleanWord == "" {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 500
+	}
+
+	from := `from dictionary_trigram f
+		join dictionary_pairs p on p.id = f.rowid
+		where dictionary_trigram match ?`
+	args := []any{`"` + strings.ReplaceAll(cleanWord, `"`, `""`) + `"`, limit}
+	if utf8.RuneCountInString(cleanWord) < 3 {
+		from = `from dictionary_pairs p
+		where (instr(p.original_clean, ?) > 0 or instr(p.translation_clean, ?) > 0)`
+		args = []any{cleanWord, cleanWord, limit}
+	}
+	rows, err := r.db.QueryContext(
+		ctx,
+		`select
+			p.original_raw,
+			p.original_lang,
+			p.translation_raw,
+			p.translation_lang,
+			p.rate,
+			p.entry_type,
+			p.subtype,
+			p.entry_index,
+			p.entry_notes,
+			p.structured_json
+		`+from+`
+		  and (p.formatted_chosen is null or p.formatted_chosen != 'deleted')
+		order by p.id
+		limit ?;`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.TranslationPairs
+	for rows.Next() {
+		var pair models.TranslationPairs
+		var entryType, entryNotes, structured sql.NullString
+		if err := rows.Scan(
+			&pair.Original,
+			&pair.OriginalLang,
+			&pair.Translate,
+			&pair.TranslateLang,
+			&pair.Rate,
+			&entryType,
+			&pair.Subtype,
+			&pair.EntryIndex,
+			&entryNotes,
+			&structured,
+		); err != nil {
+			return nil, err
+		}
+		pair.EntryType = entryType.String
+		pair.Notes = entryNotes.String
+		pair.Structured = structured.String
+		results = append(results, pair)
+	}
+
+	return results, rows.Err()
+}
+
+// ListStaleHeadwords returns distinct headwords whose pairs dosham has not
+// confirmed within maxAge, never-confirmed rows first and then the oldest.
+// The crawler re-queries these to refresh the mirror.
+func (r *Repository) ListStaleHeadwords(ctx context.Context, maxAge time.Duration, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	cutoff := fmt.Sprintf("-%d seconds", int64(maxAge.Seconds()))
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`select original_raw
+		from dictionary_pairs
+		where fetched_at is null or fetched_at < datetime('now', ?)
+		group by original_clean
+		order by min(coalesce(fetched_at, '')), min(id)
+		limit ?;`,
+		cutoff, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			return nil, err
+		}
+		out = append(out, word)
+	}
+	return out, rows.Err()
+}
+
+// MirrorSeed reports whether seed was crawled within maxAge and, if so, how
+// many entries it returned then — the crawler needs the count to decide
+// whether to descend into longer seeds on a resumed run as well.
+func (r *Repository) MirrorSeed(ctx context.Context, seed string, maxAge time.Duration) (entries int, fresh bool, err error) {
+	err = r.db.QueryRowContext(
+		ctx,
+		`select entries from mirror_seeds where seed = ? and crawled_at >= datetime('now', ?);`,
+		seed, fmt.Sprintf("-%d seconds", int64(maxAge.Seconds())),
+	).Scan(&entries)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return entries, err == nil, err
+}
+
+// MarkMirrorSeed records that seed was crawled now and how many entries it
+// returned.
+func (r *Repository) MarkMirrorSeed(ctx context.Context, seed string, entries int) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`insert into mirror_seeds (seed, entries, crawled_at) values (?, ?, current_timestamp)
+		on conflict(seed) do update set entries = excluded.entries, crawled_at = excluded.crawled_at;`,
+		seed, entries,
+	)
+	return err
+}
+
+// RandomTranslationPairs draws up to limit stored Chechen → Russian word
+// pairs at random — the local source's answer to randomEntries, for a bot
+// that has to fill /random and /quiz without dosham.
+func (r *Repository) RandomTranslationPairs(ctx context.Context, limit int) ([]models.TranslationPairs, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.db.QueryContext(
+		ctx,
+		`select original_raw, original_lang, translation_raw, translation_lang, rate, entry_type, subtype
+		from dictionary_pairs
+		where original_lang = 'CHE' and translation_lang = 'RUS'
+		  and coalesce(entry_type, '') != 'TEXT'
+		  and (formatted_chosen is null or formatted_chosen != 'deleted')
+		order by random()
+		limit ?;`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.TranslationPairs
+	for rows.Next() {
+		var pair models.TranslationPairs
+		var entryType sql.NullString
+		if err := rows.Scan(&pair.Original, &pair.OriginalLang, &pair.Translate, &pair.TranslateLang, &pair.Rate, &entryType, &pair.Subtype); err != nil {
+			return nil, err
+		}
+		pair.EntryType = entryType.String
+		results = append(results, pair)
+	}
+	return results, rows.Err()
+}
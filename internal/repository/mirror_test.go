@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestFindTranslationPairsContaining_StoredOrientationAndOrder(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	for _, p := range []TranslationPair{
+		{OriginalRaw: "Яблоня", OriginalClean: "яблоня", OriginalLang: "RUS", TranslationRaw: "Ӏежан дитт", TranslationClean: "ӏежан дитт", TranslationLang: "CHE"},
+		{OriginalRaw: "Ӏаж", OriginalClean: "ӏаж", OriginalLang: "CHE", TranslationRaw: "яблоко", TranslationClean: "яблоко", TranslationLang: "RUS"},
+		{OriginalRaw: "Груша", OriginalClean: "груша", OriginalLang: "RUS", TranslationRaw: "Кхор", TranslationClean: "кхор", TranslationLang: "CHE"},
+		{OriginalRaw: "100%_яблок", OriginalClean: "100%_яблок", OriginalLang: "RUS", TranslationRaw: "x", TranslationClean: "x", TranslationLang: "CHE"},
+	} {
+		p.Source = "api"
+		if _, _, err := r.InsertTranslationPair(ctx, p); err != nil {
+			t.Fatalf("insert %q: %v", p.OriginalRaw, err)
+		}
+	}
+
+	got, err := r.FindTranslationPairsContaining(ctx, "ябло", 10)
+	if err != nil {
+		t.Fatalf("FindTranslationPairsContaining: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d pairs, want 3: %+v", len(got), got)
+	}
+	// Insertion order, and a translation-side hit keeps its headword first —
+	// dosham does not swap, so neither may the mirror.
+	if got[0].Original != "Яблоня" || got[1].Original != "Ӏаж" || got[1].TranslateLang != "RUS" {
+		t.Fatalf("pairs = %+v, want stored order and orientation", got)
+	}
+
+	// dosham matches inside a word, and so must the mirror: «блок» is in
+	// «яблоко» and «100%_яблок», «итт» in «ӏежан дитт».
+	got, err = r.FindTranslationPairsContaining(ctx, "блок", 10)
+	if err != nil || len(got) != 2 || got[0].Original != "Ӏаж" {
+		t.Fatalf("FindTranslationPairsContaining(блок) = %+v (err %v), want the two pairs containing it", got, err)
+	}
+	got, err = r.FindTranslationPairsContaining(ctx, "итт", 10)
+	if err != nil || len(got) != 1 || got[0].Original != "Яблоня" {
+		t.Fatalf("FindTranslationPairsContaining(итт) = %+v (err %v), want the pair glossed ӏежан дитт", got, err)
+	}
+
+	// % and _ are literal characters in a query, not wildcards, and quotes
+	// are no FTS syntax.
+	got, err = r.FindTranslationPairsContaining(ctx, "%_", 10)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("FindTranslationPairsContaining(%%_) = %+v (err %v), want the one literal match", got, err)
+	}
+	got, err = r.FindTranslationPairsContaining(ctx, "0%_я", 10)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("FindTranslationPairsContaining(0%%_я) = %+v (err %v), want the one literal match", got, err)
+	}
+	for _, q := range []string{`кхор" OR "x`, "ябло*"} {
+		if _, err := r.FindTranslationPairsContaining(ctx, q, 10); err != nil {
+			t.Fatalf("FindTranslationPairsContaining(%q): %v", q, err)
+		}
+	}
+}
+
+func TestRefreshTranslationPair_BumpsFreshness(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	pair := TranslationPair{
+		OriginalRaw: "Дитт", OriginalClean: "дитт", OriginalLang: "CHE",
+		TranslationRaw: "Дерево", TranslationClean: "дерево", TranslationLang: "RUS",
+		Source: "api",
+	}
+	id, _, err := r.InsertTranslationPair(ctx, pair)
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	// Age the row past the refresh window, as if fetched long ago.
+	if _, err := r.db.ExecContext(ctx, `update dictionary_pairs set fetched_at = datetime('now', '-40 days') where id = ?`, id); err != nil {
+		t.Fatalf("age row: %v", err)
+	}
+
+	stale, err := r.ListStaleHeadwords(ctx, 30*24*time.Hour, 10)
+	if err != nil || len(stale) != 1 || stale[0] != "Дитт" {
+		t.Fatalf("ListStaleHeadwords = %v (err %v), want [Дитт]", stale, err)
+	}
+
+	pair.Rate, pair.Subtype, pair.EntryNotes = 10000, 2, "и"
+	gotID, inserted, err := r.RefreshTranslationPair(ctx, pair)
+	if err != nil || inserted || gotID != id {
+		t.Fatalf("RefreshTranslationPair = %d, %v, %v; want the existing row", gotID, inserted, err)
+	}
+
+	stale, err = r.ListStaleHeadwords(ctx, 30*24*time.Hour, 10)
+	if err != nil || len(stale) != 0 {
+		t.Fatalf("ListStaleHeadwords after refresh = %v (err %v), want none", stale, err)
+	}
+	var rate, subtype int
+	var notes sql.NullString
+	if err := r.db.QueryRowContext(ctx, `select rate, subtype, entry_notes from dictionary_pairs where id = ?`, id).Scan(&rate, &subtype, &notes); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if rate != 10000 || subtype != 2 || notes.String != "и" {
+		t.Fatalf("metadata = %d/%d/%q, want the refreshed entry", rate, subtype, notes.String)
+	}
+}
+
+func TestMirrorSeeds(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	if _, fresh, err := r.MirrorSeed(ctx, "аб", time.Hour); err != nil || fresh {
+		t.Fatalf("MirrorSeed before marking = %v, %v", fresh, err)
+	}
+	if err := r.MarkMirrorSeed(ctx, "аб", 12); err != nil {
+		t.Fatalf("MarkMirrorSeed: %v", err)
+	}
+	if err := r.MarkMirrorSeed(ctx, "аб", 14); err != nil {
+		t.Fatalf("MarkMirrorSeed again: %v", err)
+	}
+	if n, fresh, err := r.MirrorSeed(ctx, "аб", time.Hour); err != nil || !fresh || n != 14 {
+		t.Fatalf("MirrorSeed after marking = %d, %v, %v; want the latest count", n, fresh, err)
+	}
+}
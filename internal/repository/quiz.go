@@ -169,3 +169,75 @@ func (r *Repository) GetQuizScore(ctx context.Context, userID int64) (correct, t
 	}
 	return correct, total, streak, err
 }
+
+// LogQuizAnswer stores one answered question in quiz_answers. The running
+// totals in quiz_stats are RecordQuizAnswer's; this is the per-word history
+// the totals cannot give.
+func (r *Repository) LogQuizAnswer(ctx context.Context, a models.QuizAnswer) error {
+	var latency sql.NullInt64
+	if a.Latency > 0 {
+		latency = sql.NullInt64{Int64: a.Latency.Milliseconds(), Valid: true}
+	}
+	mode := a.Mode
+	if mode == "" {
+		mode = models.QuizModeQuiz
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO quiz_answers (user_id, prompt, reversed, chosen, correct_option, is_correct, latency_ms, chat_type, mode, kind)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`,
+		a.UserID, a.Prompt, a.Reversed, a.Chosen, a.CorrectOption, a.Correct, latency, a.ChatType, mode, a.Kind,
+	)
+	return err
+}
+
+// HardestQuizWords returns the prompts answered wrong most often across
+// everyone, by error rate. A prompt needs minAttempts answers to qualify, so
+// one unlucky guess does not top the list. Only /quiz translation questions
+// count: /learn repeats a word until it sticks, the other modes ask their own
+// way, and a grammar question's prompt is a form or a phrase to place.
+func (r *Repository) HardestQuizWords(ctx context.Context, minAttempts, limit int) ([]models.WordDifficulty, error) {
+	return r.queryWordDifficulty(ctx,
+		`SELECT prompt, reversed, COUNT(*), SUM(is_correct = 0)
+		 FROM quiz_answers
+		 WHERE mode = ? AND kind = ?
+		 GROUP BY prompt, reversed
+		 HAVING COUNT(*) >= ? AND SUM(is_correct = 0) > 0
+		 ORDER BY SUM(is_correct = 0) * 1.0 / COUNT(*) DESC, COUNT(*) DESC, prompt
+		 LIMIT ?;`,
+		models.QuizModeQuiz, models.QuizKindTranslation, minAttempts, limit,
+	)
+}
+
+// WeakestQuizWords returns the prompts a user has got wrong in /quiz
+// translation questions, most misses first and, among equals, the worst hit
+// rate.
+func (r *Repository) WeakestQuizWords(ctx context.Context, userID int64, limit int) ([]models.WordDifficulty, error) {
+	return r.queryWordDifficulty(ctx,
+		`SELECT prompt, reversed, COUNT(*), SUM(is_correct = 0)
+		 FROM quiz_answers
+		 WHERE user_id = ? AND mode = ? AND kind = ?
+		 GROUP BY prompt, reversed
+		 HAVING SUM(is_correct = 0) > 0
+		 ORDER BY SUM(is_correct = 0) DESC, SUM(is_correct = 0) * 1.0 / COUNT(*) DESC, prompt
+		 LIMIT ?;`,
+		userID, models.QuizModeQuiz, models.QuizKindTranslation, limit,
+	)
+}
+
+func (r *Repository) queryWordDifficulty(ctx context.Context, query string, args ...any) ([]models.WordDifficulty, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var words []models.WordDifficulty
+	for rows.Next() {
+		var w models.WordDifficulty
+		if err := rows.Scan(&w.Prompt, &w.Reversed, &w.Attempts, &w.Wrong); err != nil {
+			return nil, err
+		}
+		words = append(words, w)
+	}
+	return words, rows.Err()
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// RecordChatQuizAnswer adds a poll answer to the scoreboard of the group it
+// was posted in. The global tally is RecordQuizAnswer's; the caller records
+// both.
+func (r *Repository) RecordChatQuizAnswer(ctx context.Context, chatID, userID int64, username, firstName string, correct bool) error {
+	inc := 0
+	if correct {
+		inc = 1
+	}
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO quiz_chat_scores (chat_id, user_id, username, first_name, correct_count, total_count)
+		 VALUES (?, ?, ?, ?, ?, 1)
+		 ON CONFLICT(chat_id, user_id) DO UPDATE SET
+		     username = excluded.username,
+		     first_name = excluded.first_name,
+		     correct_count = quiz_chat_scores.correct_count + excluded.correct_count,
+		     total_count = quiz_chat_scores.total_count + 1,
+		     updated_at = CURRENT_TIMESTAMP;`,
+		chatID, userID, username, firstName, inc,
+	)
+	return err
+}
+
+// TopChatQuizScorers returns a group's own leaderboard, ordered like
+// TopQuizScorers. There is no minimum number of answers: a chat board is
+// small, and one three-round tournament is all many players will have.
+func (r *Repository) TopChatQuizScorers(ctx context.Context, chatID int64, limit int) ([]models.QuizScorer, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, username, first_name, correct_count, total_count
+		 FROM quiz_chat_scores
+		 WHERE chat_id = ?
+		 ORDER BY correct_count DESC, total_count ASC
+		 LIMIT ?;`,
+		chatID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scorers []models.QuizScorer
+	for rows.Next() {
+		var s models.QuizScorer
+		if err := rows.Scan(&s.UserID, &s.Username, &s.FirstName, &s.Correct, &s.Total); err != nil {
+			return nil, err
+		}
+		scorers = append(scorers, s)
+	}
+	return scorers, rows.Err()
+}
+
+// GetChatQuizRank returns the user's position on a group's board and their
+// score there, by TopChatQuizScorers' ordering. rank is 0 for a user who has
+// not answered in the chat.
+func (r *Repository) GetChatQuizRank(ctx context.Context, chatID, userID int64) (rank, correct, total int, err error) {
+	err = r.db.QueryRowContext(ctx,
+		`SELECT (SELECT COUNT(*) FROM quiz_chat_scores o
+		         WHERE o.chat_id = me.chat_id
+		           AND (o.correct_count > me.correct_count
+		                OR (o.correct_count = me.correct_count AND o.total_count < me.total_count))) + 1,
+		        me.correct_count, me.total_count
+		 FROM quiz_chat_scores me
+		 WHERE me.chat_id = ? AND me.user_id = ?;`,
+		chatID, userID,
+	).Scan(&rank, &correct, &total)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, 0, nil
+	}
+	return rank, correct, total, err
+}
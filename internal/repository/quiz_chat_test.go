@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChatQuizScores_KeptPerChat(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	record := func(chatID, userID int64, name string, answers ...bool) {
+		t.Helper()
+		for _, correct := range answers {
+			if err := r.RecordChatQuizAnswer(ctx, chatID, userID, name, "", correct); err != nil {
+				t.Fatalf("RecordChatQuizAnswer: %v", err)
+			}
+		}
+	}
+	record(-1, 1, "ali", true, true, false)
+	record(-1, 2, "zara", true, true)
+	record(-1, 3, "musa", false)
+	record(-2, 1, "ali", true, true, true, true)
+
+	top, err := r.TopChatQuizScorers(ctx, -1, 10)
+	if err != nil {
+		t.Fatalf("TopChatQuizScorers: %v", err)
+	}
+	// Equal correct answers: fewer attempts ranks first.
+	if len(top) != 3 || top[0].Username != "zara" || top[1].Username != "ali" || top[2].Username != "musa" {
+		t.Fatalf("chat -1 board = %+v, want zara, ali, musa", top)
+	}
+	if top[1].Correct != 2 || top[1].Total != 3 {
+		t.Fatalf("ali in chat -1 = %d/%d, want 2/3: another chat's answers leaked in", top[1].Correct, top[1].Total)
+	}
+
+	rank, correct, total, err := r.GetChatQuizRank(ctx, -1, 1)
+	if err != nil || rank != 2 || correct != 2 || total != 3 {
+		t.Fatalf("GetChatQuizRank = %d, %d/%d, %v; want №2 with 2/3", rank, correct, total, err)
+	}
+	if rank, _, _, err := r.GetChatQuizRank(ctx, -2, 3); err != nil || rank != 0 {
+		t.Fatalf("GetChatQuizRank(never answered) = %d, %v; want 0", rank, err)
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// quizQuestionIDBytes is the entropy of a question ID: 64 bits, hex-encoded
+// to 16 characters so the ID fits a callback beside the chosen option and
+// never contains the underscore the callback is split on.
+const quizQuestionIDBytes = 8
+
+const quizQuestionColumns = `id, prompt, reversed, options, correct_idx, review_id, subtype, rate, saved, kind, hint, chat_id, chat_type, created_at`
+
+// SaveQuizQuestion stores a question about to be sent to chatID and returns
+// its new opaque ID. The question can be answered until ttl has passed.
+func (r *Repository) SaveQuizQuestion(ctx context.Context, q models.QuizQuestion, chatID int64, chatType string, ttl time.Duration) (string, error) {
+	raw := make([]byte, quizQuestionIDBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(raw)
+	options, err := json.Marshal(q.Options)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO quiz_questions (id, prompt, reversed, options, correct_idx, review_id, subtype, rate, saved, kind, hint, chat_id, chat_type, created_at, expires_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`,
+		id, q.Prompt, q.Reversed, string(options), q.CorrectIdx, q.ReviewID, q.Filter.Subtype, q.Filter.Rate, q.Filter.Saved, q.Kind, q.Hint, chatID, chatType,
+		formatReviewTime(now), formatReviewTime(now.Add(ttl)),
+	)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// AttachQuizPoll records the Telegram poll a stored question was sent as,
+// since poll answers arrive keyed by the poll, not by the question.
+func (r *Repository) AttachQuizPoll(ctx context.Context, id, pollID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE quiz_questions SET poll_id = ? WHERE id = ?;`, pollID, id)
+	return err
+}
+
+// GetQuizQuestion returns a stored question by ID, or nil when there is no
+// such question or it has expired.
+func (r *Repository) GetQuizQuestion(ctx context.Context, id string) (*models.StoredQuiz, error) {
+	return r.getQuizQuestion(ctx, `id = ?`, id)
+}
+
+// GetQuizQuestionByPoll returns the stored question a poll was sent as, or
+// nil when the poll is not one of ours or its question has expired.
+func (r *Repository) GetQuizQuestionByPoll(ctx context.Context, pollID string) (*models.StoredQuiz, error) {
+	return r.getQuizQuestion(ctx, `poll_id = ?`, pollID)
+}
+
+func (r *Repository) getQuizQuestion(ctx context.Context, where string, arg any) (*models.StoredQuiz, error) {
+	var q models.StoredQuiz
+	var options, created string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT `+quizQuestionColumns+` FROM quiz_questions WHERE `+where+` AND expires_at > ?;`,
+		arg, formatReviewTime(time.Now()),
+	).Scan(&q.ID, &q.Prompt, &q.Reversed, &options, &q.CorrectIdx, &q.ReviewID,
+		&q.Filter.Subtype, &q.Filter.Rate, &q.Filter.Saved, &q.Kind, &q.Hint, &q.ChatID, &q.ChatType, &created)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(options), &q.Options); err != nil {
+		return nil, err
+	}
+	// Grammar questions are all a grammar filter draws, so the kind
+	// stores that part of the filter.
+	q.Filter.Grammar = q.Kind != models.QuizKindTranslation
+	if q.SentAt, err = time.ParseInLocation(reviewTime, created, time.UTC); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// ClaimQuizAnswer reserves the user's one answer to a question. It returns
+// false when the user has already answered it, and the answer must not count
+// again.
+func (r *Repository) ClaimQuizAnswer(ctx context.Context, questionID string, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO quiz_question_answers (question_id, user_id) VALUES (?, ?);`,
+		questionID, userID,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// PruneQuizQuestions deletes questions expired by now, with their answer
+// claims, and returns how many questions went.
+func (r *Repository) PruneQuizQuestions(ctx context.Context, now time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	cutoff := formatReviewTime(now)
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM quiz_question_answers
+		 WHERE question_id IN (SELECT id FROM quiz_questions WHERE expires_at <= ?);`,
+		cutoff,
+	); err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM quiz_questions WHERE expires_at <= ?;`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return n, tx.Commit()
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuizQuestions_StoreClaimAndPrune(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	q := models.QuizQuestion{Prompt: "дитт", Options: []string{"дом", "дерево", "вода", "огонь"}, CorrectIdx: 1, ReviewID: 3,
+		Filter: models.QuizFilter{Subtype: 2, Rate: 10000, Grammar: true}, Kind: models.QuizKindPhrase, Hint: "идёт дождь"}
+	id, err := r.SaveQuizQuestion(ctx, q, -100, "group", time.Hour)
+	if err != nil {
+		t.Fatalf("SaveQuizQuestion: %v", err)
+	}
+	if len(id) != 2*quizQuestionIDBytes || strings.Contains(id, "_") {
+		t.Fatalf("id = %q, want %d hex characters", id, 2*quizQuestionIDBytes)
+	}
+
+	got, err := r.GetQuizQuestion(ctx, id)
+	if err != nil || got == nil {
+		t.Fatalf("GetQuizQuestion = %+v, %v; want the stored question", got, err)
+	}
+	if got.Prompt != q.Prompt || got.CorrectIdx != 1 || got.ReviewID != 3 || got.ChatID != -100 || got.ChatType != "group" || got.Filter != q.Filter || got.Kind != q.Kind || got.Hint != q.Hint ||
+		strings.Join(got.Options, ",") != "дом,дерево,вода,огонь" || got.SentAt.IsZero() {
+		t.Fatalf("GetQuizQuestion = %+v, want the question as saved", got)
+	}
+	if missing, err := r.GetQuizQuestion(ctx, "0000000000000000"); err != nil || missing != nil {
+		t.Fatalf("GetQuizQuestion(unknown) = %+v, %v; want nothing", missing, err)
+	}
+
+	if err := r.AttachQuizPoll(ctx, id, "poll-1"); err != nil {
+		t.Fatalf("AttachQuizPoll: %v", err)
+	}
+	if byPoll, err := r.GetQuizQuestionByPoll(ctx, "poll-1"); err != nil || byPoll == nil || byPoll.ID != id {
+		t.Fatalf("GetQuizQuestionByPoll = %+v, %v; want question %s", byPoll, err, id)
+	}
+
+	// Only each user's first answer counts.
+	if first, err := r.ClaimQuizAnswer(ctx, id, 7); err != nil || !first {
+		t.Fatalf("ClaimQuizAnswer = %v, %v; want the first answer", first, err)
+	}
+	if first, err := r.ClaimQuizAnswer(ctx, id, 7); err != nil || first {
+		t.Fatalf("ClaimQuizAnswer again = %v, %v; want it refused", first, err)
+	}
+	if first, err := r.ClaimQuizAnswer(ctx, id, 8); err != nil || !first {
+		t.Fatalf("ClaimQuizAnswer by another user = %v, %v; want it counted", first, err)
+	}
+
+	// An expired question is gone for grading at once and from the table
+	// once pruned.
+	stale, err := r.SaveQuizQuestion(ctx, q, -100, "group", -time.Minute)
+	if err != nil {
+		t.Fatalf("SaveQuizQuestion (stale): %v", err)
+	}
+	if got, err := r.GetQuizQuestion(ctx, stale); err != nil || got != nil {
+		t.Fatalf("GetQuizQuestion(expired) = %+v, %v; want nothing", got, err)
+	}
+	if _, err := r.ClaimQuizAnswer(ctx, stale, 7); err != nil {
+		t.Fatalf("ClaimQuizAnswer (stale): %v", err)
+	}
+	if n, err := r.PruneQuizQuestions(ctx, time.Now()); err != nil || n != 1 {
+		t.Fatalf("PruneQuizQuestions = %d, %v; want the one expired question", n, err)
+	}
+	var claims int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM quiz_question_answers WHERE question_id = ?;`, stale).Scan(&claims); err != nil || claims != 0 {
+		t.Fatalf("claims left on a pruned question = %d, %v; want 0", claims, err)
+	}
+	if got, err := r.GetQuizQuestion(ctx, id); err != nil || got == nil {
+		t.Fatalf("GetQuizQuestion after prune = %+v, %v; want the live question kept", got, err)
+	}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// seasonMinAnswers is the answers a player needs in a season to be ranked in
+// it, the same bar TopQuizScorers sets for the lifetime board.
+const seasonMinAnswers = 3
+
+// TopQuizScorersBetween ranks players by correct /quiz answers given in
+// [from, to), ordered like TopQuizScorers. It reads the answer log, not the
+// running totals, so a newcomer starts each week level with everyone else.
+// Only /quiz and /write answers count: /learn reviews and questions on the
+// user's own saved words are left out as they are from the lifetime board.
+// A negative limit returns every ranked player.
+func (r *Repository) TopQuizScorersBetween(ctx context.Context, from, to time.Time, limit int) ([]models.QuizScorer, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT a.user_id, COALESCE(s.username, ''), COALESCE(s.first_name, ''), SUM(a.is_correct), COUNT(*)
+		 FROM quiz_answers a
+		 LEFT JOIN quiz_stats s ON s.user_id = a.user_id
+		 WHERE a.mode IN (?, ?) AND a.answered_at >= ? AND a.answered_at < ?
+		 GROUP BY a.user_id
+		 HAVING COUNT(*) >= ?
+		 ORDER BY SUM(a.is_correct) DESC, COUNT(*) ASC, a.user_id
+		 LIMIT ?;`,
+		models.QuizModeQuiz, models.QuizModeWrite, formatReviewTime(from), formatReviewTime(to), seasonMinAnswers, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scorers []models.QuizScorer
+	for rows.Next() {
+		var s models.QuizScorer
+		if err := rows.Scan(&s.UserID, &s.Username, &s.FirstName, &s.Correct, &s.Total); err != nil {
+			return nil, err
+		}
+		scorers = append(scorers, s)
+	}
+	return scorers, rows.Err()
+}
+
+// GetQuizRankBetween returns the user's position on the [from, to) board and
+// their score there. rank is 0 for a user not ranked in the window.
+func (r *Repository) GetQuizRankBetween(ctx context.Context, userID int64, from, to time.Time) (rank, correct, total int, err error) {
+	err = r.db.QueryRowContext(ctx,
+		`WITH board AS (
+		     SELECT user_id, SUM(is_correct) AS correct, COUNT(*) AS total
+		     FROM quiz_answers
+		     WHERE mode IN (?, ?) AND answered_at >= ? AND answered_at < ?
+		     GROUP BY user_id
+		     HAVING COUNT(*) >= ?
+		 )
+		 SELECT (SELECT COUNT(*) FROM board o
+		         WHERE o.correct > me.correct
+		            OR (o.correct = me.correct AND o.total < me.total)
+		            OR (o.correct = me.correct AND o.total = me.total AND o.user_id < me.user_id)) + 1,
+		        me.correct, me.total
+		 FROM board me
+		 WHERE me.user_id = ?;`,
+		models.QuizModeQuiz, models.QuizModeWrite, formatReviewTime(from), formatReviewTime(to), seasonMinAnswers, userID,
+	).Scan(&rank, &correct, &total)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, 0, nil
+	}
+	return rank, correct, total, err
+}
+
+// CloseQuizSeason archives a finished season: how many players it ranked and
+// its podium, winners in rank order. It returns false, writing nothing, when
+// the season was closed before — so whoever gets true announces the results,
+// and only once.
+func (r *Repository) CloseQuizSeason(ctx context.Context, season models.QuizSeason, players int, winners []models.QuizScorer) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`INSERT OR IGNORE INTO quiz_seasons (period, season, starts_at, ends_at, players) VALUES (?, ?, ?, ?, ?);`,
+		season.Period, season.Key, formatReviewTime(season.Start), formatReviewTime(season.End), players,
+	)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	for i, w := range winners {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO quiz_season_winners (period, season, rank, user_id, username, first_name, correct_count, total_count)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?);`,
+			season.Period, season.Key, i+1, w.UserID, w.Username, w.FirstName, w.Correct, w.Total,
+		); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
+
+// ListSeasonWinners returns the podiums of the last seasons closed in
+// period, newest season first and each podium in rank order.
+func (r *Repository) ListSeasonWinners(ctx context.Context, period string, seasons int) ([]models.SeasonWinner, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT w.period, w.season, w.rank, w.user_id, w.username, w.first_name, w.correct_count, w.total_count
+		 FROM quiz_season_winners w
+		 WHERE w.period = ? AND w.season IN (
+		     SELECT season FROM quiz_seasons WHERE period = ? ORDER BY starts_at DESC LIMIT ?
+		 )
+		 ORDER BY w.season DESC, w.rank;`,
+		period, period, seasons,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var winners []models.SeasonWinner
+	for rows.Next() {
+		var w models.SeasonWinner
+		if err := rows.Scan(&w.Period, &w.Season, &w.Rank, &w.UserID, &w.Username, &w.FirstName, &w.Correct, &w.Total); err != nil {
+			return nil, err
+		}
+		winners = append(winners, w)
+	}
+	return winners, rows.Err()
+}
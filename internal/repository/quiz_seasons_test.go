@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQuizSeasons_RankWindowAndCloseOnce(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	week := models.SeasonAt(models.SeasonWeek, time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC))
+	answer := func(userID int64, at time.Time, mode string, correct ...bool) {
+		t.Helper()
+		for _, c := range correct {
+			if err := r.LogQuizAnswer(ctx, models.QuizAnswer{UserID: userID, Prompt: "дог", Chosen: "x", CorrectOption: "x", Correct: c, Mode: mode}); err != nil {
+				t.Fatalf("LogQuizAnswer: %v", err)
+			}
+			if _, err := r.db.Exec(`UPDATE quiz_answers SET answered_at = ? WHERE id = last_insert_rowid();`, formatReviewTime(at)); err != nil {
+				t.Fatalf("set answered_at: %v", err)
+			}
+		}
+	}
+	inWeek := week.Start.Add(time.Hour)
+	// A veteran with a lifetime of answers before the week starts level.
+	answer(1, week.Start.Add(-time.Hour), models.QuizModeQuiz, true, true, true, true, true)
+	answer(1, inWeek, models.QuizModeQuiz, true, false, false)
+	answer(2, inWeek, models.QuizModeQuiz, true, true, true)
+	answer(3, inWeek, models.QuizModeQuiz, true, true) // below the bar
+	answer(4, inWeek, models.QuizModeLearn, true, true, true)
+	answer(6, inWeek, models.QuizModeSaved, true, true, true, true) // own saved words
+	answer(5, week.End, models.QuizModeQuiz, true, true, true)      // next week
+	answer(7, inWeek, models.QuizModeWrite, true, true, true)       // typed, as /quiz counts
+
+	top, err := r.TopQuizScorersBetween(ctx, week.Start, week.End, -1)
+	if err != nil {
+		t.Fatalf("TopQuizScorersBetween: %v", err)
+	}
+	if len(top) != 3 || top[0].UserID != 2 || top[1].UserID != 7 || top[2].UserID != 1 || top[2].Correct != 1 || top[2].Total != 3 {
+		t.Fatalf("week board = %+v, want the newcomers 3/3 above the veteran's 1/3 and nobody else", top)
+	}
+	if rank, correct, total, err := r.GetQuizRankBetween(ctx, 1, week.Start, week.End); err != nil || rank != 3 || correct != 1 || total != 3 {
+		t.Fatalf("GetQuizRankBetween = %d, %d/%d, %v; want №3 with 1/3", rank, correct, total, err)
+	}
+	if rank, _, _, err := r.GetQuizRankBetween(ctx, 3, week.Start, week.End); err != nil || rank != 0 {
+		t.Fatalf("GetQuizRankBetween(below the bar) = %d, %v; want unranked", rank, err)
+	}
+	if rank, _, _, err := r.GetQuizRankBetween(ctx, 6, week.Start, week.End); err != nil || rank != 0 {
+		t.Fatalf("GetQuizRankBetween(saved-words drill) = %d, %v; want unranked", rank, err)
+	}
+
+	closed, err := r.CloseQuizSeason(ctx, week, len(top), top)
+	if err != nil || !closed {
+		t.Fatalf("CloseQuizSeason = %v, %v; want the season closed", closed, err)
+	}
+	if again, err := r.CloseQuizSeason(ctx, week, len(top), top); err != nil || again {
+		t.Fatalf("CloseQuizSeason again = %v, %v; want it refused", again, err)
+	}
+	winners, err := r.ListSeasonWinners(ctx, models.SeasonWeek, 5)
+	if err != nil {
+		t.Fatalf("ListSeasonWinners: %v", err)
+	}
+	if len(winners) != 3 || winners[0].Season != week.Key || winners[0].Rank != 1 || winners[0].UserID != 2 || winners[1].Rank != 2 {
+		t.Fatalf("winners = %+v, want the week's podium in rank order", winners)
+	}
+	if months, err := r.ListSeasonWinners(ctx, models.SeasonMonth, 5); err != nil || len(months) != 0 {
+		t.Fatalf("month winners = %+v, %v; want none", months, err)
+	}
+}
+
+func TestSeasonAt(t *testing.T) {
+	wed := time.Date(2026, 10, 14, 23, 30, 0, 0, time.UTC)
+	week := models.SeasonAt(models.SeasonWeek, wed)
+	if week.Key != "2026-W42" || week.Start != time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC) || week.End != time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC) {
+		t.Fatalf("week = %+v, want Monday 12 to Monday 19 October, W42", week)
+	}
+	if sunday := models.SeasonAt(models.SeasonWeek, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)); sunday.Key != week.Key {
+		t.Fatalf("Sunday falls in %s, want %s", sunday.Key, week.Key)
+	}
+	if prev := week.Previous(); prev.Key != "2026-W41" || prev.End != week.Start {
+		t.Fatalf("previous week = %+v", prev)
+	}
+	month := models.SeasonAt(models.SeasonMonth, wed)
+	if month.Key != "2026-10" || month.Previous().Key != "2026-09" || month.End != time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC) {
+		t.Fatalf("month = %+v, previous %+v", month, month.Previous())
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"chetoru/internal/models"
 	"context"
 	"database/sql"
 	"testing"
@@ -272,3 +273,82 @@ func TestQuizStreak(t *testing.T) {
 		t.Fatalf("streak after gap = %d, want 1", streak)
 	}
 }
+
+func TestQuizAnswers_HardestAndWeakestWords(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	answer := func(userID int64, prompt string, correct bool) {
+		t.Helper()
+		chosen := "дерево"
+		if !correct {
+			chosen = "дом"
+		}
+		if err := r.LogQuizAnswer(ctx, models.QuizAnswer{
+			UserID: userID, Prompt: prompt, Chosen: chosen, CorrectOption: "дерево",
+			Correct: correct, Latency: 1500 * time.Millisecond, ChatType: "private",
+		}); err != nil {
+			t.Fatalf("LogQuizAnswer: %v", err)
+		}
+	}
+	answer(1, "дитт", false)
+	answer(2, "дитт", false)
+	answer(3, "дитт", true) // 2 of 3 wrong
+	answer(1, "цӏа", false)
+	answer(2, "цӏа", true)
+	answer(3, "цӏа", true) // 1 of 3 wrong
+	answer(1, "хи", false) // one answer: below the bar
+	answer(2, "ӏаж", true)
+	answer(3, "ӏаж", true)
+	answer(1, "ӏаж", true) // never missed
+	// /learn misses repeat a word on purpose: they make neither list.
+	for _, userID := range []int64{1, 2, 3} {
+		if err := r.LogQuizAnswer(ctx, models.QuizAnswer{
+			UserID: userID, Prompt: "ӏаж", Chosen: "дом", CorrectOption: "яблоко", Mode: models.QuizModeLearn,
+		}); err != nil {
+			t.Fatalf("LogQuizAnswer (learn): %v", err)
+		}
+	}
+	// A typed /write answer tests spelling, not recognition.
+	if err := r.LogQuizAnswer(ctx, models.QuizAnswer{
+		UserID: 3, Prompt: "яблоко", Reversed: true, Chosen: "ӏеж", CorrectOption: "ӏаж", Mode: models.QuizModeWrite,
+	}); err != nil {
+		t.Fatalf("LogQuizAnswer (write): %v", err)
+	}
+	// Nor do grammar questions: «which is a form of ӏаж» asks no translation.
+	for _, userID := range []int64{1, 2, 3} {
+		if err := r.LogQuizAnswer(ctx, models.QuizAnswer{
+			UserID: userID, Prompt: "ӏаж", Chosen: "ӏежан", CorrectOption: "ӏажа", Kind: models.QuizKindForm,
+		}); err != nil {
+			t.Fatalf("LogQuizAnswer (form): %v", err)
+		}
+	}
+
+	hardest, err := r.HardestQuizWords(ctx, 3, 10)
+	if err != nil {
+		t.Fatalf("HardestQuizWords: %v", err)
+	}
+	if len(hardest) != 2 || hardest[0].Prompt != "дитт" || hardest[0].Wrong != 2 || hardest[0].Attempts != 3 || hardest[1].Prompt != "цӏа" {
+		t.Fatalf("HardestQuizWords = %+v, want дитт (2/3) then цӏа", hardest)
+	}
+
+	weakest, err := r.WeakestQuizWords(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("WeakestQuizWords: %v", err)
+	}
+	if len(weakest) != 3 {
+		t.Fatalf("WeakestQuizWords = %+v, want the three words user 1 missed", weakest)
+	}
+	if got, err := r.WeakestQuizWords(ctx, 3, 10); err != nil || len(got) != 0 {
+		t.Fatalf("WeakestQuizWords(3) = %+v, %v; want none for a user who never missed", got, err)
+	}
+
+	var latency int
+	var mode string
+	if err := r.db.QueryRowContext(ctx, `SELECT latency_ms, mode FROM quiz_answers LIMIT 1`).Scan(&latency, &mode); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if latency != 1500 || mode != models.QuizModeQuiz {
+		t.Fatalf("stored latency/mode = %d/%q, want 1500/quiz", latency, mode)
+	}
+}
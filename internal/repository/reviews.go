@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// reviewTime is how word_reviews stores due_at: UTC, in a layout that sorts
+// and compares as text.
+const reviewTime = time.DateTime
+
+func formatReviewTime(t time.Time) string {
+	return t.UTC().Format(reviewTime)
+}
+
+const wordReviewColumns = `id, user_id, headword, headword_clean, russian, ease, interval_days, repetitions, lapses, due_at`
+
+func scanWordReview(row interface{ Scan(...any) error }) (*models.WordReview, error) {
+	var r models.WordReview
+	var due string
+	if err := row.Scan(&r.ID, &r.UserID, &r.Headword, &r.HeadwordClean, &r.Russian,
+		&r.Ease, &r.IntervalDays, &r.Repetitions, &r.Lapses, &due); err != nil {
+		return nil, err
+	}
+	t, err := time.ParseInLocation(reviewTime, due, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+	r.DueAt = t
+	return &r, nil
+}
+
+// AddWordReview puts a word into the user's /learn deck, due at r.DueAt, and
+// returns its row ID. introducedOn is the local date counted against the
+// daily cap on new words. added is false when the deck already held the
+// word; the existing row is left alone and its ID returned.
+func (r *Repository) AddWordReview(ctx context.Context, review models.WordReview, introducedOn string) (id int64, added bool, err error) {
+	res, err := r.db.ExecContext(ctx,
+		`insert or ignore into word_reviews
+		 (user_id, headword, headword_clean, russian, ease, interval_days, repetitions, lapses, due_at, introduced_on)
+		 values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		review.UserID, review.Headword, review.HeadwordClean, review.Russian,
+		review.Ease, review.IntervalDays, review.Repetitions, review.Lapses,
+		formatReviewTime(review.DueAt), introducedOn,
+	)
+	if err != nil {
+		return 0, false, err
+	}
+	if n, _ := res.RowsAffected(); n == 1 {
+		id, err = res.LastInsertId()
+		return id, true, err
+	}
+	err = r.db.QueryRowContext(ctx,
+		`select id from word_reviews where user_id = ? and headword_clean = ?`,
+		review.UserID, review.HeadwordClean,
+	).Scan(&id)
+	return id, false, err
+}
+
+// HasWordReview reports whether the word is already in the user's deck.
+func (r *Repository) HasWordReview(ctx context.Context, userID int64, headwordClean string) (bool, error) {
+	var one int
+	err := r.db.QueryRowContext(ctx,
+		`select 1 from word_reviews where user_id = ? and headword_clean = ?`,
+		userID, headwordClean,
+	).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// NextDueWordReview returns the user's most overdue card, or nil when nothing
+// is due at now.
+func (r *Repository) NextDueWordReview(ctx context.Context, userID int64, now time.Time) (*models.WordReview, error) {
+	review, err := scanWordReview(r.db.QueryRowContext(ctx,
+		`select `+wordReviewColumns+` from word_reviews
+		 where user_id = ? and due_at <= ?
+		 order by due_at, id
+		 limit 1`,
+		userID, formatReviewTime(now),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return review, err
+}
+
+// GetWordReview returns one of the user's cards by ID, or nil when the user
+// has no such card — an ID from someone else's deck included.
+func (r *Repository) GetWordReview(ctx context.Context, userID, id int64) (*models.WordReview, error) {
+	review, err := scanWordReview(r.db.QueryRowContext(ctx,
+		`select `+wordReviewColumns+` from word_reviews where id = ? and user_id = ?`,
+		id, userID,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return review, err
+}
+
+// UpdateWordReview stores a graded card's new schedule. Only a card that was
+// due at now is updated, so the second of two taps on the same answer grades
+// nothing: the first one has already moved the card into the future.
+func (r *Repository) UpdateWordReview(ctx context.Context, review models.WordReview, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`update word_reviews
+		 set ease = ?, interval_days = ?, repetitions = ?, lapses = ?, due_at = ?, reviewed_at = current_timestamp
+		 where id = ? and user_id = ? and due_at <= ?`,
+		review.Ease, review.IntervalDays, review.Repetitions, review.Lapses, formatReviewTime(review.DueAt),
+		review.ID, review.UserID, formatReviewTime(now),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// CountNewWordReviews returns how many words entered the user's deck on day.
+func (r *Repository) CountNewWordReviews(ctx context.Context, userID int64, day string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx,
+		`select count(*) from word_reviews where user_id = ? and introduced_on = ?`,
+		userID, day,
+	).Scan(&n)
+	return n, err
+}
+
+// CountDueWordReviews returns how many of the user's cards are due at now,
+// and how many the deck holds in all.
+func (r *Repository) CountDueWordReviews(ctx context.Context, userID int64, now time.Time) (due, total int, err error) {
+	err = r.db.QueryRowContext(ctx,
+		`select coalesce(sum(due_at <= ?), 0), count(*) from word_reviews where user_id = ?`,
+		formatReviewTime(now), userID,
+	).Scan(&due, &total)
+	return due, total, err
+}
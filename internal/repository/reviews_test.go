@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWordReviews_DeckLifecycle(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	card := models.WordReview{UserID: 7, Headword: "Дитт", HeadwordClean: "дитт", Russian: "дерево", Ease: 2.5, DueAt: now}
+	id, added, err := r.AddWordReview(ctx, card, "2026-10-16")
+	if err != nil || !added || id == 0 {
+		t.Fatalf("AddWordReview = %d, %v, %v; want a new row", id, added, err)
+	}
+	again, added, err := r.AddWordReview(ctx, card, "2026-10-16")
+	if err != nil || added || again != id {
+		t.Fatalf("AddWordReview again = %d, %v, %v; want the existing row %d", again, added, err, id)
+	}
+	if n, err := r.CountNewWordReviews(ctx, 7, "2026-10-16"); err != nil || n != 1 {
+		t.Fatalf("CountNewWordReviews = %d, %v; want 1", n, err)
+	}
+	if has, err := r.HasWordReview(ctx, 7, "дитт"); err != nil || !has {
+		t.Fatalf("HasWordReview = %v, %v", has, err)
+	}
+
+	due, err := r.NextDueWordReview(ctx, 7, now)
+	if err != nil || due == nil || due.ID != id || !due.DueAt.Equal(now) {
+		t.Fatalf("NextDueWordReview = %+v, %v; want the new card due now", due, err)
+	}
+	if other, err := r.GetWordReview(ctx, 8, id); err != nil || other != nil {
+		t.Fatalf("GetWordReview for another user = %+v, %v; want nothing", other, err)
+	}
+
+	graded := *due
+	graded.Repetitions, graded.IntervalDays, graded.DueAt = 1, 1, now.AddDate(0, 0, 1)
+	if ok, err := r.UpdateWordReview(ctx, graded, now); err != nil || !ok {
+		t.Fatalf("UpdateWordReview = %v, %v; want the due card updated", ok, err)
+	}
+	// A second tap on the same answer finds the card no longer due.
+	if ok, err := r.UpdateWordReview(ctx, graded, now); err != nil || ok {
+		t.Fatalf("UpdateWordReview twice = %v, %v; want no second grade", ok, err)
+	}
+	if due, err := r.NextDueWordReview(ctx, 7, now); err != nil || due != nil {
+		t.Fatalf("NextDueWordReview after grading = %+v, %v; want nothing due", due, err)
+	}
+
+	if due, total, err := r.CountDueWordReviews(ctx, 7, now); err != nil || due != 0 || total != 1 {
+		t.Fatalf("CountDueWordReviews now = %d/%d, %v; want 0/1", due, total, err)
+	}
+	if due, total, err := r.CountDueWordReviews(ctx, 7, now.AddDate(0, 0, 2)); err != nil || due != 1 || total != 1 {
+		t.Fatalf("CountDueWordReviews in two days = %d/%d, %v; want 1/1", due, total, err)
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const savedWordColumns = `id, user_id, headword, headword_clean, russian, saved_at`
+
+// SaveWord adds a word to the user's saved words. It returns false, changing
+// nothing, when the list already holds the word.
+func (r *Repository) SaveWord(ctx context.Context, w models.SavedWord) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`insert or ignore into saved_words (user_id, headword, headword_clean, russian, saved_at)
+		 values (?, ?, ?, ?, ?)`,
+		w.UserID, w.Headword, w.HeadwordClean, w.Russian, formatReviewTime(time.Now()),
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// ListSavedWords returns a page of the user's saved words, newest first, and
+// how many they have saved in all.
+func (r *Repository) ListSavedWords(ctx context.Context, userID int64, offset, limit int) ([]models.SavedWord, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `select count(*) from saved_words where user_id = ?`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	words, err := r.querySavedWords(ctx,
+		`select `+savedWordColumns+` from saved_words
+		 where user_id = ?
+		 order by id desc
+		 limit ? offset ?`,
+		userID, limit, offset,
+	)
+	return words, total, err
+}
+
+// DeleteSavedWord removes one of the user's saved words by ID. It returns
+// false when the user has no such word — an ID from someone else's list
+// included. The word's /learn card, if any, stays in the deck.
+func (r *Repository) DeleteSavedWord(ctx context.Context, userID, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `delete from saved_words where id = ? and user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// RandomSavedWord returns one of the user's saved words at random, or nil
+// when they have saved none.
+func (r *Repository) RandomSavedWord(ctx context.Context, userID int64) (*models.SavedWord, error) {
+	words, err := r.querySavedWords(ctx,
+		`select `+savedWordColumns+` from saved_words where user_id = ? order by random() limit 1`,
+		userID,
+	)
+	if err != nil || len(words) == 0 {
+		return nil, err
+	}
+	return &words[0], nil
+}
+
+// NextUnlearnedSavedWord returns the user's earliest saved word that is not
+// in their /learn deck yet, or nil when every saved word is.
+func (r *Repository) NextUnlearnedSavedWord(ctx context.Context, userID int64) (*models.SavedWord, error) {
+	words, err := r.querySavedWords(ctx,
+		`select `+savedWordColumns+` from saved_words s
+		 where user_id = ? and not exists (
+		     select 1 from word_reviews w where w.user_id = s.user_id and w.headword_clean = s.headword_clean
+		 )
+		 order by id
+		 limit 1`,
+		userID,
+	)
+	if err != nil || len(words) == 0 {
+		return nil, err
+	}
+	return &words[0], nil
+}
+
+// NextDueSavedWordReview is NextDueWordReview over the cards of the user's
+// saved words only.
+func (r *Repository) NextDueSavedWordReview(ctx context.Context, userID int64, now time.Time) (*models.WordReview, error) {
+	review, err := scanWordReview(r.db.QueryRowContext(ctx,
+		`select `+wordReviewColumns+` from word_reviews w
+		 where user_id = ? and due_at <= ? and exists (
+		     select 1 from saved_words s where s.user_id = w.user_id and s.headword_clean = w.headword_clean
+		 )
+		 order by due_at, id
+		 limit 1`,
+		userID, formatReviewTime(now),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return review, err
+}
+
+func (r *Repository) querySavedWords(ctx context.Context, query string, args ...any) ([]models.SavedWord, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var words []models.SavedWord
+	for rows.Next() {
+		var w models.SavedWord
+		var saved string
+		if err := rows.Scan(&w.ID, &w.UserID, &w.Headword, &w.HeadwordClean, &w.Russian, &saved); err != nil {
+			return nil, err
+		}
+		if w.SavedAt, err = time.ParseInLocation(reviewTime, saved, time.UTC); err != nil {
+			return nil, err
+		}
+		words = append(words, w)
+	}
+	return words, rows.Err()
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSavedWords_ListPagesAndDeletes(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	for _, w := range []models.SavedWord{
+		{UserID: 7, Headword: "Дитт", HeadwordClean: "дитт", Russian: "дерево"},
+		{UserID: 7, Headword: "Цӏа", HeadwordClean: "цӏа", Russian: "дом"},
+		{UserID: 7, Headword: "Хи", HeadwordClean: "хи", Russian: "вода"},
+		{UserID: 8, Headword: "Дитт", HeadwordClean: "дитт", Russian: "дерево"},
+	} {
+		if added, err := r.SaveWord(ctx, w); err != nil || !added {
+			t.Fatalf("SaveWord(%q) = %v, %v; want added", w.Headword, added, err)
+		}
+	}
+	if added, err := r.SaveWord(ctx, models.SavedWord{UserID: 7, Headword: "дитт", HeadwordClean: "дитт", Russian: "дерево"}); err != nil || added {
+		t.Fatalf("SaveWord(again) = %v, %v; want it kept once", added, err)
+	}
+
+	page, total, err := r.ListSavedWords(ctx, 7, 0, 2)
+	if err != nil || total != 3 || len(page) != 2 || page[0].Headword != "Хи" || page[1].Headword != "Цӏа" {
+		t.Fatalf("ListSavedWords(first page) = %+v, %d, %v; want Хи, Цӏа of 3", page, total, err)
+	}
+	page, _, err = r.ListSavedWords(ctx, 7, 2, 2)
+	if err != nil || len(page) != 1 || page[0].Headword != "Дитт" {
+		t.Fatalf("ListSavedWords(second page) = %+v, %v; want Дитт", page, err)
+	}
+
+	if ok, err := r.DeleteSavedWord(ctx, 8, page[0].ID); err != nil || ok {
+		t.Fatalf("DeleteSavedWord(another user's) = %v, %v; want nothing deleted", ok, err)
+	}
+	if ok, err := r.DeleteSavedWord(ctx, 7, page[0].ID); err != nil || !ok {
+		t.Fatalf("DeleteSavedWord = %v, %v", ok, err)
+	}
+	if _, total, _ := r.ListSavedWords(ctx, 7, 0, 10); total != 2 {
+		t.Fatalf("saved words after delete = %d, want 2", total)
+	}
+	if w, err := r.RandomSavedWord(ctx, 9); err != nil || w != nil {
+		t.Fatalf("RandomSavedWord(no words) = %+v, %v; want nothing", w, err)
+	}
+}
+
+func TestSavedWords_LearnDeckRestriction(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	for _, w := range []models.SavedWord{
+		{UserID: 7, Headword: "Дитт", HeadwordClean: "дитт", Russian: "дерево"},
+		{UserID: 7, Headword: "Цӏа", HeadwordClean: "цӏа", Russian: "дом"},
+	} {
+		if _, err := r.SaveWord(ctx, w); err != nil {
+			t.Fatalf("SaveWord: %v", err)
+		}
+	}
+	// A pool card, due first, and the first saved word's card.
+	for _, c := range []models.WordReview{
+		{UserID: 7, Headword: "Хи", HeadwordClean: "хи", Russian: "вода", DueAt: now.Add(-time.Hour)},
+		{UserID: 7, Headword: "Дитт", HeadwordClean: "дитт", Russian: "дерево", DueAt: now},
+	} {
+		if _, _, err := r.AddWordReview(ctx, c, "2026-10-16"); err != nil {
+			t.Fatalf("AddWordReview: %v", err)
+		}
+	}
+
+	due, err := r.NextDueSavedWordReview(ctx, 7, now)
+	if err != nil || due == nil || due.HeadwordClean != "дитт" {
+		t.Fatalf("NextDueSavedWordReview = %+v, %v; want the saved word's card, not the pool's", due, err)
+	}
+	next, err := r.NextUnlearnedSavedWord(ctx, 7)
+	if err != nil || next == nil || next.HeadwordClean != "цӏа" {
+		t.Fatalf("NextUnlearnedSavedWord = %+v, %v; want the saved word not in the deck", next, err)
+	}
+}
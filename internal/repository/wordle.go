@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"chetoru/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// GetWordleWord returns day's /wordle word, or "" when none has been picked
+// for the day yet.
+func (r *Repository) GetWordleWord(ctx context.Context, day string) (string, error) {
+	var word string
+	err := r.db.QueryRowContext(ctx, `SELECT word FROM wordle_days WHERE day = ?;`, day).Scan(&word)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return word, err
+}
+
+// SaveWordleWord stores day's word unless one is stored already; like
+// SaveDailyChallenge, the first stored is the one everybody plays. Read it
+// back with GetWordleWord.
+func (r *Repository) SaveWordleWord(ctx context.Context, day, word string) error {
+	_, err := r.db.ExecContext(ctx, `INSERT OR IGNORE INTO wordle_days (day, word) VALUES (?, ?);`, day, word)
+	return err
+}
+
+// StartWordleGame returns the user's game of day's word, starting it if they
+// have none: there is one per user per day.
+func (r *Repository) StartWordleGame(ctx context.Context, day string, userID int64, username, firstName string) (*models.WordleGame, error) {
+	if _, err := r.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO wordle_games (day, user_id, username, first_name, started_at) VALUES (?, ?, ?, ?, ?);`,
+		day, userID, username, firstName, formatReviewTime(time.Now()),
+	); err != nil {
+		return nil, err
+	}
+	return r.GetWordleGame(ctx, day, userID)
+}
+
+// GetWordleGame returns the user's game of day's word, or nil when they have
+// not started one.
+func (r *Repository) GetWordleGame(ctx context.Context, day string, userID int64) (*models.WordleGame, error) {
+	g := models.WordleGame{Day: day, UserID: userID}
+	var guesses, started string
+	var finished sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`SELECT username, first_name, guesses, solved, started_at, finished_at
+		 FROM wordle_games WHERE day = ? AND user_id = ?;`,
+		day, userID,
+	).Scan(&g.Username, &g.FirstName, &guesses, &g.Solved, &started, &finished)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	g.Guesses = strings.Fields(guesses)
+	if g.StartedAt, err = time.ParseInLocation(reviewTime, started, time.UTC); err != nil {
+		return nil, err
+	}
+	if g.FinishedAt, err = parseNullTime(finished); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// AddWordleGuess records guess as the user's guess number index of day's
+// game, out of maxGuesses, finishing the game when it solved the word or was
+// the last. Like RecordDailyAnswer it returns false, recording nothing,
+// unless index is the game's next guess: two messages racing cannot both
+// take the same turn, nor play a finished game on.
+func (r *Repository) AddWordleGuess(ctx context.Context, day string, userID int64, index int, guess string, solved bool, maxGuesses int) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE wordle_games
+		 SET guesses = CASE WHEN guesses = '' THEN ? ELSE guesses || ' ' || ? END,
+		     attempts = attempts + 1,
+		     solved = ?,
+		     finished_at = CASE WHEN ? OR attempts + 1 >= ? THEN ? ELSE finished_at END
+		 WHERE day = ? AND user_id = ? AND attempts = ? AND finished_at IS NULL;`,
+		guess, guess, solved, solved, maxGuesses, formatReviewTime(time.Now()), day, userID, index,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWordleWord_FirstStoredWins(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+
+	if got, err := r.GetWordleWord(ctx, "2026-10-16"); err != nil || got != "" {
+		t.Fatalf("GetWordleWord(new day) = %q, %v; want nothing", got, err)
+	}
+	for _, w := range []string{"хьекъал", "кӏорни"} {
+		if err := r.SaveWordleWord(ctx, "2026-10-16", w); err != nil {
+			t.Fatalf("SaveWordleWord: %v", err)
+		}
+	}
+	if got, err := r.GetWordleWord(ctx, "2026-10-16"); err != nil || got != "хьекъал" {
+		t.Fatalf("GetWordleWord = %q, %v; want the first word stored", got, err)
+	}
+}
+
+func TestWordleGame_GuessesInTurnUntilFinished(t *testing.T) {
+	r := newDictionaryTestRepo(t)
+	ctx := context.Background()
+	const day, maxGuesses = "2026-10-16", 3
+
+	g, err := r.StartWordleGame(ctx, day, 7, "ali", "Али")
+	if err != nil || g == nil || len(g.Guesses) != 0 || g.Finished() {
+		t.Fatalf("StartWordleGame = %+v, %v; want a fresh game", g, err)
+	}
+	guess := func(userID int64, index int, word string, solved, want bool) {
+		t.Helper()
+		if ok, err := r.AddWordleGuess(ctx, day, userID, index, word, solved, maxGuesses); err != nil || ok != want {
+			t.Fatalf("AddWordleGuess(%d, %q) = %v, %v; want %v", index, word, ok, err, want)
+		}
+	}
+	guess(7, 0, "кӏорни", false, true)
+	guess(7, 0, "бераш", false, false) // the same turn twice
+	guess(7, 1, "дийца", false, true)
+	if g, _ = r.GetWordleGame(ctx, day, 7); g.Finished() {
+		t.Fatalf("game after two of three guesses = %+v, want it on", g)
+	}
+	guess(7, 2, "хьекъал", true, true)
+	guess(7, 3, "хьекъал", true, false) // finished
+
+	g, err = r.GetWordleGame(ctx, day, 7)
+	if err != nil || !g.Finished() || !g.Solved || len(g.Guesses) != 3 || g.Guesses[0] != "кӏорни" || g.Guesses[2] != "хьекъал" {
+		t.Fatalf("game after solving = %+v, %v; want it solved in three", g, err)
+	}
+
+	// Out of guesses without solving finishes the game too.
+	if _, err := r.StartWordleGame(ctx, day, 8, "zara", ""); err != nil {
+		t.Fatalf("StartWordleGame: %v", err)
+	}
+	for i, w := range []string{"кӏорни", "бераш", "дийца"} {
+		guess(8, i, w, false, true)
+	}
+	if g, err = r.GetWordleGame(ctx, day, 8); err != nil || !g.Finished() || g.Solved {
+		t.Fatalf("game out of guesses = %+v, %v; want it lost", g, err)
+	}
+}
@@ -10,8 +10,11 @@ import (
 
 	"context"
 	"database/sql"
+	"math"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -82,7 +85,37 @@ func main() {
 		log.Println("AI formatting disabled (no OPENROUTER_API_KEY)")
 	}
 
-	translator := business.NewBusiness(redisCache, repo, aiClient, log)
+	// One dosham client for the whole process: translation, grammar, /random
+	// and /quiz share its concurrency cap and circuit breaker.
+	translator := business.NewBusiness(redisCache, repo, business.NewDoshamClient(), aiClient, log)
+
+	// Further dictionaries beside dosham, in priority order (see README).
+	if spec := os.Getenv("DICTIONARY_SOURCES"); spec != "" {
+		sources, err := translator.SourcesFromConfig(spec, os.Getenv("GLOSSARY_PATH"))
+		if err != nil {
+			log.Fatal("dictionary sources: ", err)
+		}
+		translator.SetSources(sources...)
+		log.Printf("dictionary sources: %s", spec)
+	}
+
+	// Offline, lookups are answered from the local mirror only (see cmd/mirror).
+	if v := os.Getenv("OFFLINE_MODE"); v == "1" || strings.EqualFold(v, "true") {
+		translator.SetOfflineMode(true)
+		log.Warn("offline mode: dosham will not be called")
+	}
+
+	// How alike quiz options are, 0 (any word of the category) to 1.
+	if v := os.Getenv("QUIZ_DIFFICULTY"); v != "" {
+		d, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			log.Fatal("QUIZ_DIFFICULTY: ", err)
+		}
+		if math.IsNaN(d) || math.IsInf(d, 0) {
+			log.Fatal("QUIZ_DIFFICULTY: not a finite number: ", v)
+		}
+		translator.SetQuizDifficulty(d)
+	}
 
 	var spellChecker net.AI
 	if aiClient != nil {
@@ -90,6 +123,11 @@ func main() {
 	}
 	botService := net.NewNet(log, repo, bot, translator, redisCache, spellChecker)
 
+	// Learner's transcription under Chechen headwords on word cards.
+	if v := os.Getenv("PRONUNCIATION"); v == "1" || strings.EqualFold(v, "true") {
+		botService.SetPronunciation(true)
+	}
+
 	// Wire callback: after AI formatting → send to moderation
 	translator.SetOnPairReady(func(pairID int64, cleanWord string) {
 		botService.SendAutoModeration(context.Background(), cleanWord)
@@ -104,6 +142,9 @@ func main() {
 	// Evening warning to quiz players whose daily streak lapses at midnight.
 	botService.StartStreakReminderScheduler(ctx)
 
+	// Weekly and monthly leaderboard seasons: archive and announce the results.
+	botService.StartSeasonScheduler(ctx)
+
 	botService.Start(ctx)
 
 	// Bounded grace for detached background work (pair persistence, cache
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- fetched_at is when dosham last confirmed a pair. The bot answers from this
+-- table when dosham is down or OFFLINE_MODE is on, and cmd/mirror re-fetches
+-- the oldest rows first so the mirror does not drift from the source. Rows
+-- stored before the mirror existed stay null and count as the stalest.
+alter table dictionary_pairs add column fetched_at datetime;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- One row per search seed cmd/mirror has sent to dosham, so an interrupted
+-- crawl resumes where it stopped instead of starting over.
+create table if not exists mirror_seeds (
+    seed       text primary key,
+    entries    integer not null default 0,
+    crawled_at datetime not null default current_timestamp
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists mirror_seeds;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+alter table dictionary_pairs drop column fetched_at;
+-- +goose StatementEnd
@@ -0,0 +1,118 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Full-text index over dictionary_pairs. The exact and prefix lookups only
+-- reach a word that is a headword or a whole gloss; a word used inside a gloss
+-- or an example sentence was unreachable locally. rowid is dictionary_pairs.id.
+--
+-- headword and gloss copy the *_clean columns, which are already folded the
+-- way the bot folds queries (case, ё, palochka stand-ins). examples comes from
+-- the parsed article in structured_json and is folded by the view below;
+-- unicode61 handles case and strips the stress marks.
+create virtual table if not exists dictionary_fts using fts5(
+    headword,
+    gloss,
+    examples,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- The example text of each pair, folded like tools.NormalizeSearch: ё to е,
+-- and a 1, i or l next to a Cyrillic letter to the palochka, so «г1ала» in an
+-- example is found by «гӏала». lower() only folds ASCII, which is all the
+-- stand-ins need. The per-character walk runs only on text holding one.
+create view if not exists dictionary_fts_examples as
+select p.id as id, (
+    with recursive
+        src(t) as (
+            select replace(replace(lower((
+                select group_concat(coalesce(json_extract(e.value, '$.ce'), '') || ' ' || coalesce(json_extract(e.value, '$.ru'), ''), ' ')
+                from json_each(case when json_valid(p.structured_json) then p.structured_json else '{}' end, '$.examples') e
+            )), 'ё', 'е'), 'Ё', 'Е')
+        ),
+        fold(i, out) as (
+            select 1, '' from src
+            union all
+            select i + 1, out || case
+                when substr(t, i, 1) in ('1', 'i', 'l') and (
+                    substr(out, -1) between 'а' and 'я' or substr(out, -1) between 'А' and 'Я'
+                    or substr(out, -1) in ('ё', 'Ё', 'ӏ', 'Ӏ')
+                    or substr(t, i + 1, 1) between 'а' and 'я' or substr(t, i + 1, 1) between 'А' and 'Я'
+                    or substr(t, i + 1, 1) in ('ё', 'Ё', 'ӏ', 'Ӏ'))
+                then 'ӏ' else substr(t, i, 1) end
+            from fold, src
+            where i <= length(t)
+        )
+    select case
+        when t glob '*[1il]*' then (select out from fold order by i desc limit 1)
+        else t
+    end
+    from src
+) as examples
+from dictionary_pairs p;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+insert into dictionary_fts (rowid, headword, gloss, examples)
+select p.id, p.original_clean, p.translation_clean, x.examples
+from dictionary_pairs p
+join dictionary_fts_examples x on x.id = p.id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger if not exists dictionary_pairs_fts_insert after insert on dictionary_pairs
+begin
+    insert into dictionary_fts (rowid, headword, gloss, examples)
+    values (
+        new.id,
+        new.original_clean,
+        new.translation_clean,
+        (select examples from dictionary_fts_examples where id = new.id)
+    );
+end;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- structured_json is written long after the insert, by cmd/parse_articles, so
+-- the index has to follow updates too.
+create trigger if not exists dictionary_pairs_fts_update
+after update of original_clean, translation_clean, structured_json on dictionary_pairs
+begin
+    delete from dictionary_fts where rowid = old.id;
+    insert into dictionary_fts (rowid, headword, gloss, examples)
+    values (
+        new.id,
+        new.original_clean,
+        new.translation_clean,
+        (select examples from dictionary_fts_examples where id = new.id)
+    );
+end;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger if not exists dictionary_pairs_fts_delete after delete on dictionary_pairs
+begin
+    delete from dictionary_fts where rowid = old.id;
+end;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop trigger if exists dictionary_pairs_fts_delete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop trigger if exists dictionary_pairs_fts_update;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop trigger if exists dictionary_pairs_fts_insert;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop view if exists dictionary_fts_examples;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists dictionary_fts;
+-- +goose StatementEnd
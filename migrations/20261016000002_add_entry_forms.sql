@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Inflected Chechen forms mapped to their headword, from dosham's entryForms.
+-- dosham's search only knows headwords, so «дийцира» finds nothing while the
+-- paradigm of «дийца» lists it. Filled whenever grammar is fetched and by
+-- cmd/backfill_forms. form_clean and headword_clean hold the business
+-- package's normalizeForRank key — tools.NormalizeSearch with the stress marks
+-- stripped — and are looked up by the same; headword keeps the display
+-- spelling as well.
+create table if not exists entry_forms (
+    form_clean     text not null,
+    headword       text not null,
+    headword_clean text not null,
+    created_at     datetime not null default current_timestamp,
+    primary key (form_clean, headword_clean)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists entry_forms;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Spaced-repetition state behind /learn: one row per user and Chechen
+-- headword, scheduled by SM-2 (pkg/srs). headword_clean is the headword
+-- normalized (tools.NormalizeSearch, stress stripped), so one word is one card
+-- however the dictionary spelled it; headword and russian are what the card
+-- shows. due_at is UTC text, "YYYY-MM-DD HH:MM:SS", compared as a string.
+-- introduced_on is the local date the card was first served, which is what
+-- the daily cap on new words counts.
+create table if not exists word_reviews (
+    id             integer primary key autoincrement,
+    user_id        integer not null,
+    headword       text    not null,
+    headword_clean text    not null,
+    russian        text    not null,
+    ease           real    not null default 2.5,
+    interval_days  integer not null default 0,
+    repetitions    integer not null default 0,
+    lapses         integer not null default 0,
+    due_at         text    not null,
+    introduced_on  text    not null,
+    reviewed_at    datetime,
+    unique (user_id, headword_clean)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create index if not exists word_reviews_due on word_reviews (user_id, due_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index if exists word_reviews_due;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists word_reviews;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per quiz answer, beside the running totals in quiz_stats: the
+-- totals say how well someone plays, only the answers say which words are
+-- hard. prompt is the word as the question showed it — Chechen, or Russian
+-- when reversed. mode separates /quiz answers ('quiz') from /learn reviews
+-- ('learn'), which repeat words on purpose. latency_ms is null when the
+-- question's send time is unknown.
+create table if not exists quiz_answers (
+    id             integer primary key autoincrement,
+    user_id        integer not null,
+    prompt         text    not null,
+    reversed       integer not null default 0,
+    chosen         text    not null,
+    correct_option text    not null,
+    is_correct     integer not null,
+    latency_ms     integer,
+    chat_type      text    not null default '',
+    mode           text    not null default 'quiz',
+    answered_at    datetime not null default current_timestamp
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create index if not exists quiz_answers_user on quiz_answers (user_id, prompt);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create index if not exists quiz_answers_prompt on quiz_answers (prompt, reversed);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index if exists quiz_answers_prompt;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop index if exists quiz_answers_user;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists quiz_answers;
+-- +goose StatementEnd
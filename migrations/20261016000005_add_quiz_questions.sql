@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every quiz question the bot sends, buttons and polls alike, under an opaque
+-- random id. The callback used to carry the correct option itself, so a
+-- modified client could answer right every time and farm /top; now it carries
+-- only the id and the chosen option, and grading reads the rest from here.
+-- options is a JSON array of the options as shown. poll_id is set for a group
+-- poll, whose answers arrive keyed by it. Rows past expires_at are pruned.
+create table if not exists quiz_questions (
+    id          text    primary key,
+    prompt      text    not null,
+    reversed    integer not null default 0,
+    options     text    not null,
+    correct_idx integer not null,
+    review_id   integer not null default 0,
+    chat_type   text    not null default '',
+    poll_id     text    unique,
+    created_at  text    not null,
+    expires_at  text    not null
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create index if not exists quiz_questions_expires on quiz_questions (expires_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- One answer per user per question: the first one counts.
+create table if not exists quiz_question_answers (
+    question_id text    not null,
+    user_id     integer not null,
+    primary key (question_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists quiz_question_answers;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop index if exists quiz_questions_expires;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists quiz_questions;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The /quiz filter a question was drawn under — part of speech (subtype) and
+-- source dictionary (rate), 0 for any — so its "next question" button keeps
+-- to the same category.
+alter table quiz_questions add column subtype integer not null default 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+alter table quiz_questions add column rate integer not null default 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table quiz_questions drop column rate;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+alter table quiz_questions drop column subtype;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- What a stored quiz question asks: '' for a translation, or one of the
+-- grammar kinds (form, idiom, phrase). hint is the meaning shown beside a
+-- set phrase with a word missing. A grammar question's "next" button asks for
+-- another grammar question, so kind also stands for that part of its filter.
+alter table quiz_questions add column kind text not null default '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+alter table quiz_questions add column hint text not null default '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table quiz_questions drop column hint;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+alter table quiz_questions drop column kind;
+-- +goose StatementEnd
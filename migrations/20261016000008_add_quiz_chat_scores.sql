@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The chat a question was sent to, 0 for questions sent before this column
+-- existed. A poll answer arrives without its chat, so crediting a group's own
+-- scoreboard has to find the chat here.
+alter table quiz_questions add column chat_id integer not null default 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Each group's own quiz scoreboard, fed by the polls posted in it — /quiz and
+-- /tournament alike — beside the global one in quiz_stats. In a group /top
+-- shows this one: a chat's regulars compete with each other, not with the
+-- whole bot's top ten.
+create table if not exists quiz_chat_scores (
+    chat_id       integer not null,
+    user_id       integer not null,
+    username      text    not null default '',
+    first_name    text    not null default '',
+    correct_count integer not null default 0,
+    total_count   integer not null default 0,
+    updated_at    datetime not null default current_timestamp,
+    primary key (chat_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists quiz_chat_scores;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+alter table quiz_questions drop column chat_id;
+-- +goose StatementEnd
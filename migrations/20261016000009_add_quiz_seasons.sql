@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The weekly and monthly boards rank answers by when they were given.
+create index if not exists quiz_answers_answered on quiz_answers (answered_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Closed leaderboard seasons: period is 'week' or 'month', season its key
+-- ("2026-W42", "2026-10"). A row is written once, when the season's results
+-- are announced, so it also keeps the announcement from going out twice.
+create table if not exists quiz_seasons (
+    period    text not null,
+    season    text not null,
+    starts_at text not null,
+    ends_at   text not null,
+    players   integer not null default 0,
+    closed_at datetime not null default current_timestamp,
+    primary key (period, season)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- The podium of each closed season, with the names as they were then.
+create table if not exists quiz_season_winners (
+    period        text    not null,
+    season        text    not null,
+    rank          integer not null,
+    user_id       integer not null,
+    username      text    not null default '',
+    first_name    text    not null default '',
+    correct_count integer not null,
+    total_count   integer not null,
+    primary key (period, season, rank)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists quiz_season_winners;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists quiz_seasons;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop index if exists quiz_answers_answered;
+-- +goose StatementEnd
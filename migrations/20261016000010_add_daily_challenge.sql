@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each day's challenge, generated by whoever opens it first and then the same
+-- for everyone: day is the local date, questions a JSON array of the
+-- questions as asked, correct options included — grading reads them here.
+create table if not exists daily_challenges (
+    day        text primary key,
+    questions  text not null,
+    created_at datetime not null default current_timestamp
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- One attempt per user per day. results holds a '1' or '0' per question
+-- answered, in order, so the next answer's place is its length and the
+-- shareable grid is read straight off it. finished_at is null until the last
+-- answer; the leaderboard ranks finished attempts by correct answers, then
+-- by time taken.
+create table if not exists daily_challenge_attempts (
+    day         text    not null,
+    user_id     integer not null,
+    username    text    not null default '',
+    first_name  text    not null default '',
+    results     text    not null default '',
+    correct     integer not null default 0,
+    started_at  text    not null,
+    finished_at text,
+    primary key (day, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists daily_challenge_attempts;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists daily_challenges;
+-- +goose StatementEnd
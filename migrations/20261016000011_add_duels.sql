@@ -0,0 +1,66 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One-on-one quiz duels, challenged from inline mode. A row is created when
+-- the challenger's inline query is answered, before anyone has picked the
+-- result, so most rows are never accepted; those are pruned. The opponent
+-- columns and inline_message_id — the posted challenge, edited with the
+-- score — are filled on acceptance, and questions, a JSON array like
+-- daily_challenges', with them. finished_at is set once, when the duel is
+-- settled and the ratings move.
+create table if not exists duels (
+    id                    text primary key,
+    challenger_id         integer not null,
+    challenger_username   text    not null default '',
+    challenger_first_name text    not null default '',
+    opponent_id           integer,
+    opponent_username     text    not null default '',
+    opponent_first_name   text    not null default '',
+    inline_message_id     text    not null default '',
+    questions             text    not null default '',
+    created_at            text    not null,
+    accepted_at           text,
+    finished_at           text
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Each side's run through a duel's questions, kept as daily_challenge_attempts
+-- keeps a day's: a '1' or '0' per answer in results, finished_at set by the
+-- last one.
+create table if not exists duel_players (
+    duel_id     text    not null,
+    user_id     integer not null,
+    results     text    not null default '',
+    correct     integer not null default 0,
+    finished_at text,
+    primary key (duel_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- The duel rating, Elo (pkg/elo), apart from the /quiz tallies: a duel is won
+-- against a person, and how strong that person was is what the rating weighs.
+create table if not exists duel_ratings (
+    user_id    integer primary key,
+    username   text    not null default '',
+    first_name text    not null default '',
+    rating     integer not null,
+    wins       integer not null default 0,
+    losses     integer not null default 0,
+    draws      integer not null default 0,
+    updated_at datetime not null default current_timestamp
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists duel_ratings;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists duel_players;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists duels;
+-- +goose StatementEnd
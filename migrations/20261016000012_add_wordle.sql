@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each day's /wordle word, picked by whoever opens the day first and then
+-- the same for everyone, however the lexicon grows during the day. word is
+-- the folded spelling guesses are compared to.
+create table if not exists wordle_days (
+    day        text primary key,
+    word       text not null,
+    created_at datetime not null default current_timestamp
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- One game per user per day. guesses holds the words guessed, in order and
+-- space-separated, and attempts their count, so the next guess's place is
+-- checked without splitting; the board and the shareable grid are rebuilt
+-- from the guesses and the day's word. finished_at is null while guesses
+-- are left and the word is not solved.
+create table if not exists wordle_games (
+    day         text    not null,
+    user_id     integer not null,
+    username    text    not null default '',
+    first_name  text    not null default '',
+    guesses     text    not null default '',
+    attempts    integer not null default 0,
+    solved      integer not null default 0,
+    started_at  text    not null,
+    finished_at text,
+    primary key (day, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists wordle_games;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists wordle_days;
+-- +goose StatementEnd
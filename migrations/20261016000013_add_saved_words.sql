@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Words a user saved from a card with «⭐ Сохранить», for /mywords and for
+-- /quiz and /learn restricted to them. headword_clean is the key a word is
+-- saved once under, the same key word_reviews uses, so a saved word can be
+-- matched to its /learn card.
+create table if not exists saved_words (
+    id             integer primary key autoincrement,
+    user_id        integer not null,
+    headword       text    not null,
+    headword_clean text    not null,
+    russian        text    not null,
+    saved_at       text    not null,
+    unique (user_id, headword_clean)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- A question drawn from the asker's saved words keeps drawing from them when
+-- «next» is tapped.
+alter table quiz_questions add column saved integer not null default 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table quiz_questions drop column saved;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists saved_words;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- What a text lookup was for: one row per activity row it details, so the
+-- analytics counting activity stay as they are. word is the query
+-- normalized as the dictionary searches it — what /history lists once — and
+-- direction is 1 for Chechen → Russian, 2 for Russian → Chechen, 0 when a
+-- miss gave nothing to tell by. Rows older than the retention are pruned
+-- daily.
+create table if not exists activity_lookups (
+    activity_id integer primary key references activity(id) on delete cascade,
+    user_id     integer not null,
+    query       text    not null,
+    word        text    not null,
+    direction   integer not null default 0,
+    hit         integer not null default 0,
+    created_at  text    not null
+);
+create index if not exists idx_activity_lookups_user on activity_lookups (user_id, created_at);
+create index if not exists idx_activity_lookups_created on activity_lookups (created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Users who turned /history off: their lookups are not kept.
+alter table users add column history_off integer not null default 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table users drop column history_off;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists activity_lookups;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- What an answered question asked, as quiz_questions.kind: '' for a
+-- translation, or a grammar kind. A grammar question's prompt is a headword
+-- or a set phrase, and its misses say nothing about how hard the word is to
+-- translate, so the difficulty lists read translations only.
+alter table quiz_answers add column kind text not null default '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table quiz_answers drop column kind;
+-- +goose StatementEnd
@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Substring index over the folded headword and gloss, for the local stand-in
+-- for dosham's `find`. dictionary_fts matches whole words and word prefixes;
+-- dosham matches anywhere in a word («елка» finds «белка»), and a mirror that
+-- matched less would render a different card whenever it answered. The
+-- trigram tokenizer matches any substring of three letters or more without
+-- reading the whole table. rowid is dictionary_pairs.id.
+create virtual table if not exists dictionary_trigram using fts5(
+    original_clean,
+    translation_clean,
+    content = 'dictionary_pairs',
+    content_rowid = 'id',
+    tokenize = 'trigram'
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+insert into dictionary_trigram (dictionary_trigram) values ('rebuild');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger if not exists dictionary_pairs_trigram_insert after insert on dictionary_pairs
+begin
+    insert into dictionary_trigram (rowid, original_clean, translation_clean)
+    values (new.id, new.original_clean, new.translation_clean);
+end;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger if not exists dictionary_pairs_trigram_update
+after update of original_clean, translation_clean on dictionary_pairs
+begin
+    insert into dictionary_trigram (dictionary_trigram, rowid, original_clean, translation_clean)
+    values ('delete', old.id, old.original_clean, old.translation_clean);
+    insert into dictionary_trigram (rowid, original_clean, translation_clean)
+    values (new.id, new.original_clean, new.translation_clean);
+end;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger if not exists dictionary_pairs_trigram_delete after delete on dictionary_pairs
+begin
+    insert into dictionary_trigram (dictionary_trigram, rowid, original_clean, translation_clean)
+    values ('delete', old.id, old.original_clean, old.translation_clean);
+end;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop trigger if exists dictionary_pairs_trigram_delete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop trigger if exists dictionary_pairs_trigram_update;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop trigger if exists dictionary_pairs_trigram_insert;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+drop table if exists dictionary_trigram;
+-- +goose StatementEnd
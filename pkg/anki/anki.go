@@ -0,0 +1,275 @@
+// Package anki writes vocabulary out in forms Anki imports: delimited text
+// with Anki's header directives, and an .apkg package.
+//
+// An .apkg is a zip holding a legacy (schema 11) Anki collection — an SQLite
+// database named collection.anki2 — and a JSON map of media files, here
+// empty. Every note gets two cards, word → meaning and meaning → word.
+//
+// The note type, the deck and each note's GUID are derived from fixed names
+// and the note's front, not from the clock, so importing a newer export of
+// the same words updates the notes already there instead of duplicating them.
+package anki
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Note is one word: its front (the word), back (its meaning) and two
+// optional extras shown with the answer.
+type Note struct {
+	Front   string
+	Back    string
+	Example string
+	Grammar string
+}
+
+// Fields names Note's fields, in order, as the note type and text headers do.
+var Fields = []string{"Front", "Back", "Example", "Grammar"}
+
+func (n Note) fields() []string {
+	return []string{n.Front, n.Back, n.Example, n.Grammar}
+}
+
+// WriteText writes notes as delimited text, one note per line, separated by
+// sep (',' or '\t'). The header lines tell Anki's importer the separator and
+// the field names, so the file maps onto the fields without questions.
+func WriteText(w io.Writer, notes []Note, sep rune) error {
+	name := "comma"
+	if sep == '\t' {
+		name = "tab"
+	}
+	if _, err := fmt.Fprintf(w, "#separator:%s\n#html:false\n#columns:%s\n", name, strings.Join(Fields, string(sep))); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = sep
+	for _, n := range notes {
+		if err := cw.Write(n.fields()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WritePackage writes notes as an .apkg holding one deck named deck. The
+// collection is built in a temporary file, which SQLite needs, and removed
+// once zipped.
+func WritePackage(ctx context.Context, w io.Writer, deck string, notes []Note) error {
+	dir, err := os.MkdirTemp("", "anki")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "collection.anki2")
+	if err := writeCollection(ctx, path, deck, notes, time.Now()); err != nil {
+		return fmt.Errorf("write collection: %w", err)
+	}
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, f := range []struct {
+		name string
+		body []byte
+	}{
+		{"collection.anki2", collection},
+		{"media", []byte("{}")},
+	} {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// modelID is the note type's ID. Anki matches an imported note type to one
+// it has by ID, so it must never change.
+const modelID = 1744306117042
+
+const schema = `
+CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null, models text not null, decks text not null, dconf text not null, tags text not null);
+CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null, flags integer not null, data text not null);
+CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null, mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null, ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null);
+CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+func writeCollection(ctx context.Context, path, deck string, notes []Note, now time.Time) error {
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, schema); err != nil {
+		return err
+	}
+
+	deckID := deckID(deck)
+	conf, models, decks, dconf, err := collectionJSON(deck, deckID, now)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}');`,
+		now.Truncate(24*time.Hour).Unix(), now.Unix(), now.UnixMilli(), conf, models, decks, dconf,
+	); err != nil {
+		return err
+	}
+
+	// Note and card IDs are creation times in milliseconds in Anki; counting
+	// up from now keeps them unique within the package. On import Anki
+	// matches notes by GUID, not ID.
+	id := now.UnixMilli()
+	for i, n := range notes {
+		fields := n.fields()
+		for j, f := range fields {
+			fields[j] = strings.ReplaceAll(html.EscapeString(f), "\n", "<br>")
+		}
+		noteID := id
+		id++
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO notes VALUES (?, ?, ?, ?, -1, '', ?, ?, ?, 0, '');`,
+			noteID, guid(n.Front), modelID, now.Unix(), strings.Join(fields, "\x1f"), n.Front, checksum(n.Front),
+		); err != nil {
+			return err
+		}
+		for ord := range 2 {
+			// A new card's due is its position in the new queue.
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO cards VALUES (?, ?, ?, ?, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '');`,
+				id, noteID, deckID, ord, now.Unix(), i+1,
+			); err != nil {
+				return err
+			}
+			id++
+		}
+	}
+	return tx.Commit()
+}
+
+// collectionJSON builds the col row's JSON columns: the collection config,
+// the note type, the decks (Anki expects its Default deck, ID 1, to exist)
+// and the deck options.
+func collectionJSON(deck string, deckID int64, now time.Time) (conf, models, decks, dconf string, err error) {
+	side := func(name string, ord int, front, back string) map[string]any {
+		return map[string]any{
+			"name":  name,
+			"ord":   ord,
+			"qfmt":  "{{" + front + "}}",
+			"afmt":  "{{FrontSide}}<hr id=answer>{{" + back + "}}{{#Example}}<div class=example>{{Example}}</div>{{/Example}}{{#Grammar}}<div class=grammar>{{Grammar}}</div>{{/Grammar}}",
+			"did":   nil,
+			"bqfmt": "",
+			"bafmt": "",
+		}
+	}
+	flds := make([]map[string]any, len(Fields))
+	for i, name := range Fields {
+		flds[i] = map[string]any{"name": name, "ord": i, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []any{}}
+	}
+	deckJSON := func(id int64, name string) map[string]any {
+		return map[string]any{
+			"id": id, "name": name, "desc": "", "mod": now.Unix(), "usn": -1,
+			"lrnToday": []int{0, 0}, "revToday": []int{0, 0}, "newToday": []int{0, 0}, "timeToday": []int{0, 0},
+			"collapsed": false, "browserCollapsed": false, "dyn": 0, "conf": 1, "extendNew": 0, "extendRev": 0,
+		}
+	}
+
+	parts := []any{
+		map[string]any{
+			"activeDecks": []int64{deckID}, "curDeck": deckID, "curModel": strconv.FormatInt(modelID, 10),
+			"newSpread": 0, "collapseTime": 1200, "timeLim": 0, "estTimes": true, "dueCounts": true,
+			"nextPos": 1, "sortType": "noteFld", "sortBackwards": false, "addToCur": true,
+		},
+		map[string]any{
+			strconv.FormatInt(modelID, 10): map[string]any{
+				"id": modelID, "name": "chetoru", "type": 0, "mod": now.Unix(), "usn": -1, "sortf": 0, "did": deckID,
+				"tmpls":     []any{side("Forward", 0, "Front", "Back"), side("Reverse", 1, "Back", "Front")},
+				"flds":      flds,
+				"css":       ".card { font-family: arial; font-size: 24px; text-align: center; }\n.example { font-style: italic; margin-top: 1em; }\n.grammar { color: grey; font-size: 16px; margin-top: 1em; }\n",
+				"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+				"latexPost": "\\end{document}",
+				"tags":      []any{}, "vers": []any{},
+				"req": []any{[]any{0, "any", []int{0}}, []any{1, "any", []int{1}}},
+			},
+		},
+		map[string]any{
+			"1":                           deckJSON(1, "Default"),
+			strconv.FormatInt(deckID, 10): deckJSON(deckID, deck),
+		},
+		map[string]any{
+			"1": map[string]any{
+				"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true, "dyn": false,
+				"new":   map[string]any{"delays": []int{1, 10}, "ints": []int{1, 4, 7}, "initialFactor": 2500, "order": 1, "perDay": 20, "bury": false, "separate": true},
+				"rev":   map[string]any{"perDay": 200, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "bury": false, "minSpace": 1},
+				"lapse": map[string]any{"delays": []int{10}, "mult": 0, "minInt": 1, "leechFails": 8, "leechAction": 0},
+			},
+		},
+	}
+	out := make([]string, len(parts))
+	for i, p := range parts {
+		raw, err := json.Marshal(p)
+		if err != nil {
+			return "", "", "", "", err
+		}
+		out[i] = string(raw)
+	}
+	return out[0], out[1], out[2], out[3], nil
+}
+
+// deckID derives a deck's ID from its name, so every export lands in the
+// same deck. It stays clear of 1, the Default deck.
+func deckID(name string) int64 {
+	sum := sha1.Sum([]byte("deck:" + name))
+	return int64(binary.BigEndian.Uint32(sum[:4])) + 2
+}
+
+// guid derives a note's GUID from its front: the word is what a note is.
+func guid(front string) string {
+	sum := sha1.Sum([]byte("note:" + front))
+	return hex.EncodeToString(sum[:8])
+}
+
+// checksum is Anki's duplicate check value: the first 8 hex digits of the
+// sort field's SHA-1, as an integer.
+func checksum(field string) int64 {
+	sum := sha1.Sum([]byte(field))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
@@ -0,0 +1,140 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	notes := []Note{
+		{Front: "хьекъал", Back: "ум, разум", Example: "Хьекъал долу стаг.", Grammar: "сущ."},
+		{Front: "го", Back: "видеть"},
+	}
+	tests := []struct {
+		name string
+		sep  rune
+		want string
+	}{
+		{
+			name: "comma",
+			sep:  ',',
+			want: "#separator:comma\n#html:false\n#columns:Front,Back,Example,Grammar\n" +
+				"хьекъал,\"ум, разум\",Хьекъал долу стаг.,сущ.\n" +
+				"го,видеть,,\n",
+		},
+		{
+			name: "tab",
+			sep:  '\t',
+			want: "#separator:tab\n#html:false\n#columns:Front\tBack\tExample\tGrammar\n" +
+				"хьекъал\tум, разум\tХьекъал долу стаг.\tсущ.\n" +
+				"го\tвидеть\t\t\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := WriteText(&b, notes, tt.sep); err != nil {
+				t.Fatalf("WriteText: %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("WriteText =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWritePackage(t *testing.T) {
+	notes := []Note{
+		{Front: "хьекъал", Back: "ум <разум>", Example: "строка\nещё", Grammar: "сущ."},
+		{Front: "го", Back: "видеть"},
+	}
+	var b bytes.Buffer
+	if err := WritePackage(context.Background(), &b, "Чеченский", notes); err != nil {
+		t.Fatalf("WritePackage: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+	if string(files["media"]) != "{}" {
+		t.Errorf("media = %q, want {}", files["media"])
+	}
+
+	path := filepath.Join(t.TempDir(), "collection.anki2")
+	if err := os.WriteFile(path, files["collection.anki2"], 0o600); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var decks string
+	if err := db.QueryRow(`SELECT decks FROM col;`).Scan(&decks); err != nil {
+		t.Fatalf("select col: %v", err)
+	}
+	if !strings.Contains(decks, `"name":"Чеченский"`) {
+		t.Errorf("decks = %s, want the named deck", decks)
+	}
+
+	rows, err := db.Query(`SELECT guid, flds FROM notes ORDER BY id;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var flds []string
+	for rows.Next() {
+		var g, f string
+		if err := rows.Scan(&g, &f); err != nil {
+			t.Fatal(err)
+		}
+		flds = append(flds, f)
+	}
+	want := []string{
+		"хьекъал\x1fум &lt;разум&gt;\x1fстрока<br>ещё\x1fсущ.",
+		"го\x1fвидеть\x1f\x1f",
+	}
+	if len(flds) != len(want) {
+		t.Fatalf("notes = %q, want %q", flds, want)
+	}
+	for i := range want {
+		if flds[i] != want[i] {
+			t.Errorf("note %d flds = %q, want %q", i, flds[i], want[i])
+		}
+	}
+
+	var cards int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM cards WHERE did = ?;`, deckID("Чеченский")).Scan(&cards); err != nil {
+		t.Fatal(err)
+	}
+	if cards != 4 {
+		t.Errorf("cards = %d, want 4 (two per note)", cards)
+	}
+}
+
+func TestGUIDStable(t *testing.T) {
+	if guid("го") != guid("го") {
+		t.Error("guid is not stable")
+	}
+	if guid("го") == guid("ган") {
+		t.Error("guid collides on different fronts")
+	}
+}
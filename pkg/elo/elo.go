@@ -0,0 +1,43 @@
+// Package elo rates players of one-on-one games with the Elo system, as chess
+// federations do.
+//
+// Each game moves rating from the loser to the winner, by how surprising the
+// result was: beating a much stronger player earns nearly K points, beating a
+// much weaker one next to nothing. A draw moves rating towards the weaker
+// player. What one player gains the other loses, so the pool's average stays
+// where it started.
+package elo
+
+import "math"
+
+const (
+	// Initial is the rating a player starts from.
+	Initial = 1200
+	// K is the most a single game can move a rating. 32 is the usual choice
+	// for a casual pool, where ratings should settle in tens of games, not
+	// hundreds.
+	K = 32
+)
+
+// Score is a game's result from the first player's side.
+type Score float64
+
+const (
+	Loss Score = 0
+	Draw Score = 0.5
+	Win  Score = 1
+)
+
+// Expected is the score a player rated a is expected to take from a game
+// against one rated b: 0.5 between equals, about 0.76 for a 200-point
+// favourite.
+func Expected(a, b int) float64 {
+	return 1 / (1 + math.Pow(10, float64(b-a)/400))
+}
+
+// Update returns both ratings after a game between a player rated a and one
+// rated b that ended with score for the first.
+func Update(a, b int, score Score) (newA, newB int) {
+	delta := int(math.Round(K * (float64(score) - Expected(a, b))))
+	return a + delta, b - delta
+}
@@ -0,0 +1,28 @@
+package elo
+
+import "testing"
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  int
+		score Score
+		wantA int
+		wantB int
+	}{
+		{"equals, win", Initial, Initial, Win, 1216, 1184},
+		{"equals, draw", Initial, Initial, Draw, Initial, Initial},
+		{"favourite wins", 1400, 1200, Win, 1408, 1192},
+		{"underdog wins", 1200, 1400, Win, 1224, 1376},
+		{"draw helps the weaker", 1200, 1400, Draw, 1208, 1392},
+	}
+	for _, tt := range tests {
+		a, b := Update(tt.a, tt.b, tt.score)
+		if a != tt.wantA || b != tt.wantB {
+			t.Errorf("%s: Update(%d, %d, %v) = %d, %d; want %d, %d", tt.name, tt.a, tt.b, tt.score, a, b, tt.wantA, tt.wantB)
+		}
+		if a+b != tt.a+tt.b {
+			t.Errorf("%s: ratings not conserved: %d+%d != %d+%d", tt.name, a, b, tt.a, tt.b)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+// Package morph guesses the dictionary form of an inflected Russian word.
+//
+// The dictionary stores lemmas — «Яблоко», «Идти» — and users type whatever
+// form their sentence needed: «яблоками», «шёл». Trimming trailing letters
+// until something matches catches some of these and invents others («шёл»
+// trims to nothing useful; «стола» trims to «сто»). This package instead
+// undoes the endings Russian actually has, plus a table for the forms no
+// ending rule can reach.
+//
+// It is a guesser, not an analyzer: Lemmas returns every plausible candidate
+// and leaves the dictionary to say which one exists. A candidate that is not a
+// word costs one failed lookup; a missed rule costs the user the answer, so
+// the rules err on the side of proposing.
+package morph
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// POS is the part of speech a candidate lemma was derived as.
+type POS int
+
+const (
+	Unknown POS = iota
+	Noun
+	Verb
+	Adjective
+	Adverb
+)
+
+func (p POS) String() string {
+	switch p {
+	case Noun:
+		return "сущ."
+	case Verb:
+		return "гл."
+	case Adjective:
+		return "прил."
+	case Adverb:
+		return "нареч."
+	}
+	return ""
+}
+
+// Lemma is one candidate dictionary form of a word. Word is lowercase with ё
+// folded to е, the way the dictionary's search keys are.
+type Lemma struct {
+	Word string
+	POS  POS
+}
+
+// minStemRunes keeps a rule from stripping a word down to a fragment: «ели»
+// may be a form of «ель», but «и» is not the stem of anything. A candidate
+// that is the bare stem, with no ending put back, needs one letter more —
+// otherwise «ели» proposes «ел» and «они» proposes «он».
+const minStemRunes = 2
+
+// rule replaces ending with each of lemmas, for a word of the given part of
+// speech.
+type rule struct {
+	ending string
+	lemmas []string
+	pos    POS
+}
+
+// rules are tried longest ending first, so «-ами» is considered before «-и».
+// Several rules may match one word; every match contributes candidates.
+var rules = []rule{
+	// Adjectives: every case ending back to the masculine nominative. Hard
+	// stems may end in -ый or stressed -ой; soft and velar stems in -ий.
+	{"ыми", []string{"ый", "ой"}, Adjective},
+	{"ими", []string{"ий"}, Adjective},
+	{"ого", []string{"ый", "ой", "ий"}, Adjective},
+	{"его", []string{"ий"}, Adjective},
+	{"ому", []string{"ый", "ой", "ий"}, Adjective},
+	{"ему", []string{"ий"}, Adjective},
+	{"ая", []string{"ый", "ой", "ий"}, Adjective},
+	{"яя", []string{"ий"}, Adjective},
+	{"ую", []string{"ый", "ой", "ий"}, Adjective},
+	{"юю", []string{"ий"}, Adjective},
+	{"ое", []string{"ый", "ой", "ий"}, Adjective},
+	{"ее", []string{"ий"}, Adjective},
+	{"ые", []string{"ый", "ой"}, Adjective},
+	{"ие", []string{"ий"}, Adjective},
+	{"ых", []string{"ый", "ой"}, Adjective},
+	{"их", []string{"ий"}, Adjective},
+	{"ым", []string{"ый", "ой"}, Adjective},
+	{"им", []string{"ий"}, Adjective},
+	{"ой", []string{"ый"}, Adjective},
+	{"ей", []string{"ий"}, Adjective},
+
+	// Nouns. -ия/-ие/-ий stems first: «армии», «здании» are not «арми».
+	{"иями", []string{"ия", "ие"}, Noun},
+	{"иях", []string{"ия", "ие"}, Noun},
+	{"иям", []string{"ия", "ие"}, Noun},
+	{"ией", []string{"ия"}, Noun},
+	{"ием", []string{"ие", "ий"}, Noun},
+	{"ии", []string{"ия", "ие", "ий"}, Noun},
+	{"ию", []string{"ия", "ие"}, Noun},
+	{"ий", []string{"ия", "ие"}, Noun},
+	{"ами", []string{"а", "о", ""}, Noun},
+	{"ями", []string{"я", "ь", "е", "й"}, Noun},
+	{"ах", []string{"а", "о", ""}, Noun},
+	{"ях", []string{"я", "ь", "е", "й"}, Noun},
+	{"ам", []string{"а", "о", ""}, Noun},
+	{"ям", []string{"я", "ь", "е", "й"}, Noun},
+	{"ов", []string{""}, Noun},
+	{"ев", []string{"й", "ь"}, Noun},
+	{"ей", []string{"ь", "е", "я"}, Noun},
+	{"ой", []string{"а"}, Noun},
+	{"ою", []string{"а"}, Noun},
+	{"ом", []string{"", "о"}, Noun},
+	{"ем", []string{"ь", "е", "й"}, Noun},
+	{"ью", []string{"ь"}, Noun},
+	// Genitive plural with a fleeting vowel: «кошек», «девочек», «окон».
+	{"ек", []string{"ка"}, Noun},
+	{"ок", []string{"ка", "ко"}, Noun},
+	{"ы", []string{"а", ""}, Noun},
+	{"и", []string{"а", "я", "ь", "", "о", "й"}, Noun},
+	{"а", []string{"", "о"}, Noun},
+	{"я", []string{"ь", "й", "е"}, Noun},
+	{"у", []string{"", "а", "о"}, Noun},
+	{"ю", []string{"ь", "я", "й", "е"}, Noun},
+	{"е", []string{"", "а", "о", "я"}, Noun},
+	// Zero ending: the genitive plural of -а and -о nouns, «яблок», «книг».
+	// Only tried on a word ending in a consonant.
+	{"", []string{"о", "а"}, Noun},
+
+	// Verbs: past tense, present/future of both conjugations, imperative.
+	{"ешь", []string{"ть", "ить"}, Verb},
+	{"ете", []string{"ть"}, Verb},
+	{"ите", []string{"ить", "еть"}, Verb},
+	{"йте", []string{"ть"}, Verb},
+	{"ишь", []string{"ить", "еть", "ать"}, Verb},
+	{"ла", []string{"ть"}, Verb},
+	{"ло", []string{"ть"}, Verb},
+	{"ли", []string{"ть"}, Verb},
+	{"ет", []string{"ть"}, Verb},
+	{"ем", []string{"ть"}, Verb},
+	{"ют", []string{"ть"}, Verb},
+	{"ит", []string{"ить", "еть"}, Verb},
+	{"им", []string{"ить", "еть"}, Verb},
+	{"ят", []string{"ить", "еть"}, Verb},
+	{"ат", []string{"ать"}, Verb},
+	{"ут", []string{"ть"}, Verb},
+	{"л", []string{"ть"}, Verb},
+	{"ю", []string{"ть", "ить"}, Verb},
+	{"у", []string{"ть", "ить", "ать"}, Verb},
+	{"й", []string{"ть"}, Verb},
+	{"и", []string{"ить"}, Verb},
+}
+
+// exceptions holds the forms no ending rule reaches: suppletive stems
+// («шёл» → «идти», «люди» → «человек»), irregular conjugations and
+// comparatives. Keys are folded like Lemma.Word.
+var exceptions = map[string][]Lemma{}
+
+func init() {
+	add := func(lemma string, pos POS, forms ...string) {
+		for _, f := range forms {
+			exceptions[f] = append(exceptions[f], Lemma{Word: lemma, POS: pos})
+		}
+	}
+	add("идти", Verb, "шел", "шла", "шло", "шли", "иду", "идешь", "идет", "идем", "идете", "идут", "иди", "идите")
+	add("пойти", Verb, "пошел", "пошла", "пошло", "пошли", "пойду", "пойдешь", "пойдет", "пойдем", "пойдете", "пойдут")
+	add("прийти", Verb, "пришел", "пришла", "пришло", "пришли", "приду", "придешь", "придет", "придем", "придете", "придут")
+	add("уйти", Verb, "ушел", "ушла", "ушло", "ушли", "уйду", "уйдешь", "уйдет", "уйдем", "уйдете", "уйдут")
+	add("выйти", Verb, "вышел", "вышла", "вышло", "вышли", "выйду", "выйдешь", "выйдет", "выйдем", "выйдете", "выйдут")
+	add("войти", Verb, "вошел", "вошла", "вошло", "вошли", "войду", "войдешь", "войдет", "войдем", "войдете", "войдут")
+	add("найти", Verb, "нашел", "нашла", "нашло", "нашли", "найду", "найдешь", "найдет", "найдем", "найдете", "найдут")
+	add("быть", Verb, "был", "была", "было", "были", "буду", "будешь", "будет", "будем", "будете", "будут", "будь")
+	add("есть", Verb, "ел", "ела", "ело", "ели", "ем", "ешь", "ест", "едим", "едите", "едят")
+	add("дать", Verb, "дам", "дашь", "даст", "дадим", "дадите", "дадут")
+	add("хотеть", Verb, "хочу", "хочешь", "хочет", "хотим", "хотите", "хотят")
+	add("мочь", Verb, "мог", "могла", "могло", "могли", "могу", "можешь", "может", "можем", "можете", "могут")
+	add("ехать", Verb, "еду", "едешь", "едет", "едем", "едете", "едут", "езжай")
+	add("жить", Verb, "живу", "живешь", "живет", "живем", "живете", "живут")
+	add("писать", Verb, "пишу", "пишешь", "пишет", "пишем", "пишете", "пишут")
+	add("брать", Verb, "беру", "берешь", "берет", "берем", "берете", "берут")
+	add("взять", Verb, "возьму", "возьмешь", "возьмет", "возьмем", "возьмете", "возьмут", "взял", "взяла", "взяли")
+	add("спать", Verb, "сплю", "спишь", "спит", "спим", "спите", "спят")
+	add("пить", Verb, "пью", "пьешь", "пьет", "пьем", "пьете", "пьют")
+	add("сказать", Verb, "скажу", "скажешь", "скажет", "скажем", "скажете", "скажут")
+
+	add("человек", Noun, "люди", "людей", "людям", "людьми", "людях")
+	add("ребенок", Noun, "дети", "детей", "детям", "детьми", "детях")
+	add("мать", Noun, "матери", "матерью", "матерей", "матерям", "матерями", "матерях")
+	add("дочь", Noun, "дочери", "дочерью", "дочерей", "дочерям", "дочерьми", "дочерях")
+	add("сын", Noun, "сыновья", "сыновей", "сыновьям", "сыновьями", "сыновьях")
+	add("брат", Noun, "братья", "братьев", "братьям", "братьями", "братьях")
+	add("друг", Noun, "друзья", "друзей", "друзьям", "друзьями", "друзьях")
+	add("ухо", Noun, "уши", "ушей", "ушам", "ушами", "ушах")
+	add("небо", Noun, "небеса", "небес")
+	add("время", Noun, "времени", "временем", "времена", "времен")
+	add("имя", Noun, "имени", "именем", "имена", "имен")
+	add("путь", Noun, "пути", "путем")
+	add("церковь", Noun, "церкви", "церковью")
+	add("любовь", Noun, "любви", "любовью")
+
+	add("хороший", Adjective, "лучше", "лучший")
+	add("хорошо", Adverb, "лучше")
+	add("плохой", Adjective, "хуже", "худший")
+	add("плохо", Adverb, "хуже")
+	add("большой", Adjective, "больше", "больший")
+	add("много", Adverb, "больше")
+	add("маленький", Adjective, "меньше", "меньший")
+	add("мало", Adverb, "меньше")
+}
+
+// Lemmas returns candidate dictionary forms of word, most likely first:
+// table entries, then rule-derived candidates by ending length. An infinitive
+// comes back as itself, ahead of whatever the rules make of it. Non-Cyrillic
+// input and phrases yield nothing.
+func Lemmas(word string) []Lemma {
+	w := fold(word)
+	if w == "" || !isCyrillicWord(w) {
+		return nil
+	}
+
+	var out []Lemma
+	seen := map[Lemma]bool{}
+	push := func(l Lemma) {
+		if l.Word == "" || seen[l] {
+			return
+		}
+		seen[l] = true
+		out = append(out, l)
+	}
+
+	for _, l := range exceptions[w] {
+		push(l)
+	}
+
+	// Reflexive verbs: undo the verb under -ся/-сь and put the particle back.
+	// «учится» → «учит» → «учить» → «учиться».
+	for _, particle := range []string{"ся", "сь"} {
+		stem, ok := strings.CutSuffix(w, particle)
+		if !ok || utf8.RuneCountInString(stem) <= minStemRunes {
+			continue
+		}
+		for _, l := range exceptions[stem] {
+			if l.POS == Verb {
+				push(Lemma{Word: l.Word + "ся", POS: Verb})
+			}
+		}
+		if isInfinitive(stem) {
+			push(Lemma{Word: w, POS: Verb})
+		}
+		for _, l := range applyRules(stem) {
+			if l.POS == Verb {
+				push(Lemma{Word: l.Word + "ся", POS: Verb})
+			}
+		}
+	}
+
+	if isInfinitive(w) {
+		push(Lemma{Word: w, POS: Verb})
+	}
+	for _, l := range applyRules(w) {
+		push(l)
+	}
+	return out
+}
+
+// applyRules returns every rule-derived candidate, longest ending first.
+func applyRules(w string) []Lemma {
+	var out []Lemma
+	for _, r := range rulesByLength {
+		stem, ok := strings.CutSuffix(w, r.ending)
+		if !ok || utf8.RuneCountInString(stem) < minStemRunes {
+			continue
+		}
+		if r.ending == "" && !endsInConsonant(w) {
+			continue
+		}
+		for _, suffix := range r.lemmas {
+			if suffix == "" && utf8.RuneCountInString(stem) <= minStemRunes {
+				continue
+			}
+			if lemma := stem + suffix; lemma != w {
+				out = append(out, Lemma{Word: lemma, POS: r.pos})
+			}
+		}
+	}
+	return out
+}
+
+// rulesByLength is rules in trial order. Stable within one length, so the
+// table's own order decides between rules of equal length.
+var rulesByLength = func() []rule {
+	out := make([]rule, 0, len(rules))
+	for n := 4; n >= 0; n-- {
+		for _, r := range rules {
+			if utf8.RuneCountInString(r.ending) == n {
+				out = append(out, r)
+			}
+		}
+	}
+	return out
+}()
+
+func endsInConsonant(w string) bool {
+	last, _ := utf8.DecodeLastRuneInString(w)
+	return !strings.ContainsRune("аеиоуыэюяйьъ", last)
+}
+
+func isInfinitive(w string) bool {
+	return strings.HasSuffix(w, "ть") || strings.HasSuffix(w, "ти") || strings.HasSuffix(w, "чь")
+}
+
+// fold lowercases and folds ё, so «Шёл» and «шел» are one key.
+func fold(word string) string {
+	w := strings.ToLower(strings.TrimSpace(word))
+	return strings.ReplaceAll(w, "ё", "е")
+}
+
+func isCyrillicWord(w string) bool {
+	for _, r := range w {
+		if (r < 'а' || r > 'я') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package morph
+
+import "testing"
+
+// Each row is a form a user might type and the lemma the dictionary stores it
+// under. Lemmas may propose other candidates too — the dictionary decides — so
+// the test is that the right one is among them with the right part of speech.
+func TestLemmas(t *testing.T) {
+	tests := []struct {
+		form  string
+		lemma string
+		pos   POS
+	}{
+		// Nouns, all three declensions.
+		{"яблоками", "яблоко", Noun},
+		{"яблоки", "яблоко", Noun},
+		{"яблок", "яблоко", Noun},
+		{"стола", "стол", Noun},
+		{"столом", "стол", Noun},
+		{"столах", "стол", Noun},
+		{"столов", "стол", Noun},
+		{"кошкой", "кошка", Noun},
+		{"кошек", "кошка", Noun},
+		{"кошку", "кошка", Noun},
+		{"земле", "земля", Noun},
+		{"землями", "земля", Noun},
+		{"ночью", "ночь", Noun},
+		{"ночей", "ночь", Noun},
+		{"коня", "конь", Noun},
+		{"музеев", "музей", Noun},
+		{"окна", "окно", Noun},
+		{"полей", "поле", Noun},
+		{"армии", "армия", Noun},
+		{"зданием", "здание", Noun},
+		{"девочек", "девочка", Noun},
+
+		// Adjectives.
+		{"красного", "красный", Adjective},
+		{"красная", "красный", Adjective},
+		{"красными", "красный", Adjective},
+		{"синего", "синий", Adjective},
+		{"большую", "большой", Adjective},
+		{"новых", "новый", Adjective},
+
+		// Verbs: past, present, imperative, reflexive.
+		{"читал", "читать", Verb},
+		{"читала", "читать", Verb},
+		{"читаю", "читать", Verb},
+		{"читаешь", "читать", Verb},
+		{"читают", "читать", Verb},
+		{"говорит", "говорить", Verb},
+		{"говорю", "говорить", Verb},
+		{"говори", "говорить", Verb},
+		{"смотрят", "смотреть", Verb},
+		{"держат", "держать", Verb},
+		{"читай", "читать", Verb},
+		{"учится", "учиться", Verb},
+		{"училась", "учиться", Verb},
+		{"смеялись", "смеяться", Verb},
+		{"жил", "жить", Verb},
+		{"читать", "читать", Verb},
+
+		// Suppletive and irregular forms no suffix rule can reach.
+		{"шёл", "идти", Verb},
+		{"Шла", "идти", Verb},
+		{"пошёл", "пойти", Verb},
+		{"ели", "есть", Verb},
+		{"хочу", "хотеть", Verb},
+		{"могут", "мочь", Verb},
+		{"люди", "человек", Noun},
+		{"детей", "ребенок", Noun},
+		{"матери", "мать", Noun},
+		{"друзья", "друг", Noun},
+		{"лучше", "хороший", Adjective},
+		{"хуже", "плохо", Adverb},
+	}
+	for _, tt := range tests {
+		got := Lemmas(tt.form)
+		found := false
+		for _, l := range got {
+			if l.Word == tt.lemma && l.POS == tt.pos {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Lemmas(%q) = %v, want %s (%s) among them", tt.form, got, tt.lemma, tt.pos)
+		}
+	}
+}
+
+// The table answers first: «шёл» has to try «идти» before any rule-made guess
+// spends a lookup.
+func TestLemmas_ExceptionsComeFirst(t *testing.T) {
+	got := Lemmas("шёл")
+	if len(got) == 0 || got[0].Word != "идти" {
+		t.Fatalf("Lemmas(шёл) = %v, want идти first", got)
+	}
+}
+
+// The prefix trimmer this replaces chopped «стола» to «сто»; no rule may
+// strip a word below a two-letter stem.
+func TestLemmas_NoFragments(t *testing.T) {
+	for _, form := range []string{"стола", "ели", "они"} {
+		for _, l := range Lemmas(form) {
+			if len([]rune(l.Word)) < 3 {
+				t.Errorf("Lemmas(%q) proposed fragment %q", form, l.Word)
+			}
+		}
+	}
+}
+
+func TestLemmas_OnlyCyrillicWords(t *testing.T) {
+	for _, in := range []string{"", "   ", "apple", "красное яблоко", "г1ала"} {
+		if got := Lemmas(in); got != nil {
+			t.Errorf("Lemmas(%q) = %v, want nil", in, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Package srs schedules spaced-repetition reviews with SM-2, the algorithm
+// behind SuperMemo 2 and, in spirit, Anki.
+//
+// SM-2 grades recall on a 0–5 scale. A multiple-choice answer carries one bit —
+// right or wrong — so the scale collapses to two points: a right answer is a
+// 4 («correct, after some thought»), a wrong one a 1. At 4 the ease factor
+// holds steady and the interval grows by it; at 1 the card goes back to the
+// start and its ease drops, so a word that keeps slipping comes round more
+// often for good.
+//
+// A lapsed card is not left for tomorrow, as SM-2 has it: it is due again in
+// RelearnDelay, so the word gets another try while the right answer is fresh.
+package srs
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// DefaultEase is the ease factor a new card starts with.
+	DefaultEase = 2.5
+	// MinEase is SM-2's floor: below it a hard card would come due so often
+	// that it crowds out the rest.
+	MinEase = 1.3
+	// RelearnDelay is how soon a card answered wrong comes round again.
+	RelearnDelay = 10 * time.Minute
+
+	correctQuality = 4
+	wrongQuality   = 1
+)
+
+// Card is one word's review state.
+type Card struct {
+	Ease         float64
+	IntervalDays int
+	Repetitions  int // right answers in a row
+	Lapses       int // wrong answers, ever
+	Due          time.Time
+}
+
+// New returns the state of a card never reviewed, due at now.
+func New(now time.Time) Card {
+	return Card{Ease: DefaultEase, Due: now}
+}
+
+// Review grades one answer given at now and returns the card's next state.
+// The first right answer schedules the card a day out, the second six days
+// out, and each after that the previous interval times the ease.
+func (c Card) Review(correct bool, now time.Time) Card {
+	if c.Ease < MinEase {
+		c.Ease = DefaultEase // a zero Card: never scheduled
+	}
+	q := float64(wrongQuality)
+	if correct {
+		q = correctQuality
+	}
+	c.Ease = math.Max(MinEase, c.Ease+0.1-(5-q)*(0.08+(5-q)*0.02))
+
+	if !correct {
+		c.Repetitions = 0
+		c.IntervalDays = 0
+		c.Lapses++
+		c.Due = now.Add(RelearnDelay)
+		return c
+	}
+
+	c.Repetitions++
+	switch c.Repetitions {
+	case 1:
+		c.IntervalDays = 1
+	case 2:
+		c.IntervalDays = 6
+	default:
+		c.IntervalDays = int(math.Round(float64(max(c.IntervalDays, 1)) * c.Ease))
+	}
+	c.Due = now.AddDate(0, 0, c.IntervalDays)
+	return c
+}
@@ -0,0 +1,66 @@
+package srs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReview_IntervalsGrowWithRightAnswers(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	c := New(now)
+
+	var got []int
+	for range 5 {
+		c = c.Review(true, now)
+		got = append(got, c.IntervalDays)
+		now = c.Due
+	}
+	want := []int{1, 6, 15, 38, 95}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("intervals = %v, want %v", got, want)
+		}
+	}
+	if c.Ease != DefaultEase {
+		t.Fatalf("ease = %v after right answers only, want it unchanged at %v", c.Ease, DefaultEase)
+	}
+	if c.Repetitions != 5 || c.Lapses != 0 {
+		t.Fatalf("repetitions/lapses = %d/%d, want 5/0", c.Repetitions, c.Lapses)
+	}
+}
+
+func TestReview_LapseResetsAndComesBackSoon(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	c := New(now).Review(true, now).Review(true, now)
+
+	c = c.Review(false, now)
+	if c.Repetitions != 0 || c.IntervalDays != 0 || c.Lapses != 1 {
+		t.Fatalf("after a lapse: %+v, want the card back at the start", c)
+	}
+	if !c.Due.Equal(now.Add(RelearnDelay)) {
+		t.Fatalf("due %v, want %v", c.Due, now.Add(RelearnDelay))
+	}
+	if c.Ease >= DefaultEase {
+		t.Fatalf("ease = %v, want it lowered by the lapse", c.Ease)
+	}
+
+	// The ease has a floor however often the word slips.
+	for range 10 {
+		c = c.Review(false, now)
+	}
+	if c.Ease != MinEase {
+		t.Fatalf("ease = %v after many lapses, want the %v floor", c.Ease, MinEase)
+	}
+
+	// The first right answer after a lapse starts the ladder again.
+	if c = c.Review(true, now); c.IntervalDays != 1 {
+		t.Fatalf("interval after relearning = %d, want 1", c.IntervalDays)
+	}
+}
+
+func TestReview_ZeroCardGetsDefaultEase(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	if c := (Card{}).Review(true, now); c.Ease != DefaultEase || c.IntervalDays != 1 {
+		t.Fatalf("zero card reviewed = %+v, want default ease and a one-day interval", c)
+	}
+}
@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"html"
+	"strings"
+)
+
+// AnswerGrade is how a typed quiz answer compares to the answers accepted for
+// the question.
+type AnswerGrade struct {
+	Correct bool
+	// Closest is the accepted answer the typed one is nearest to, as listed;
+	// on a right answer, the one it matched.
+	Closest  string
+	Distance int // edits between the two, under FuzzyKey
+	// NearMiss marks a wrong answer close enough to Closest to be a slip in
+	// spelling rather than a different word.
+	NearMiss bool
+}
+
+// GradeAnswer grades a typed answer against every accepted spelling. Case,
+// ё, palochka stand-ins and stress marks are folded away first (FuzzyKey):
+// «г1ала» typed on a phone without the letter is right, not a near miss.
+func GradeAnswer(typed string, accepted []string) AnswerGrade {
+	key := FuzzyKey(typed)
+	grade := AnswerGrade{Distance: -1}
+	if key == "" {
+		return grade
+	}
+	for _, a := range accepted {
+		d := Levenshtein(key, FuzzyKey(a))
+		if grade.Distance < 0 || d < grade.Distance {
+			grade.Closest, grade.Distance = a, d
+		}
+	}
+	if grade.Distance < 0 {
+		return grade
+	}
+	grade.Correct = grade.Distance == 0
+	grade.NearMiss = !grade.Correct && grade.Distance <= nearMissEdits(FuzzyKey(grade.Closest))
+	return grade
+}
+
+// nearMissEdits is how many edits still read as a misspelling of want: one
+// for a short word, where two would already make another word, two past six
+// letters.
+func nearMissEdits(want string) int {
+	if len([]rune(want)) > 6 {
+		return 2
+	}
+	return 1
+}
+
+// AnswerVariants splits a dictionary side listing several spellings or
+// senses — «дом, жилище», «цӏа; хӏусам» — into the answers a typed quiz
+// accepts for it, parenthesized notes dropped.
+func AnswerVariants(side string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(stripParens(side), func(r rune) bool {
+		return r == ',' || r == ';' || r == '/'
+	}) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// SpellingDiff renders want as HTML with the edits that turn typed into it
+// marked: letters typed that do not belong struck through, letters missing or
+// mistyped underlined. Both sides go through FuzzyKey, so the diff shows no
+// mistake the grading forgave.
+func SpellingDiff(typed, want string) string {
+	a, b := []rune(FuzzyKey(typed)), []rune(FuzzyKey(want))
+
+	// dist[i][j] is the edit distance between a[i:] and b[j:], so the walk
+	// below can go front to back.
+	dist := make([][]int, len(a)+1)
+	for i := range dist {
+		dist[i] = make([]int, len(b)+1)
+	}
+	for i := len(a); i >= 0; i-- {
+		for j := len(b); j >= 0; j-- {
+			switch {
+			case i == len(a):
+				dist[i][j] = len(b) - j
+			case j == len(b):
+				dist[i][j] = len(a) - i
+			default:
+				cost := 1
+				if a[i] == b[j] {
+					cost = 0
+				}
+				dist[i][j] = min(dist[i+1][j]+1, dist[i][j+1]+1, dist[i+1][j+1]+cost)
+			}
+		}
+	}
+
+	var out strings.Builder
+	mark := func(tag string, r rune) {
+		out.WriteString("<" + tag + ">" + html.EscapeString(string(r)) + "</" + tag + ">")
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j] && dist[i][j] == dist[i+1][j+1]:
+			out.WriteString(html.EscapeString(string(b[j])))
+			i, j = i+1, j+1
+		case i < len(a) && j < len(b) && dist[i][j] == dist[i+1][j+1]+1:
+			mark("u", b[j]) // mistyped
+			i, j = i+1, j+1
+		case j < len(b) && dist[i][j] == dist[i][j+1]+1:
+			mark("u", b[j]) // missing
+			j++
+		default:
+			mark("s", a[i]) // extra
+			i++
+		}
+	}
+	return diffRuns.Replace(out.String())
+}
+
+// diffRuns joins adjacent marks into one run, «<u>ӏа</u>» rather than a tag
+// pair per letter.
+var diffRuns = strings.NewReplacer("</u><u>", "", "</s><s>", "")
@@ -0,0 +1,67 @@
+package tools
+
+import "testing"
+
+func TestGradeAnswer(t *testing.T) {
+	accepted := []string{"гӏала", "гӏалин"}
+	cases := []struct {
+		typed    string
+		correct  bool
+		nearMiss bool
+		closest  string
+	}{
+		{"гӏала", true, false, "гӏала"},
+		{"Г1ала", true, false, "гӏала"},   // palochka stand-in and case fold away
+		{"гӏа́ла", true, false, "гӏала"},  // so does stress
+		{"гӏалин", true, false, "гӏалин"}, // any accepted form
+		{"гала", false, true, "гӏала"},    // missing palochka: a slip
+		{"хи", false, false, ""},
+		{"", false, false, ""},
+	}
+	for _, c := range cases {
+		g := GradeAnswer(c.typed, accepted)
+		if g.Correct != c.correct || g.NearMiss != c.nearMiss || (c.closest != "" && g.Closest != c.closest) {
+			t.Errorf("GradeAnswer(%q) = %+v, want correct=%v nearMiss=%v closest=%q", c.typed, g, c.correct, c.nearMiss, c.closest)
+		}
+	}
+}
+
+func TestNearMissScalesWithLength(t *testing.T) {
+	if g := GradeAnswer("ца", []string{"цӏа"}); !g.NearMiss {
+		t.Errorf("one edit on a short word = %+v, want a near miss", g)
+	}
+	if g := GradeAnswer("ц", []string{"цӏа"}); g.NearMiss {
+		t.Errorf("two edits on a short word = %+v, want plain wrong", g)
+	}
+	if g := GradeAnswer("кехатт", []string{"кехаташ"}); !g.NearMiss || g.Distance != 2 {
+		t.Errorf("two edits on a long word = %+v, want a near miss", g)
+	}
+}
+
+func TestAnswerVariants(t *testing.T) {
+	got := AnswerVariants("дом (жилой), жилище; хата")
+	want := []string{"дом", "жилище", "хата"}
+	if len(got) != len(want) {
+		t.Fatalf("AnswerVariants = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("AnswerVariants = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestSpellingDiff(t *testing.T) {
+	cases := []struct{ typed, want, diff string }{
+		{"гала", "гӏала", "г<u>ӏ</u>ала"},
+		{"гӏаала", "гӏала", "гӏа<s>а</s>ла"},
+		{"гӏоло", "гӏала", "гӏ<u>а</u>л<u>а</u>"},
+		{"х", "цӏа", "<u>цӏа</u>"}, // mistyped, then missing
+		{"цӏаа", "цӏа", "цӏа<s>а</s>"},
+	}
+	for _, c := range cases {
+		if got := SpellingDiff(c.typed, c.want); got != c.diff {
+			t.Errorf("SpellingDiff(%q, %q) = %q, want %q", c.typed, c.want, got, c.diff)
+		}
+	}
+}
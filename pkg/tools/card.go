@@ -38,12 +38,23 @@ const (
 // FormatCard renders one lookup as a single card.
 func FormatCard(query string, pairs []models.TranslationPairs) string {
 	c := collect(query, pairs)
-	if len(c.blocks) == 0 && len(c.neighbours) == 0 {
+	if len(c.blocks) == 0 && len(c.neighbours) == 0 && len(c.found) == 0 {
 		return ""
 	}
 	return c.render()
 }
 
+// ChechenHead returns the headword the card for query opens with, spelled as
+// the highest-rated dictionary spells it — stress marks and all. ok is false
+// when the card opens on a Russian headword, or on none.
+func ChechenHead(query string, pairs []models.TranslationPairs) (head string, ok bool) {
+	c := collect(query, pairs)
+	if len(c.blocks) == 0 || !c.blocks[0].cheHead || c.blocks[0].head == "" {
+		return "", false
+	}
+	return Clean(c.blocks[0].head), true
+}
+
 // block is one headword-and-homonym: the unit a card repeats.
 type block struct {
 	head     string
@@ -61,6 +72,10 @@ type example struct{ chechen, russian string }
 type collected struct {
 	blocks     []*block
 	neighbours []string
+	// found holds the places the word turns up when no entry is about it:
+	// examples and glosses that mention it. Rendered only when blocks is
+	// empty — beside a real answer they are noise.
+	found []example
 }
 
 // collect turns ranked pairs into blocks. Which door a pair takes — headword,
@@ -128,6 +143,11 @@ func collect(query string, pairs []models.TranslationPairs) collected {
 
 			case strings.HasPrefix(NormalizeSearch(original), key):
 				c.neighbours = append(c.neighbours, original)
+
+			// The word is part of a longer headword — «в саду растут яблони».
+			// The glosses, not the raw article, stand beside it.
+			case containsWord(original, key) && len(glosses) > 0:
+				c.found = append(c.found, example{chechen: strings.Join(glosses, ", "), russian: original})
 			}
 			continue
 		}
@@ -154,6 +174,11 @@ func collect(query string, pairs []models.TranslationPairs) collected {
 		// Never a card, worth one line at the foot.
 		case p.EntryType != "TEXT" && strings.HasPrefix(NormalizeSearch(original), key):
 			c.neighbours = append(c.neighbours, original)
+
+		// The word sits inside a longer gloss — «яблони» in «Ӏаж: яблоко, плод
+		// яблони». Not an answer, but a lead when nothing better exists.
+		case containsWord(original, key) || containsWord(translate, key):
+			c.found = append(c.found, orient(p, original, translate))
 		}
 	}
 
@@ -171,6 +196,9 @@ func collect(query string, pairs []models.TranslationPairs) collected {
 	}
 	if len(kept) > 0 {
 		kept[0].examples = append(kept[0].examples, orphaned...)
+		c.found = nil
+	} else {
+		c.found = dedupExamples(append(orphaned, c.found...), key)
 	}
 	for _, b := range kept {
 		b.examples = dedupExamples(b.examples, NormalizeSearch(b.head))
@@ -215,6 +243,17 @@ func (c collected) render() string {
 	for _, b := range c.blocks {
 		out = append(out, b.render())
 	}
+	if len(c.found) > 0 {
+		found := c.found
+		if len(found) > maxCardExampleLines {
+			found = found[:maxCardExampleLines]
+		}
+		lines := []string{"<i>найдено в примерах:</i>"}
+		for _, ex := range found {
+			lines = append(lines, FormatExample(ex.chechen, ex.russian))
+		}
+		out = append(out, strings.Join(lines, "\n"))
+	}
 	if len(c.neighbours) > 0 {
 		names := c.neighbours
 		if len(names) > maxNeighbours {
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"sort"
+	"strings"
+)
+
+// FuzzyKey is the spelling fuzzy matching compares: the search normalization
+// (case, ё, palochka stand-ins) with stress marks removed. Without the fold
+// «г1ала» would sit two edits from «гӏала» — the digit and a missing letter —
+// and a stressed headword would be one edit from itself.
+func FuzzyKey(text string) string {
+	return strings.ReplaceAll(NormalizeSearch(text), "́", "")
+}
+
+// Levenshtein returns the edit distance between a and b, counted in runes —
+// a Cyrillic letter is one edit, not the two its UTF-8 bytes would make it.
+func Levenshtein(a, b string) int {
+	if a == b {
+		return 0
+	}
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// FuzzyMatch is a lexicon word close to a query and how many edits away it is.
+type FuzzyMatch struct {
+	Word     string
+	Distance int
+}
+
+// BKTree indexes words by edit distance so a lookup visits only the branches
+// the triangle inequality leaves open, instead of measuring the query against
+// the whole lexicon. Words are keyed by FuzzyKey; spellings that share a key
+// are one node and answer with the first one added.
+type BKTree struct {
+	root *bkNode
+	size int
+}
+
+type bkNode struct {
+	key      string
+	word     string
+	children map[int]*bkNode
+}
+
+// Add indexes word. A word whose key is already present is ignored.
+func (t *BKTree) Add(word string) {
+	key := FuzzyKey(word)
+	if key == "" {
+		return
+	}
+	if t.root == nil {
+		t.root = &bkNode{key: key, word: word}
+		t.size++
+		return
+	}
+	n := t.root
+	for {
+		d := Levenshtein(key, n.key)
+		if d == 0 {
+			return
+		}
+		child, ok := n.children[d]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[int]*bkNode)
+			}
+			n.children[d] = &bkNode{key: key, word: word}
+			t.size++
+			return
+		}
+		n = child
+	}
+}
+
+// Len reports how many distinct keys the tree holds.
+func (t *BKTree) Len() int {
+	return t.size
+}
+
+// Search returns up to limit words within maxDist edits of query, nearest
+// first, ties broken by key so the same typo always gets the same buttons.
+func (t *BKTree) Search(query string, maxDist, limit int) []FuzzyMatch {
+	key := FuzzyKey(query)
+	if t.root == nil || key == "" {
+		return nil
+	}
+
+	type hit struct {
+		key string
+		FuzzyMatch
+	}
+	var hits []hit
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		d := Levenshtein(key, n.key)
+		if d <= maxDist {
+			hits = append(hits, hit{n.key, FuzzyMatch{Word: n.word, Distance: d}})
+		}
+		for cd, child := range n.children {
+			if cd >= d-maxDist && cd <= d+maxDist {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Distance != hits[j].Distance {
+			return hits[i].Distance < hits[j].Distance
+		}
+		return hits[i].key < hits[j].key
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	out := make([]FuzzyMatch, len(hits))
+	for i, h := range hits {
+		out[i] = h.FuzzyMatch
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"дитт", "дитт", 0},
+		{"дитт", "дит", 1},      // deletion
+		{"яблоко", "яблако", 1}, // substitution, counted per rune not byte
+		{"гӏала", "гала", 1},    // a dropped palochka is one letter
+		{"книга", "кинга", 2},   // a transposition is two edits
+		{"", "цӏа", 3},
+	}
+	for _, c := range cases {
+		if got := Levenshtein(c.a, c.b); got != c.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+		if got := Levenshtein(c.b, c.a); got != c.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want it symmetric", c.b, c.a, got)
+		}
+	}
+}
+
+// Spellings the search already treats as one word must be zero edits apart,
+// or a query typed with a stand-in would be offered its own headword.
+func TestFuzzyKey_FoldsLikeSearch(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"г1ала", "гӏала"},
+		{"ГIала", "гӏала"},
+		{"Ёлка", "елка"},
+		{"ди́тт", "дитт"},
+	} {
+		if a, b := FuzzyKey(pair[0]), FuzzyKey(pair[1]); a != b {
+			t.Errorf("FuzzyKey(%q) = %q, FuzzyKey(%q) = %q; want equal", pair[0], a, pair[1], b)
+		}
+	}
+}
+
+func TestBKTree_Search(t *testing.T) {
+	var tree BKTree
+	for _, w := range []string{"Гӏала", "Гала", "Яблоко", "Ябло́ня", "Дитт", "Дийца", "г1ала"} {
+		tree.Add(w)
+	}
+	if tree.Len() != 6 {
+		t.Fatalf("Len = %d, want 6 — г1ала is Гӏала", tree.Len())
+	}
+
+	got := tree.Search("яблака", 2, 5)
+	want := []FuzzyMatch{{"Яблоко", 2}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Search(яблака) = %+v, want %+v", got, want)
+	}
+
+	// Stress marks and palochka stand-ins cost nothing; the stand-in query is
+	// the headword itself, so it comes before the real near miss.
+	got = tree.Search("ябланя", 1, 5)
+	want = []FuzzyMatch{{"Ябло́ня", 1}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Search(ябланя) = %+v, want %+v", got, want)
+	}
+	got = tree.Search("г1ала", 1, 5)
+	want = []FuzzyMatch{{"Гӏала", 0}, {"Гала", 1}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Search(г1ала) = %+v, want %+v", got, want)
+	}
+
+	if got := tree.Search("гӏала", 1, 1); len(got) != 1 || got[0].Word != "Гӏала" {
+		t.Fatalf("limit ignored: %+v", got)
+	}
+	var empty BKTree
+	if got := empty.Search("дитт", 2, 5); got != nil {
+		t.Fatalf("empty tree returned %+v", got)
+	}
+}
+
+// The tree prunes by the triangle inequality; pruning that drops a word in
+// range is a silent bug, so compare against measuring every word.
+func TestBKTree_MatchesBruteForce(t *testing.T) {
+	letters := []rune("абвгдеклмнорстӏхцчя")
+	rng := rand.New(rand.NewPCG(1, 2))
+	word := func() string {
+		r := make([]rune, 3+rng.IntN(5))
+		for i := range r {
+			r[i] = letters[rng.IntN(len(letters))]
+		}
+		return string(r)
+	}
+
+	var tree BKTree
+	seen := map[string]bool{}
+	var words []string
+	for range 2000 {
+		w := word()
+		tree.Add(w)
+		if !seen[w] {
+			seen[w] = true
+			words = append(words, w)
+		}
+	}
+
+	for range 50 {
+		q := word()
+		for maxDist := 1; maxDist <= 2; maxDist++ {
+			var want []string
+			for _, w := range words {
+				if Levenshtein(q, w) <= maxDist {
+					want = append(want, w)
+				}
+			}
+			var got []string
+			for _, m := range tree.Search(q, maxDist, 0) {
+				got = append(got, m.Word)
+			}
+			slices.Sort(want)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Fatalf("Search(%q, %d) = %v, brute force = %v", q, maxDist, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"chetoru/internal/models"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxGlossRunes keeps an interlinear gloss to one line on a phone.
+const maxGlossRunes = 40
+
+// Gloss reads the answer to query the way FormatCard would and returns the
+// headword the card is about and its first sense, shortened for one line of
+// an interlinear gloss. ok is false when no entry is about query — neighbours
+// and mentions in examples make a card, not a gloss.
+func Gloss(query string, pairs []models.TranslationPairs) (head, sense string, ok bool) {
+	c := collect(query, pairs)
+	if len(c.blocks) == 0 {
+		return "", "", false
+	}
+	b := c.blocks[0]
+	for _, s := range b.senses {
+		if short := shortSense(s); short != "" {
+			return Clean(b.head), short, true
+		}
+	}
+	return "", "", false
+}
+
+// shortSense cuts a sense down to its first reading: no grammar labels, no
+// numbered senses after the first, no examples after a semicolon, no
+// parenthesized qualifiers, and no more than maxGlossRunes.
+func shortSense(s string) string {
+	s = stripLabels(Clean(s))
+	if parts := meaningRe.Split(s, -1); len(parts) > 0 {
+		for _, p := range parts {
+			if p = strings.TrimSpace(p); p != "" {
+				s = p
+				break
+			}
+		}
+	}
+	if i := strings.Index(s, ";"); i >= 0 {
+		s = s[:i]
+	}
+	if stripped := stripParens(s); stripped != "" {
+		s = stripped
+	}
+	s = strings.Trim(strings.TrimSpace(s), ",.")
+	if utf8.RuneCountInString(s) <= maxGlossRunes {
+		return s
+	}
+	// Over the limit: keep whole comma-separated readings while they fit,
+	// and cut mid-word only when the first one alone is too long.
+	var kept []string
+	n := 0
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if n+utf8.RuneCountInString(part) > maxGlossRunes {
+			break
+		}
+		kept = append(kept, part)
+		n += utf8.RuneCountInString(part) + 2
+	}
+	if len(kept) > 0 {
+		return strings.Join(kept, ", ")
+	}
+	return string([]rune(s)[:maxGlossRunes-1]) + "…"
+}
@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"chetoru/internal/models"
+	"testing"
+)
+
+func TestGloss(t *testing.T) {
+	pairs := []models.TranslationPairs{
+		{Original: "Куьг", Translate: "рука́ (кисть); по́дпись", OriginalLang: "CHE", TranslateLang: "RUS"},
+		{Original: "Куьгаш", Translate: "руки", OriginalLang: "CHE", TranslateLang: "RUS"},
+	}
+	head, sense, ok := Gloss("куьг", pairs)
+	if !ok || head != "Куьг" || sense != "рука́" {
+		t.Fatalf("Gloss(куьг) = %q, %q, %v; want the headword and its first reading", head, sense, ok)
+	}
+
+	// Typed the gloss: the headword is the answer, as on the card.
+	head, sense, ok = Gloss("город", []models.TranslationPairs{
+		{Original: "Гӏала", Translate: "город", OriginalLang: "CHE", TranslateLang: "RUS"},
+	})
+	if !ok || head != "город" || sense != "Гӏала" {
+		t.Fatalf("Gloss(город) = %q, %q, %v", head, sense, ok)
+	}
+
+	// A neighbour is no gloss.
+	if _, _, ok := Gloss("дом", []models.TranslationPairs{
+		{Original: "Домбра", Translate: "домбра", OriginalLang: "RUS", TranslateLang: "CHE"},
+	}); ok {
+		t.Fatal("Gloss(дом) accepted a neighbour")
+	}
+}
+
+func TestShortSense(t *testing.T) {
+	cases := map[string]string{
+		"несов. 1) идти 2) ходить":       "идти",
+		"рука; куьг бетта - пожать руку": "рука",
+		"(учреждение) дом":               "дом",
+		"большой, крупный, великий, огромный, громадный, значительный": "большой, крупный, великий, огромный",
+		"достопримечательностьдостопримечательность":                   "достопримечательностьдостопримечательно…",
+	}
+	for in, want := range cases {
+		if got := shortSense(in); got != want {
+			t.Errorf("shortSense(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
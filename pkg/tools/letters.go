@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ChechenLetters splits a word into the letters of the Chechen alphabet,
+// counting the ones spelled with two characters — кх, аь, гӏ and the rest of
+// the digraphs the transcription knows — as one: «кхаьна» is four letters,
+// кх-аь-н-а. The word is folded as FuzzyKey folds it first. It returns nil
+// for anything but a single Cyrillic word.
+func ChechenLetters(word string) []string {
+	key := FuzzyKey(word)
+	if key == "" {
+		return nil
+	}
+	var letters []string
+	for i := 0; i < len(key); {
+		r, size := utf8.DecodeRuneInString(key[i:])
+		if !unicode.Is(unicode.Cyrillic, r) {
+			return nil
+		}
+		if next, nsize := utf8.DecodeRuneInString(key[i+size:]); nsize > 0 && chechenDigraphs[string(r)+string(next)] {
+			size += nsize
+		}
+		letters = append(letters, key[i:i+size])
+		i += size
+	}
+	return letters
+}
+
+// chechenDigraphs are the two-character letters, taken from the phones.
+var chechenDigraphs = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, p := range phones {
+		if utf8.RuneCountInString(p.cyrillic) == 2 {
+			m[p.cyrillic] = true
+		}
+	}
+	return m
+}()
+
+// LetterMark is how a guessed letter compares to the hidden word's letter in
+// the same place, Wordle's three colours.
+type LetterMark int
+
+const (
+	LetterAbsent    LetterMark = iota // not in the word, or not that many times
+	LetterElsewhere                   // in the word, in another place
+	LetterRight                       // in the word, in this place
+)
+
+// ScoreGuess marks each letter of guess against answer, both split by
+// ChechenLetters and of equal length. A letter guessed more times than the
+// answer has it is marked elsewhere only as many times as the answer has
+// it left over after the right places, as in Wordle.
+func ScoreGuess(answer, guess []string) []LetterMark {
+	marks := make([]LetterMark, len(guess))
+	left := make(map[string]int)
+	for i, l := range answer {
+		if i < len(guess) && guess[i] == l {
+			marks[i] = LetterRight
+		} else {
+			left[l]++
+		}
+	}
+	for i, l := range guess {
+		if marks[i] != LetterRight && left[l] > 0 {
+			marks[i] = LetterElsewhere
+			left[l]--
+		}
+	}
+	return marks
+}
+
+// UpperLetter capitalizes a letter of ChechenLetters for display: only the
+// first character of a digraph, and never the palochka, which has no case
+// worth showing.
+func UpperLetter(l string) string {
+	r, size := utf8.DecodeRuneInString(l)
+	if r == 'ӏ' {
+		return l
+	}
+	return strings.ToUpper(string(r)) + l[size:]
+}
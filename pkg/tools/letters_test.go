@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChechenLetters(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"кхаьна", []string{"кх", "аь", "н", "а"}},
+		{"Гӏала", []string{"гӏ", "а", "л", "а"}},
+		{"г1ала", []string{"гӏ", "а", "л", "а"}}, // digit stand-in
+		{"къа́ршо", []string{"къ", "а", "р", "ш", "о"}},
+		{"цӏено", []string{"цӏ", "е", "н", "о"}},
+		{"ӏуьйре", []string{"ӏ", "уь", "й", "р", "е"}},
+		{"два слова", nil},
+		{"a-b", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := ChechenLetters(tt.word); !slices.Equal(got, tt.want) {
+			t.Errorf("ChechenLetters(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestScoreGuess(t *testing.T) {
+	R, E, A := LetterRight, LetterElsewhere, LetterAbsent
+	tests := []struct {
+		answer, guess string
+		want          []LetterMark
+	}{
+		{"къорза", "къорза", []LetterMark{R, R, R, R, R}},
+		{"къорза", "зорах", []LetterMark{E, R, R, E, A}},
+		// The answer has one а: the second guessed а is absent, and the one
+		// in the right place takes it before the one elsewhere.
+		{"къорза", "аааза", []LetterMark{A, A, A, R, R}},
+		// кх is one letter: a guessed к is not part of it.
+		{"кхаьна", "кхана", []LetterMark{R, A, R, R}},
+	}
+	for _, tt := range tests {
+		got := ScoreGuess(ChechenLetters(tt.answer), ChechenLetters(tt.guess))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ScoreGuess(%s, %s) = %v, want %v", tt.answer, tt.guess, got, tt.want)
+		}
+	}
+}
+
+func TestUpperLetter(t *testing.T) {
+	for l, want := range map[string]string{"кх": "Кх", "аь": "Аь", "ӏ": "ӏ", "а": "А"} {
+		if got := UpperLetter(l); got != want {
+			t.Errorf("UpperLetter(%q) = %q, want %q", l, got, want)
+		}
+	}
+}
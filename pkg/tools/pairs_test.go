@@ -92,6 +92,30 @@ func TestFormatCard_NeighboursGoToTheFooter(t *testing.T) {
 	}
 }
 
+// A word no entry is about can still turn up inside glosses and examples. With
+// no block to fold them into, those mentions are the card; beside a real block
+// they stay out.
+func TestFormatCard_FoundInExamplesWhenNoHeadword(t *testing.T) {
+	mentions := []models.TranslationPairs{
+		{Original: "Ӏаж", Translate: "яблоко, плод яблони", OriginalLang: "CHE", TranslateLang: "RUS", EntryType: "WORD"},
+		{Original: "в саду растут яблони", Translate: "бешахь Ӏежаш ду", OriginalLang: "RUS", TranslateLang: "CHE", EntryType: "TEXT"},
+	}
+	card := FormatCard("яблони", mentions)
+	if !strings.Contains(card, "найдено в примерах") {
+		t.Fatalf("mentions section missing:\n%s", card)
+	}
+	if !strings.Contains(card, "Ӏаж") || !strings.Contains(card, "бешахь Ӏежаш ду") {
+		t.Fatalf("a mention was dropped:\n%s", card)
+	}
+
+	withHead := append([]models.TranslationPairs{
+		{Original: "Яблони", Translate: "Ӏежаш", OriginalLang: "RUS", TranslateLang: "CHE", EntryType: "WORD"},
+	}, mentions...)
+	if card := FormatCard("яблони", withHead); strings.Contains(card, "найдено в примерах") {
+		t.Fatalf("mentions section shown beside a real answer:\n%s", card)
+	}
+}
+
 // The Russian–Chechen article is the one corpus that still needs parsing: its
 // glosses become senses and its tilde examples become example lines.
 func TestFormatCard_ArticleBecomesSensesAndExamples(t *testing.T) {
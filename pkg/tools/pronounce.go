@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Learners stumble on the letters Chechen spells with two: the palochka
+// digraphs (кӏ, пӏ, тӏ, цӏ, чӏ, гӏ, хӏ), the ones built on ъ and ь (къ, хь), and
+// the vowels softened by ь (аь, оь, уь). A transcription spells each of them
+// as one sound, in two registers: a Latin romanization a reader of English
+// can sound out, and an approximate IPA string for those who read IPA.
+//
+// Stress is the dictionary's combining acute (U+0301) after the vowel — the
+// mark stripStressMarks removes from cards. Here it is kept: the romanization
+// puts the accent on the vowel, the IPA puts ˈ before the stressed syllable.
+
+// phone is one Chechen letter, single or digraph, in both registers.
+type phone struct {
+	cyrillic, latin, ipa string
+	vowel                bool
+}
+
+// phones are matched longest first, on lowercased text with the palochka
+// stand-ins folded. ь standalone only softens a consonant in Russian loans,
+// so it is dropped from the romanization and kept as ʲ in the IPA.
+var phones = []phone{
+	{"юь", "yü", "jy", true}, {"яь", "yä", "jæ", true},
+	{"аь", "ä", "æ", true}, {"оь", "ö", "ø", true}, {"уь", "ü", "y", true},
+	{"гӏ", "gh", "ɣ", false}, {"кх", "q", "q", false}, {"къ", "q'", "qʼ", false},
+	{"кӏ", "k'", "kʼ", false}, {"пӏ", "p'", "pʼ", false}, {"тӏ", "t'", "tʼ", false},
+	{"хь", "h'", "ħ", false}, {"хӏ", "h", "h", false},
+	{"цӏ", "ts'", "t͡sʼ", false}, {"чӏ", "ch'", "t͡ʃʼ", false},
+	{"а", "a", "a", true}, {"е", "e", "e", true}, {"ё", "yo", "jo", true},
+	{"и", "i", "i", true}, {"о", "o", "o", true}, {"у", "u", "u", true},
+	{"ы", "y", "ɨ", true}, {"э", "e", "e", true}, {"ю", "yu", "ju", true},
+	{"я", "ya", "ja", true},
+	{"б", "b", "b", false}, {"в", "v", "v", false}, {"г", "g", "ɡ", false},
+	{"д", "d", "d", false}, {"ж", "zh", "ʒ", false}, {"з", "z", "z", false},
+	{"й", "y", "j", false}, {"к", "k", "k", false}, {"л", "l", "l", false},
+	{"м", "m", "m", false}, {"н", "n", "n", false}, {"п", "p", "p", false},
+	{"р", "r", "r", false}, {"с", "s", "s", false}, {"т", "t", "t", false},
+	{"ф", "f", "f", false}, {"х", "kh", "x", false}, {"ц", "ts", "t͡s", false},
+	{"ч", "ch", "t͡ʃ", false}, {"ш", "sh", "ʃ", false}, {"щ", "shch", "ɕː", false},
+	{"ъ", "'", "ʔ", false}, {"ь", "", "ʲ", false}, {"ӏ", "'", "ʕ", false},
+}
+
+// stressedLatin spells a stressed vowel with a precomposed accent where
+// Unicode has one; the rest take the combining mark.
+var stressedLatin = map[rune]string{
+	'a': "á", 'e': "é", 'i': "í", 'o': "ó", 'u': "ú", 'y': "ý",
+}
+
+// segment is one transcribed letter of the input, or a character the phones
+// do not cover (a space, a hyphen), which passes through as it is.
+type segment struct {
+	phone    *phone
+	raw      string
+	stressed bool
+	upper    bool
+	initial  bool // first letter of its word
+}
+
+// transcribe splits text into segments. Nothing comes back for text with no
+// Cyrillic letter: there is nothing Chechen in it to transcribe.
+func transcribe(text string) []segment {
+	if !strings.ContainsFunc(text, func(r rune) bool { return unicode.Is(unicode.Cyrillic, r) }) {
+		return nil
+	}
+	runes := []rune(strings.TrimSpace(Clean(text)))
+	// Case is read off the original; matching runs on the folded copy. Both
+	// fold rune for rune, so the indexes line up.
+	lower := []rune(foldPalochka(strings.ToLower(string(runes))))
+	if len(lower) != len(runes) {
+		runes = lower // a letter whose case changes length; lose the case
+	}
+
+	var out []segment
+	wordStart := true
+	for i := 0; i < len(lower); {
+		if lower[i] == '\u0301' {
+			i++ // a stray accent with no letter to sit on
+			continue
+		}
+		matched := false
+		for j := range phones {
+			p := &phones[j]
+			n, stressed, ok := matchPhone(lower[i:], p.cyrillic)
+			if !ok {
+				continue
+			}
+			out = append(out, segment{
+				phone:    p,
+				stressed: stressed && p.vowel,
+				upper:    unicode.IsUpper(runes[i]),
+				initial:  wordStart,
+			})
+			i += n
+			wordStart = false
+			matched = true
+			break
+		}
+		if !matched {
+			out = append(out, segment{raw: string(lower[i])})
+			wordStart = !unicode.IsLetter(lower[i])
+			i++
+		}
+	}
+	return out
+}
+
+// matchPhone reports whether s opens with letters, allowing the stress mark
+// anywhere inside a digraph ("а́ь" is as common as "аь́") and consuming any that
+// follow it. n counts the runes consumed.
+func matchPhone(s []rune, letters string) (n int, stressed, ok bool) {
+	for _, want := range letters {
+		for n < len(s) && s[n] == '\u0301' && n > 0 {
+			stressed = true
+			n++
+		}
+		if n >= len(s) || s[n] != want {
+			return 0, false, false
+		}
+		n++
+	}
+	for n < len(s) && s[n] == '\u0301' {
+		stressed = true
+		n++
+	}
+	return n, stressed, true
+}
+
+// Romanize spells Chechen Cyrillic in a learner's Latin: one Latin letter or
+// fixed cluster per Chechen letter, an apostrophe for the ejectives and the
+// palochka («кӏант» → «k'ant»), and an accent on the stressed vowel where
+// the dictionary marked one («къо́лам» → «q'ólam»). A word-initial е reads
+// «ye». Returns "" when text has nothing Cyrillic in it.
+func Romanize(text string) string {
+	segs := transcribe(text)
+	var sb strings.Builder
+	for _, s := range segs {
+		if s.phone == nil {
+			sb.WriteString(s.raw)
+			continue
+		}
+		latin := s.phone.latin
+		if s.initial && s.phone.cyrillic == "е" {
+			latin = "ye"
+		}
+		if s.stressed {
+			latin = accentLatin(latin)
+		}
+		if s.upper && latin != "" {
+			r := []rune(latin)
+			r[0] = unicode.ToUpper(r[0])
+			latin = string(r)
+		}
+		sb.WriteString(latin)
+	}
+	return sb.String()
+}
+
+// accentLatin puts the stress accent on the first vowel of a romanized
+// letter, which is its only vowel: «ya» → «yá», «ä» → «ä́».
+func accentLatin(latin string) string {
+	r := []rune(latin)
+	for i, c := range r {
+		if !strings.ContainsRune("aeiouyäöü", c) || (c == 'y' && i+1 < len(r)) {
+			continue
+		}
+		if acc, ok := stressedLatin[c]; ok {
+			return string(r[:i]) + acc + string(r[i+1:])
+		}
+		return string(r[:i+1]) + "\u0301" + string(r[i+1:])
+	}
+	return latin
+}
+
+// IPA gives an approximate broad transcription of Chechen Cyrillic
+// («гӏала» → «ɣala»). Vowel length, which the spelling does not record, is
+// not guessed at. A marked stress puts ˈ before the stressed syllable, taken
+// to open at the consonant right before its vowel. Returns "" when text has
+// nothing Cyrillic in it.
+func IPA(text string) string {
+	segs := transcribe(text)
+	parts := make([]string, len(segs))
+	for i, s := range segs {
+		switch {
+		case s.phone == nil:
+			parts[i] = s.raw
+		case s.initial && s.phone.cyrillic == "е":
+			parts[i] = "je"
+		default:
+			parts[i] = s.phone.ipa
+		}
+	}
+	for i, s := range segs {
+		if !s.stressed {
+			continue
+		}
+		at := i
+		if i > 0 && segs[i-1].phone != nil && !segs[i-1].phone.vowel {
+			at = i - 1
+		}
+		parts[at] = "ˈ" + parts[at]
+	}
+	return strings.Join(parts, "")
+}
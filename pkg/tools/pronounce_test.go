@@ -0,0 +1,29 @@
+package tools
+
+import "testing"
+
+func TestRomanizeAndIPA(t *testing.T) {
+	cases := []struct{ in, latin, ipa string }{
+		{"гӏала", "ghala", "ɣala"},
+		{"г1ала", "ghala", "ɣala"}, // digit stand-in for the palochka
+		{"Ӏаж", "'azh", "ʕaʒ"},
+		{"кӏант", "k'ant", "kʼant"},
+		{"къо́лам", "q'ólam", "ˈqʼolam"},
+		{"ба́ьрг", "bä́rg", "ˈbærɡ"}, // stress inside the digraph
+		{"баь́рг", "bä́rg", "ˈbærɡ"}, // and after it
+		{"хьаша", "h'asha", "ħaʃa"},
+		{"еса", "yesa", "jesa"},
+		{"Нохчийн мотт", "Nokhchiyn mott", "noxt͡ʃijn mott"},
+		{"дешна́", "deshná", "deʃˈna"},
+		{"яй", "yay", "jaj"},
+		{"apple", "", ""},
+	}
+	for _, c := range cases {
+		if got := Romanize(c.in); got != c.latin {
+			t.Errorf("Romanize(%q) = %q, want %q", c.in, got, c.latin)
+		}
+		if got := IPA(c.in); got != c.ipa {
+			t.Errorf("IPA(%q) = %q, want %q", c.in, got, c.ipa)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The two keyboard layouts, key for key. ЙЦУКЕН puts letters on the
+// punctuation keys too, which is why «ckjdj» needs ";" and "'" to spell
+// «слово» in general: «жёлтый» is ";`knsq».
+var (
+	qwertyKeys = []rune("qwertyuiop[]asdfghjkl;'zxcvbnm,.`")
+	jcukenKeys = []rune("йцукенгшщзхъфывапролджэячсмитьбюё")
+
+	toJcuken = make(map[rune]rune, len(qwertyKeys))
+	toQwerty = make(map[rune]rune, len(qwertyKeys))
+)
+
+func init() {
+	for i, q := range qwertyKeys {
+		toJcuken[q] = jcukenKeys[i]
+		toQwerty[jcukenKeys[i]] = q
+	}
+}
+
+// SwitchLayout retypes text on the other keyboard layout: what a user typed
+// with the wrong one active comes back as what they meant. Each character
+// crosses in its own direction, so a query that is half in each layout is
+// swapped whole; case carries over, anything off the letter keys stays.
+func SwitchLayout(text string) string {
+	var sb strings.Builder
+	sb.Grow(len(text) * 2)
+	for _, r := range text {
+		lower := unicode.ToLower(r)
+		swapped, ok := toJcuken[lower]
+		if !ok {
+			swapped, ok = toQwerty[lower]
+		}
+		switch {
+		case !ok:
+			sb.WriteRune(r)
+		case lower != r:
+			sb.WriteRune(unicode.ToUpper(swapped))
+		default:
+			sb.WriteRune(swapped)
+		}
+	}
+	return sb.String()
+}
+
+// composeLatin folds the decomposed spellings of the Chechen Latin letters —
+// a base letter plus a combining mark, which is what some keyboards send —
+// into the precomposed ones latinRules match.
+var composeLatin = strings.NewReplacer(
+	"a\u0308", "ä", "o\u0308", "ö", "u\u0308", "ü",
+	"c\u0307", "ċ", "c\u0327", "ç", "g\u0307", "ġ", "s\u0327", "ş",
+)
+
+func latinKey(text string) string {
+	return composeLatin.Replace(strings.ToLower(strings.TrimSpace(text)))
+}
+
+// latinRules spell the Chechen Latin alphabet of the 1990s in Cyrillic, plus
+// the ASCII habits of people writing without it (sh, ch, zh, gh). Matched
+// longest first; input goes through latinKey first, so a dotted or
+// cedilla letter is one rune wherever Unicode has a precomposed form.
+var latinRules = []struct{ latin, cyrillic string }{
+	// Combining sequences with no precomposed form.
+	{"ç̇", "чӏ"}, {"q̇", "кӏ"},
+	{"kh", "къ"}, {"ph", "пӏ"}, {"th", "тӏ"},
+	{"sh", "ш"}, {"ch", "ч"}, {"zh", "ж"}, {"gh", "гӏ"},
+	{"yo", "ё"}, {"yu", "ю"}, {"ya", "я"},
+	{"ä", "аь"}, {"ö", "оь"}, {"ü", "уь"},
+	{"ċ", "цӏ"}, {"ç", "ч"}, {"ġ", "гӏ"}, {"ħ", "хӏ"}, {"ş", "ш"}, {"ƶ", "ж"}, {"ꞑ", "н"},
+	{"a", "а"}, {"b", "б"}, {"c", "ц"}, {"d", "д"}, {"e", "е"}, {"f", "ф"},
+	{"g", "г"}, {"h", "хь"}, {"i", "и"}, {"j", "ӏ"}, {"k", "к"}, {"l", "л"},
+	{"m", "м"}, {"n", "н"}, {"o", "о"}, {"p", "п"}, {"q", "кх"}, {"r", "р"},
+	{"s", "с"}, {"t", "т"}, {"u", "у"}, {"v", "в"}, {"w", "в"}, {"x", "х"},
+	{"y", "й"}, {"z", "з"}, {"'", "ъ"}, {"’", "ъ"},
+}
+
+// chechenLatinMarks are letters only the Latin Chechen orthography uses. A
+// query carrying one is Chechen, not a mistyped Russian word, so the layout
+// swap is not worth trying.
+const chechenLatinMarks = "äöüċçġħşƶꞑ̇"
+
+// LatinToCyrillic respells a query written in Latin Chechen in Cyrillic.
+// ok is false when text is not Latin: any Cyrillic letter means the user
+// typed Cyrillic, and a stray digit-1 palochka is foldPalochka's job.
+func LatinToCyrillic(text string) (string, bool) {
+	s := latinKey(text)
+	if !isLatinQuery(s) {
+		return "", false
+	}
+	var sb strings.Builder
+	sb.Grow(len(s) * 2)
+	for len(s) > 0 {
+		matched := false
+		for _, rule := range latinRules {
+			if strings.HasPrefix(s, rule.latin) {
+				sb.WriteString(rule.cyrillic)
+				s = s[len(rule.latin):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			// A rune the rules do not know — a space, a hyphen, a digit.
+			r := []rune(s)[0]
+			sb.WriteRune(r)
+			s = s[len(string(r)):]
+		}
+	}
+	return sb.String(), true
+}
+
+// isLatinQuery reports whether s has a Latin letter and no Cyrillic one.
+func isLatinQuery(s string) bool {
+	latin := false
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			return false
+		case unicode.Is(unicode.Latin, r):
+			latin = true
+		}
+	}
+	return latin
+}
+
+// TransliterationCandidates returns the Cyrillic queries a Latin-script query
+// may have meant, most likely first: the keyboard-layout swap («ckjdj» →
+// «слово»), then the Latin Chechen reading («ġala» → «гӏала»). A query with a
+// Chechen Latin letter skips the swap. Cyrillic input, and a candidate that
+// would still be Latin, yield nothing — the dictionary is Cyrillic.
+func TransliterationCandidates(text string) []string {
+	s := latinKey(text)
+	if !isLatinQuery(s) {
+		return nil
+	}
+
+	var out []string
+	add := func(c string) {
+		if c == "" || !isCyrillicQuery(c) {
+			return
+		}
+		for _, seen := range out {
+			if seen == c {
+				return
+			}
+		}
+		out = append(out, c)
+	}
+	if !strings.ContainsAny(s, chechenLatinMarks) {
+		add(SwitchLayout(s))
+	}
+	if c, ok := LatinToCyrillic(s); ok {
+		add(c)
+	}
+	return out
+}
+
+// isCyrillicQuery reports whether s reads as a Cyrillic query: Cyrillic
+// letters, with nothing else but digits (a palochka stand-in), spaces and
+// hyphens. A Latin reading that left a semicolon behind is not one.
+func isCyrillicQuery(s string) bool {
+	letters := false
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			letters = true
+		case unicode.IsDigit(r), r == ' ', r == '-':
+		default:
+			return false
+		}
+	}
+	return letters
+}
@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSwitchLayout(t *testing.T) {
+	cases := map[string]string{
+		"ckjdj":  "слово",
+		";tknsq": "желтый",
+		"Ckjdj":  "Слово", // case carries over
+		"руддщ":  "hello", // and the other way round
+		"a-b 1":  "ф-и 1", // off-letter keys stay
+	}
+	for in, want := range cases {
+		if got := SwitchLayout(in); got != want {
+			t.Errorf("SwitchLayout(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLatinToCyrillic(t *testing.T) {
+	cases := map[string]string{
+		"ġala":    "гӏала",
+		"Ġala":    "гӏала",
+		"ġala":   "гӏала", // decomposed dot above
+		"ç̇ara":   "чӏара",
+		"ċa":      "цӏа",
+		"q̇ant":   "кӏант",
+		"jaƶ":     "ӏаж",
+		"ħalxa":   "хӏалха",
+		"khaant":  "къаант",
+		"noxçiyn": "нохчийн",
+		"shura":   "шура",
+		"ägar":    "аьгар",
+		"g1ala":   "г1ала", // the digit is foldPalochka's
+	}
+	for in, want := range cases {
+		got, ok := LatinToCyrillic(in)
+		if !ok || got != want {
+			t.Errorf("LatinToCyrillic(%q) = %q/%v, want %q", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"гӏала", "g1алa", "123", ""} {
+		if got, ok := LatinToCyrillic(in); ok {
+			t.Errorf("LatinToCyrillic(%q) = %q, want not Latin", in, got)
+		}
+	}
+}
+
+func TestTransliterationCandidates(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		// Plain ASCII could be either; the layout swap is the commoner slip.
+		{"ckjdj", []string{"слово", "цкӏдӏ"}},
+		// A Chechen Latin letter rules the swap out.
+		{"ġala", []string{"гӏала"}},
+		// Punctuation keys swap to letters, and a Latin reading that leaves
+		// the punctuation behind is dropped.
+		{";tkfnm", []string{"желать"}},
+		{"гӏала", nil},
+		{"", nil},
+	}
+	for _, c := range cases {
+		if got := TransliterationCandidates(c.in); !slices.Equal(got, c.want) {
+			t.Errorf("TransliterationCandidates(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}